@@ -18,11 +18,13 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 	"github.com/Gskill75/api2/pkg/config"
 	harbordb "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	idempotencydb "github.com/Gskill75/api2/pkg/db/sqlc/idempotency"
 	k8sdb "github.com/Gskill75/api2/pkg/db/sqlc/kubernetes"
 	postgresdb "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
 
 	awxclient "github.com/Gskill75/api2/pkg/awx/client"
 	"github.com/Gskill75/api2/pkg/dbaas"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
 	"github.com/Gskill75/api2/pkg/docs"
 	"github.com/Gskill75/api2/pkg/harbor"
 	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
@@ -82,6 +84,7 @@ func runApp(cmd *cobra.Command, args []string) {
 	postgresQueries := postgresdb.New(pool)
 	k8sQueries := k8sdb.New(pool)
 	harborQueries := harbordb.New(pool)
+	idempotencyQueries := idempotencydb.New(pool)
 
 	dbConn := pool // Pour les health checks
 
@@ -110,16 +113,31 @@ func runApp(cmd *cobra.Command, args []string) {
 		klog.Fatalf("Unable to init Awx client: %v", err)
 	}
 
+	// Secret store des identifiants DBaaS
+	secretStore, err := secret.New(cfg, postgresQueries)
+	if err != nil {
+		klog.Fatalf("Unable to init DBaaS secret store: %v", err)
+	}
+
 	// kubernetes v2 service
 	k8sSolV2, err := kubernetesv2.NewKubernetesSolution(cfg, kubeClient, k8sQueries)
 	cobra.CheckErr(err)
 
+	harborSol := harbor.NewHarborSolution(cfg, harborClient, harborQueries, kubeClient, k8sQueries)
+	dbaasSol := dbaas.NewDbaasSolution(cfg, awxClient, postgresQueries, secretStore)
+
 	ss := []solutions.Solution{
-		harbor.NewHarborSolution(cfg, harborClient, harborQueries),
-		dbaas.NewDbaasSolution(cfg, awxClient, postgresQueries),
+		harborSol,
 		k8sSol,
 		k8sSolV2,
 	}
+	// Solutions qui lancent des jobs AWX, protégées contre les doubles lancements
+	awxSols := []solutions.Solution{dbaasSol}
+	// Moteurs DBaaS génériques déclarés dans dbaas.engines
+	engineSols := dbaas.NewEngineSolutions(cfg, awxClient, postgresQueries, secretStore)
+	for _, engineSol := range engineSols {
+		awxSols = append(awxSols, engineSol)
+	}
 
 	r := gin.Default()
 
@@ -127,7 +145,8 @@ func runApp(cmd *cobra.Command, args []string) {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"}, // minute papillon
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.IdempotencyKeyHeader},
+		ExposeHeaders:    []string{middleware.IdempotentReplayedHeader},
 		AllowCredentials: false,
 	}))
 
@@ -146,10 +165,18 @@ func runApp(cmd *cobra.Command, args []string) {
 		klog.Fatalf("Unable to init OIDC middleware: %v", err)
 	}
 	api.Use(authMiddleware)
+	// Rejeu des requêtes mutantes portant un header Idempotency-Key (double clic, retry après timeout).
+	// Limité aux lancements AWX : les réponses rejouables sont stockées en clair et celles des autres
+	// solutions peuvent contenir des secrets (robots Harbor...)
+	idempotencyTTL := time.Duration(cfg.Server.IdempotencyTTL) * time.Hour
+	idempotency := middleware.Idempotency(idempotencyQueries, idempotencyTTL)
 
 	for _, s := range ss {
 		s.Endpoint(api.Group(fmt.Sprintf("/%s/%s", s.Name(), s.Version())))
 	}
+	for _, s := range awxSols {
+		s.Endpoint(api.Group(fmt.Sprintf("/%s/%s", s.Name(), s.Version()), idempotency))
+	}
 
 	// Health endpoints
 	health.RegisterRoutes(r, &health.Options{
@@ -180,6 +207,15 @@ func runApp(cmd *cobra.Command, args []string) {
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	// Backups et opérations planifiés DBaaS, arrêtés avec le serveur
+	go dbaasSol.RunScheduler(ctx)
+	for _, engineSol := range engineSols {
+		go engineSol.RunScheduler(ctx)
+	}
+	// Renouvellement des imagePullSecrets Harbor avant expiration des robots
+	go harborSol.RunRefresher(ctx)
+	go middleware.PurgeIdempotencyKeys(ctx, idempotencyQueries, idempotencyTTL, time.Hour)
+
 	<-ctx.Done() // attente du signal
 	klog.Info("Shutdown signal received")
 
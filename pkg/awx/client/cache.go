@@ -0,0 +1,59 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// templateCache caches template name to ID lookups for a limited time
+type templateCache struct {
+	mu      sync.RWMutex
+	ttl     time.Duration
+	entries map[string]templateCacheEntry
+}
+
+type templateCacheEntry struct {
+	id        int
+	expiresAt time.Time
+}
+
+// newTemplateCache creates a cache, disabled when ttl is zero
+func newTemplateCache(ttl time.Duration) *templateCache {
+	return &templateCache{
+		ttl:     ttl,
+		entries: map[string]templateCacheEntry{},
+	}
+}
+
+func (c *templateCache) get(name string) (int, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	entry, ok := c.entries[name]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return 0, false
+	}
+	return entry.id, true
+}
+
+func (c *templateCache) set(name string, id int) {
+	if c.ttl <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[name] = templateCacheEntry{id: id, expiresAt: time.Now().Add(c.ttl)}
+}
+
+// invalidateID drops the entries pointing to a template that no longer exists
+func (c *templateCache) invalidateID(id int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, entry := range c.entries {
+		if entry.id == id {
+			delete(c.entries, name)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/Gskill75/api2/pkg/config"
 	"k8s.io/klog/v2"
@@ -12,6 +13,15 @@ import (
 type Client struct {
 	BaseURL   string
 	Requester *Requester
+
+	templateIDs *templateCache
+	timeouts    callTimeouts
+}
+
+// callTimeouts overrides the default timeout for the slow AWX calls
+type callTimeouts struct {
+	launch time.Duration
+	stdout time.Duration
 }
 
 // New initialise un nouveau client AWX en utilisant l'API
@@ -67,14 +77,31 @@ func New(cfg *config.Config) (*Client, error) {
 		cfg.Awx.Token,
 		cfg.Awx.Bearer,
 		cfg.Awx.Insecure,
+		RequesterOptions{
+			Timeout:          seconds(cfg.Awx.Timeouts.Default),
+			MaxRetries:       cfg.Awx.Retry.MaxRetries,
+			RetryBaseDelay:   time.Duration(cfg.Awx.Retry.BaseDelayMs) * time.Millisecond,
+			RetryMaxDelay:    time.Duration(cfg.Awx.Retry.MaxDelayMs) * time.Millisecond,
+			BreakerThreshold: cfg.Awx.CircuitBreaker.FailureThreshold,
+			BreakerCooldown:  seconds(cfg.Awx.CircuitBreaker.OpenSeconds),
+		},
 	)
 
 	return &Client{
-		BaseURL:   cfg.Awx.Url,
-		Requester: requester,
+		BaseURL:     cfg.Awx.Url,
+		Requester:   requester,
+		templateIDs: newTemplateCache(seconds(cfg.Awx.TemplateCacheTTL)),
+		timeouts: callTimeouts{
+			launch: seconds(cfg.Awx.Timeouts.Launch),
+			stdout: seconds(cfg.Awx.Timeouts.Stdout),
+		},
 	}, nil
 }
 
+func seconds(n int) time.Duration {
+	return time.Duration(n) * time.Second
+}
+
 // JobService returns a new JobService
 func (c *Client) JobService() *JobService {
 	return NewJobService(c)
@@ -85,6 +112,16 @@ func (c *Client) JobTemplateService() *JobTemplateService {
 	return NewJobTemplateService(c)
 }
 
+// WorkflowJobService returns a new WorkflowJobService
+func (c *Client) WorkflowJobService() *WorkflowJobService {
+	return NewWorkflowJobService(c)
+}
+
+// WorkflowJobTemplateService returns a new WorkflowJobTemplateService
+func (c *Client) WorkflowJobTemplateService() *WorkflowJobTemplateService {
+	return NewWorkflowJobTemplateService(c)
+}
+
 // TestConnection tests AWX API connectivity
 func (c *Client) TestConnection(ctx context.Context) error {
 	resp, err := c.Requester.MakeRequest(ctx, "GET", "/ping/", nil)
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	apierrors "github.com/Gskill75/api2/pkg/errors"
+)
+
+// Typed AWX errors, matched with errors.Is and mapped to HTTP codes by middleware.ErrorHandler
+var (
+	ErrBadRequest   = apierrors.ErrBadRequest
+	ErrNotFound     = apierrors.ErrNotFound
+	ErrUnauthorized = apierrors.ErrUnauthorized
+	ErrConflict     = apierrors.ErrConflict
+	ErrUnavailable  = apierrors.ErrUnavailable
+)
+
+// ErrCircuitOpen is returned without calling AWX while the circuit breaker is open
+var ErrCircuitOpen = fmt.Errorf("%w: AWX circuit breaker open", ErrUnavailable)
+
+// APIError is returned when AWX answers with an error status
+type APIError struct {
+	StatusCode int
+	Body       string
+
+	retryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("AWX API error (status %d): %s", e.StatusCode, e.Body)
+}
+
+// Unwrap exposes the typed error matching the status code
+func (e *APIError) Unwrap() error {
+	switch {
+	case e.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case e.StatusCode == http.StatusUnauthorized, e.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case e.StatusCode == http.StatusConflict:
+		return ErrConflict
+	case e.StatusCode == http.StatusTooManyRequests, e.StatusCode >= 500:
+		return ErrUnavailable
+	case e.StatusCode >= 400:
+		return ErrBadRequest
+	}
+	return nil
+}
+
+// IsNotFound reports whether err is an AWX 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// unavailableError wraps a transport error (timeout, connection refused...) as ErrUnavailable
+type unavailableError struct {
+	err error
+}
+
+func (e *unavailableError) Error() string {
+	return fmt.Sprintf("AWX unavailable: %v", e.err)
+}
+
+func (e *unavailableError) Unwrap() []error {
+	return []error{ErrUnavailable, e.err}
+}
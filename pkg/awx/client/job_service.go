@@ -45,6 +45,72 @@ func (js *JobService) GetJob(ctx context.Context, jobID int) (*Job, error) {
 	return &job, nil
 }
 
+// GetJobStdout retrieves the plain text output of a job
+func (js *JobService) GetJobStdout(ctx context.Context, jobID int) (string, error) {
+	endpoint := fmt.Sprintf("/jobs/%d/stdout/?format=txt", jobID)
+
+	resp, err := js.client.Requester.MakeRequest(withTimeout(ctx, js.client.timeouts.stdout), "GET", endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to get job stdout: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read job stdout: %w", err)
+	}
+
+	return string(body), nil
+}
+
+// GetJobEvents retrieves all the events of a job, ordered by counter
+func (js *JobService) GetJobEvents(ctx context.Context, jobID int) ([]JobEvent, error) {
+	endpoint := fmt.Sprintf("/jobs/%d/job_events/?order_by=counter&page_size=200", jobID)
+
+	events, err := ListAll[JobEvent](ctx, js.client.Requester, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job events: %w", err)
+	}
+
+	return events, nil
+}
+
+// CancelJob requests the cancellation of a pending or running job
+func (js *JobService) CancelJob(ctx context.Context, jobID int) error {
+	endpoint := fmt.Sprintf("/jobs/%d/cancel/", jobID)
+
+	resp, err := js.client.Requester.MakeRequest(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to cancel job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// RelaunchJob relaunches a finished job with the same parameters and returns the new job
+func (js *JobService) RelaunchJob(ctx context.Context, jobID int) (*Job, error) {
+	endpoint := fmt.Sprintf("/jobs/%d/relaunch/", jobID)
+
+	resp, err := js.client.Requester.MakeRequest(ctx, "POST", endpoint, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to relaunch job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read relaunch response: %w", err)
+	}
+
+	var job Job
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal relaunch response: %w", err)
+	}
+
+	return &job, nil
+}
+
 // GetRunningJobsForTemplate gets running jobs for a specific template
 func (js *JobService) GetRunningJobsForTemplate(ctx context.Context, templateName string) ([]Job, error) {
 	// First get template ID
@@ -56,23 +122,12 @@ func (js *JobService) GetRunningJobsForTemplate(ctx context.Context, templateNam
 	// Get jobs for this template that are running
 	endpoint := fmt.Sprintf("/jobs/?job_template=%d&status__in=pending,waiting,running", templateID)
 
-	resp, err := js.client.Requester.MakeRequest(ctx, "GET", endpoint, nil)
+	jobs, err := ListAll[Job](ctx, js.client.Requester, endpoint)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get running jobs: %w", err)
 	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read jobs response: %w", err)
-	}
-
-	var jobsResp JobsResponse
-	if err := json.Unmarshal(body, &jobsResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal jobs response: %w", err)
-	}
 
-	return jobsResp.Results, nil
+	return jobs, nil
 }
 
 // MonitorJob polls job status until completion
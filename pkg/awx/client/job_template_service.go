@@ -3,8 +3,10 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net/url"
 
 	"k8s.io/klog/v2"
 )
@@ -21,33 +23,39 @@ func NewJobTemplateService(client *Client) *JobTemplateService {
 	}
 }
 
-// GetTemplateIDByName retrieves job template ID by name
-func (jts *JobTemplateService) GetTemplateIDByName(ctx context.Context, jobTemplate string) (int, error) {
-	resp, err := jts.client.Requester.MakeRequest(ctx, "GET", "/job_templates/", nil)
-	if err != nil {
-		klog.Errorf("failed to get job templates: %v", err)
-		return 0, fmt.Errorf("failed to get job templates: %w", err)
-	}
-	defer resp.Body.Close()
+// ErrTemplateNotFound is returned when no template has the requested name
+var ErrTemplateNotFound = errors.New("template_name_not_found")
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return 0, fmt.Errorf("failed to read response body: %w", err)
-	}
-
-	var templatesResp JobTemplatesResponse
-	if err := json.Unmarshal(body, &templatesResp); err != nil {
-		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+// GetTemplateIDByName retrieves job template ID by name, using the client cache
+func (jts *JobTemplateService) GetTemplateIDByName(ctx context.Context, jobTemplate string) (int, error) {
+	key := templateCacheKey(TemplateKindJob, jobTemplate)
+	if id, ok := jts.client.templateIDs.get(key); ok {
+		return id, nil
 	}
 
-	for _, template := range templatesResp.Results {
+	// Server-side filter on the exact name, whatever the page
+	endpoint := "/job_templates/?name=" + url.QueryEscape(jobTemplate)
+	for template, err := range Paginate[JobTemplate](ctx, jts.client.Requester, endpoint) {
+		if err != nil {
+			klog.Errorf("failed to get job templates: %v", err)
+			return 0, fmt.Errorf("failed to get job templates: %w", err)
+		}
 		if template.Name == jobTemplate {
+			jts.client.templateIDs.set(key, template.ID)
 			return template.ID, nil
 		}
 	}
 
 	klog.Errorf("template name '%s' not found", jobTemplate)
-	return 0, fmt.Errorf("template_name_not_found")
+	return 0, ErrTemplateNotFound
+}
+
+// invalidateOnNotFound drops the cached name of a template deleted in AWX
+func (jts *JobTemplateService) invalidateOnNotFound(templateID int, err error) {
+	if IsNotFound(err) {
+		klog.Warningf("job template %d not found, invalidating cache", templateID)
+		jts.client.templateIDs.invalidateID(templateID)
+	}
 }
 
 // LaunchJob launches a job template with optional extra variables
@@ -58,8 +66,9 @@ func (jts *JobTemplateService) LaunchJob(ctx context.Context, templateID int, ex
 		ExtraVars: extraVars,
 	}
 
-	resp, err := jts.client.Requester.MakeRequest(ctx, "POST", endpoint, launchReq)
+	resp, err := jts.client.Requester.MakeRequest(withTimeout(ctx, jts.client.timeouts.launch), "POST", endpoint, launchReq)
 	if err != nil {
+		jts.invalidateOnNotFound(templateID, err)
 		return nil, fmt.Errorf("failed to launch job: %w", err)
 	}
 	defer resp.Body.Close()
@@ -76,3 +85,27 @@ func (jts *JobTemplateService) LaunchJob(ctx context.Context, templateID int, ex
 
 	return &launchResp, nil
 }
+
+// GetSurveySpec retrieves the survey spec of a job template (empty spec if no survey is defined)
+func (jts *JobTemplateService) GetSurveySpec(ctx context.Context, templateID int) (*SurveySpec, error) {
+	endpoint := fmt.Sprintf("/job_templates/%d/survey_spec/", templateID)
+
+	resp, err := jts.client.Requester.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		jts.invalidateOnNotFound(templateID, err)
+		return nil, fmt.Errorf("failed to get survey spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read survey spec response: %w", err)
+	}
+
+	var spec SurveySpec
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal survey spec response: %w", err)
+	}
+
+	return &spec, nil
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+)
+
+// Page is a page of an AWX list endpoint
+type Page[T any] struct {
+	Count    int    `json:"count"`
+	Next     string `json:"next"`
+	Previous string `json:"previous"`
+	Results  []T    `json:"results"`
+}
+
+// Paginate iterates over all the items of an AWX list endpoint, following the next links.
+// Iteration stops at the first error, yielded with a zero item.
+func Paginate[T any](ctx context.Context, r *Requester, endpoint string) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		for endpoint != "" {
+			page, err := getPage[T](ctx, r, endpoint)
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range page.Results {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			// next is a path including /api/v2, which MakeRequest already prepends
+			endpoint = strings.TrimPrefix(page.Next, "/api/v2")
+		}
+	}
+}
+
+// ListAll returns all the items of an AWX list endpoint
+func ListAll[T any](ctx context.Context, r *Requester, endpoint string) ([]T, error) {
+	var items []T
+	for item, err := range Paginate[T](ctx, r, endpoint) {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+func getPage[T any](ctx context.Context, r *Requester, endpoint string) (*Page[T], error) {
+	resp, err := r.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var page Page[T]
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page: %w", err)
+	}
+	return &page, nil
+}
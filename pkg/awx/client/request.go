@@ -5,15 +5,30 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"k8s.io/klog/v2"
 )
 
+// RequesterOptions configures timeouts, retries and circuit breaker of the requester
+type RequesterOptions struct {
+	// Timeout applies to each attempt, unless overridden for a call with withTimeout
+	Timeout        time.Duration
+	MaxRetries     int
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// BreakerThreshold consecutive failures open the circuit for BreakerCooldown (0 disables it)
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
 // Requester handles HTTP requests to AWX API
 type Requester struct {
 	httpClient *http.Client
@@ -22,10 +37,12 @@ type Requester struct {
 	password   string
 	token      string
 	bearer     string
+	opts       RequesterOptions
+	breaker    *circuitBreaker
 }
 
 // NewRequester creates a new HTTP requester for AWX API
-func NewRequester(baseURL, username, password, token, bearer string, insecure bool) *Requester {
+func NewRequester(baseURL, username, password, token, bearer string, insecure bool, opts RequesterOptions) *Requester {
 	return &Requester{
 		httpClient: newHTTPClient(insecure),
 		baseURL:    baseURL,
@@ -33,7 +50,19 @@ func NewRequester(baseURL, username, password, token, bearer string, insecure bo
 		password:   password,
 		token:      token,
 		bearer:     bearer,
+		opts:       opts,
+		breaker:    newCircuitBreaker(opts.BreakerThreshold, opts.BreakerCooldown),
+	}
+}
+
+type timeoutKey struct{}
+
+// withTimeout overrides the per-attempt timeout of the requests made with ctx
+func withTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	if timeout <= 0 {
+		return ctx
 	}
+	return context.WithValue(ctx, timeoutKey{}, timeout)
 }
 
 // setCredentials sets the appropriate authentication headers based on available credentials
@@ -50,7 +79,8 @@ func (r *Requester) setCredentials(req *http.Request) {
 	}
 }
 
-// MakeRequest performs HTTP request to AWX API
+// MakeRequest performs HTTP request to AWX API.
+// GET requests are retried on network errors, 5xx and 429; other methods only on 429.
 func (r *Requester) MakeRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
 	var url string
 
@@ -59,19 +89,65 @@ func (r *Requester) MakeRequest(ctx context.Context, method, endpoint string, bo
 		url = fmt.Sprintf("%s/api/v2%s", r.baseURL, endpoint)
 	}
 
-	var reqBody io.Reader
+	var jsonBody []byte
 	if body != nil {
-		jsonBody, err := json.Marshal(body)
+		var err error
+		jsonBody, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonBody)
 	}
 
-	klog.Infof("Making AWX API request: %s %s", method, url)
+	for attempt := 0; ; attempt++ {
+		if err := r.breaker.allow(); err != nil {
+			return nil, err
+		}
+
+		klog.Infof("Making AWX API request: %s %s", method, url)
+		resp, err := r.do(ctx, method, url, jsonBody)
+		if ctx.Err() != nil {
+			// Canceled by the caller: neither a success nor an AWX failure
+			r.breaker.release()
+		} else {
+			r.breaker.record(isServerFailure(err))
+		}
+		if err == nil {
+			return resp, nil
+		}
+
+		if attempt >= r.opts.MaxRetries || ctx.Err() != nil || !shouldRetry(method, err) {
+			return nil, err
+		}
+
+		delay := r.backoff(attempt, err)
+		klog.Warningf("AWX request %s %s failed (attempt %d/%d), retrying in %s: %v", method, url, attempt+1, r.opts.MaxRetries+1, delay, err)
+		select {
+		case <-ctx.Done():
+			return nil, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// do performs a single attempt, bounded by the per-call timeout
+func (r *Requester) do(ctx context.Context, method, url string, jsonBody []byte) (*http.Response, error) {
+	timeout := r.opts.Timeout
+	if t, ok := ctx.Value(timeoutKey{}).(time.Duration); ok {
+		timeout = t
+	}
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	var reqBody io.Reader
+	if jsonBody != nil {
+		reqBody = bytes.NewReader(jsonBody)
+	}
 
 	req, err := http.NewRequestWithContext(ctx, method, url, reqBody)
 	if err != nil {
+		cancel()
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
@@ -82,19 +158,94 @@ func (r *Requester) MakeRequest(ctx context.Context, method, endpoint string, bo
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		cancel()
+		return nil, &unavailableError{err: err}
 	}
 
 	if resp.StatusCode >= 400 {
 		body, _ := io.ReadAll(resp.Body)
 		resp.Body.Close()
-		return nil, fmt.Errorf("AWX API error (status %d): %s", resp.StatusCode, string(body))
+		cancel()
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(body), retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"))}
 	}
 
+	// The timeout must cover the reading of the body, released on Close
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return resp, nil
 }
 
-// newHTTPClient creates a new HTTP client with TLS configuration
+// shouldRetry tells whether a failed attempt can be replayed without side effects
+func shouldRetry(method string, err error) bool {
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		if apiErr.StatusCode == http.StatusTooManyRequests {
+			return true
+		}
+		return isIdempotent(method) && apiErr.StatusCode >= 500
+	}
+
+	var unavailable *unavailableError
+	return errors.As(err, &unavailable) && isIdempotent(method)
+}
+
+// isServerFailure tells whether the error counts as an AWX failure for the circuit breaker
+func isServerFailure(err error) bool {
+	if err == nil {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500
+	}
+	var unavailable *unavailableError
+	return errors.As(err, &unavailable)
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
+
+// backoff returns an exponential delay with jitter, or the Retry-After delay sent by AWX
+func (r *Requester) backoff(attempt int, err error) time.Duration {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && apiErr.retryAfter > 0 {
+		return min(apiErr.retryAfter, r.opts.RetryMaxDelay)
+	}
+
+	delay := r.opts.RetryBaseDelay << attempt
+	if delay <= 0 || delay > r.opts.RetryMaxDelay {
+		delay = r.opts.RetryMaxDelay
+	}
+	// Full jitter between delay/2 and delay
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+func parseRetryAfter(value string) time.Duration {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// newHTTPClient creates a new HTTP client with TLS configuration.
+// Timeouts are applied per attempt by the requester.
 func newHTTPClient(insecure bool) *http.Client {
 	transport := &http.Transport{
 		TLSClientConfig: &tls.Config{
@@ -104,6 +255,5 @@ func newHTTPClient(insecure bool) *http.Client {
 
 	return &http.Client{
 		Transport: transport,
-		Timeout:   30 * time.Second,
 	}
 }
@@ -0,0 +1,174 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/klog/v2"
+)
+
+// TemplateKind is the type of an AWX template, as reported by AWX (unified_job_type)
+type TemplateKind string
+
+const (
+	TemplateKindJob      TemplateKind = "job_template"
+	TemplateKindWorkflow TemplateKind = "workflow_job_template"
+)
+
+// JobKind is the type of a job launched from a template
+type JobKind string
+
+const (
+	JobKindJob      JobKind = "job"
+	JobKindWorkflow JobKind = "workflow_job"
+)
+
+// JobKind returns the kind of the jobs launched by the template
+func (k TemplateKind) JobKind() JobKind {
+	if k == TemplateKindWorkflow {
+		return JobKindWorkflow
+	}
+	return JobKindJob
+}
+
+// TemplateRef identifies a job template or a workflow job template
+type TemplateRef struct {
+	Kind TemplateKind
+	ID   int
+	Name string
+}
+
+func templateCacheKey(kind TemplateKind, name string) string {
+	if kind == TemplateKindJob {
+		return name
+	}
+	return string(kind) + "/" + name
+}
+
+// ResolveTemplate finds a template by name among job templates, then workflow job templates,
+// so that callers do not need to know how the automation is implemented in AWX
+func (c *Client) ResolveTemplate(ctx context.Context, name string) (*TemplateRef, error) {
+	id, err := c.JobTemplateService().GetTemplateIDByName(ctx, name)
+	if err == nil {
+		return &TemplateRef{Kind: TemplateKindJob, ID: id, Name: name}, nil
+	}
+	if !errors.Is(err, ErrTemplateNotFound) {
+		return nil, err
+	}
+
+	id, err = c.WorkflowJobTemplateService().GetTemplateIDByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return &TemplateRef{Kind: TemplateKindWorkflow, ID: id, Name: name}, nil
+}
+
+// GetSurveySpec retrieves the survey spec of the template, whatever its kind
+func (c *Client) GetSurveySpec(ctx context.Context, ref *TemplateRef) (*SurveySpec, error) {
+	if ref.Kind == TemplateKindWorkflow {
+		return c.WorkflowJobTemplateService().GetSurveySpec(ctx, ref.ID)
+	}
+	return c.JobTemplateService().GetSurveySpec(ctx, ref.ID)
+}
+
+// Launch launches the template and returns the ID of the job or workflow job created
+func (c *Client) Launch(ctx context.Context, ref *TemplateRef, extraVars map[string]any) (int, error) {
+	if ref.Kind == TemplateKindWorkflow {
+		resp, err := c.WorkflowJobTemplateService().LaunchWorkflow(ctx, ref.ID, extraVars)
+		if err != nil {
+			return 0, err
+		}
+		return resp.WorkflowJob, nil
+	}
+
+	resp, err := c.JobTemplateService().LaunchJob(ctx, ref.ID, extraVars)
+	if err != nil {
+		return 0, err
+	}
+	return resp.Job, nil
+}
+
+// GetUnifiedJob retrieves a job or a workflow job. The status of a workflow job is reported as a job status,
+// without artifacts.
+func (c *Client) GetUnifiedJob(ctx context.Context, kind JobKind, jobID int) (*Job, error) {
+	if kind == JobKindWorkflow {
+		wj, err := c.WorkflowJobService().GetWorkflowJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		return wj.asJob(), nil
+	}
+	return c.JobService().GetJob(ctx, jobID)
+}
+
+// MonitorUnifiedJob polls a job or a workflow job until completion.
+// For a workflow, the returned job carries the artifacts published by its nodes.
+func (c *Client) MonitorUnifiedJob(ctx context.Context, kind JobKind, jobID int) (*Job, error) {
+	if kind != JobKindWorkflow {
+		return c.JobService().MonitorJob(ctx, jobID)
+	}
+
+	wj, monitorErr := c.WorkflowJobService().MonitorWorkflowJob(ctx, jobID)
+	if wj == nil {
+		return nil, monitorErr
+	}
+	job := wj.asJob()
+	artifacts, err := c.WorkflowJobService().CollectArtifacts(ctx, jobID)
+	if err != nil {
+		// The workflow outcome does not depend on its artifacts: a successful workflow stays successful
+		klog.Warningf("Failed to collect artifacts of workflow job %d: %v", jobID, err)
+		return job, monitorErr
+	}
+	job.Artifacts = artifacts
+	return job, monitorErr
+}
+
+// CancelUnifiedJob requests the cancellation of a job or a workflow job
+func (c *Client) CancelUnifiedJob(ctx context.Context, kind JobKind, jobID int) error {
+	if kind == JobKindWorkflow {
+		return c.WorkflowJobService().CancelWorkflowJob(ctx, jobID)
+	}
+	return c.JobService().CancelJob(ctx, jobID)
+}
+
+// RelaunchUnifiedJob relaunches a finished job or workflow job and returns the new one
+func (c *Client) RelaunchUnifiedJob(ctx context.Context, kind JobKind, jobID int) (*Job, error) {
+	if kind == JobKindWorkflow {
+		wj, err := c.WorkflowJobService().RelaunchWorkflowJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		return wj.asJob(), nil
+	}
+	return c.JobService().RelaunchJob(ctx, jobID)
+}
+
+// GetRunningJobsForTemplate gets the pending or running jobs of a job template or workflow job template
+func (c *Client) GetRunningJobsForTemplate(ctx context.Context, templateName string) ([]Job, error) {
+	ref, err := c.ResolveTemplate(ctx, templateName)
+	if err != nil {
+		return nil, err
+	}
+	if ref.Kind != TemplateKindWorkflow {
+		return c.JobService().GetRunningJobsForTemplate(ctx, templateName)
+	}
+
+	workflowJobs, err := c.WorkflowJobService().GetRunningWorkflowJobsForTemplate(ctx, ref.ID)
+	if err != nil {
+		return nil, err
+	}
+	jobs := make([]Job, 0, len(workflowJobs))
+	for _, wj := range workflowJobs {
+		jobs = append(jobs, *wj.asJob())
+	}
+	return jobs, nil
+}
+
+func (wj *WorkflowJob) asJob() *Job {
+	return &Job{
+		ID:     wj.ID,
+		Name:   wj.Name,
+		Status: wj.Status,
+		URL:    wj.URL,
+	}
+}
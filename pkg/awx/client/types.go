@@ -1,5 +1,7 @@
 package client
 
+import "strings"
+
 // AWX API response structures
 type JobTemplate struct {
 	ID   int    `json:"id"`
@@ -7,21 +9,95 @@ type JobTemplate struct {
 	URL  string `json:"url"`
 }
 
-type JobTemplatesResponse struct {
-	Count   int           `json:"count"`
-	Results []JobTemplate `json:"results"`
-}
-
 type Job struct {
 	ID     int    `json:"id"`
 	Name   string `json:"name"`
 	Status string `json:"status"`
 	URL    string `json:"url"`
+	// Artifacts contains the values published by the playbook with set_stats
+	Artifacts map[string]interface{} `json:"artifacts,omitempty"`
+}
+
+// JobEvent is an event emitted by a running job (/jobs/:id/job_events/)
+type JobEvent struct {
+	ID        int                    `json:"id"`
+	Event     string                 `json:"event"`
+	Counter   int                    `json:"counter"`
+	Play      string                 `json:"play"`
+	Task      string                 `json:"task"`
+	Host      string                 `json:"host_name"`
+	Failed    bool                   `json:"failed"`
+	Changed   bool                   `json:"changed"`
+	Created   string                 `json:"created"`
+	EventData map[string]interface{} `json:"event_data"`
+}
+
+// WorkflowJobTemplate is a workflow chaining several templates (/workflow_job_templates/)
+type WorkflowJobTemplate struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// WorkflowJob is a run of a workflow job template (/workflow_jobs/:id/)
+type WorkflowJob struct {
+	ID                  int    `json:"id"`
+	Name                string `json:"name"`
+	Status              string `json:"status"`
+	URL                 string `json:"url"`
+	Failed              bool   `json:"failed"`
+	WorkflowJobTemplate int    `json:"workflow_job_template"`
+}
+
+// WorkflowLaunchResponse is returned by /workflow_job_templates/:id/launch/
+type WorkflowLaunchResponse struct {
+	WorkflowJob   int                    `json:"workflow_job"`
+	IgnoredFields map[string]interface{} `json:"ignored_fields,omitempty"`
+	ID            int                    `json:"id"`
+	Type          string                 `json:"type"`
+	URL           string                 `json:"url"`
+}
+
+// WorkflowNode is a node of a workflow job, with the job it spawned if any (/workflow_jobs/:id/workflow_nodes/)
+type WorkflowNode struct {
+	ID            int    `json:"id"`
+	Identifier    string `json:"identifier"`
+	Job           *int   `json:"job"`
+	DoNotRun      bool   `json:"do_not_run"`
+	SummaryFields struct {
+		Job *struct {
+			ID     int    `json:"id"`
+			Name   string `json:"name"`
+			Status string `json:"status"`
+			Type   string `json:"type"`
+		} `json:"job,omitempty"`
+		UnifiedJobTemplate *struct {
+			ID             int    `json:"id"`
+			Name           string `json:"name"`
+			UnifiedJobType string `json:"unified_job_type"`
+		} `json:"unified_job_template,omitempty"`
+	} `json:"summary_fields"`
+}
+
+// Name returns the name of the template run by the node
+func (n WorkflowNode) Name() string {
+	if t := n.SummaryFields.UnifiedJobTemplate; t != nil {
+		return t.Name
+	}
+	return n.Identifier
 }
 
-type JobsResponse struct {
-	Count   int   `json:"count"`
-	Results []Job `json:"results"`
+// Status returns the status of the job spawned by the node, "never_run" if the node was skipped
+// and "pending" if it has not started yet
+func (n WorkflowNode) Status() string {
+	switch {
+	case n.SummaryFields.Job != nil:
+		return n.SummaryFields.Job.Status
+	case n.DoNotRun:
+		return "never_run"
+	default:
+		return "pending"
+	}
 }
 
 type JobLaunchRequest struct {
@@ -35,3 +111,43 @@ type JobLaunchResponse struct {
 	Type          string                 `json:"type"`
 	URL           string                 `json:"url"`
 }
+
+// SurveySpec is the survey attached to a job template (/job_templates/:id/survey_spec/)
+type SurveySpec struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description"`
+	Spec        []SurveyQuestion `json:"spec"`
+}
+
+// SurveyQuestion describes one extra variable expected by a job template
+type SurveyQuestion struct {
+	QuestionName string      `json:"question_name"`
+	Variable     string      `json:"variable"`
+	Type         string      `json:"type"`
+	Required     bool        `json:"required"`
+	Min          *float64    `json:"min,omitempty"`
+	Max          *float64    `json:"max,omitempty"`
+	Default      interface{} `json:"default,omitempty"`
+	// Choices est une string séparée par des \n sur les anciennes versions d'AWX, un tableau sur les récentes
+	Choices interface{} `json:"choices,omitempty"`
+}
+
+// ChoiceList returns the allowed values of a multiplechoice/multiselect question
+func (q SurveyQuestion) ChoiceList() []string {
+	var choices []string
+	switch v := q.Choices.(type) {
+	case string:
+		for _, c := range strings.Split(v, "\n") {
+			if c = strings.TrimSpace(c); c != "" {
+				choices = append(choices, c)
+			}
+		}
+	case []interface{}:
+		for _, c := range v {
+			if s, ok := c.(string); ok {
+				choices = append(choices, s)
+			}
+		}
+	}
+	return choices
+}
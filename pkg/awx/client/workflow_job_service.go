@@ -0,0 +1,164 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// WorkflowJobService handles workflow job operations
+type WorkflowJobService struct {
+	client *Client
+}
+
+// NewWorkflowJobService creates a new workflow job service
+func NewWorkflowJobService(client *Client) *WorkflowJobService {
+	return &WorkflowJobService{
+		client: client,
+	}
+}
+
+// GetWorkflowJob retrieves workflow job status by ID
+func (ws *WorkflowJobService) GetWorkflowJob(ctx context.Context, workflowJobID int) (*WorkflowJob, error) {
+	endpoint := fmt.Sprintf("/workflow_jobs/%d/", workflowJobID)
+
+	resp, err := ws.client.Requester.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow job status: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow job response: %w", err)
+	}
+
+	var job WorkflowJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow job response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// ListWorkflowNodes retrieves the nodes of a workflow job with the status of the job each one spawned
+func (ws *WorkflowJobService) ListWorkflowNodes(ctx context.Context, workflowJobID int) ([]WorkflowNode, error) {
+	endpoint := fmt.Sprintf("/workflow_jobs/%d/workflow_nodes/?order_by=id", workflowJobID)
+
+	nodes, err := ListAll[WorkflowNode](ctx, ws.client.Requester, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow nodes: %w", err)
+	}
+
+	return nodes, nil
+}
+
+// CancelWorkflowJob requests the cancellation of a pending or running workflow job and of its running nodes
+func (ws *WorkflowJobService) CancelWorkflowJob(ctx context.Context, workflowJobID int) error {
+	endpoint := fmt.Sprintf("/workflow_jobs/%d/cancel/", workflowJobID)
+
+	resp, err := ws.client.Requester.MakeRequest(ctx, "POST", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to cancel workflow job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
+
+// RelaunchWorkflowJob relaunches a finished workflow job with the same parameters and returns the new workflow job
+func (ws *WorkflowJobService) RelaunchWorkflowJob(ctx context.Context, workflowJobID int) (*WorkflowJob, error) {
+	endpoint := fmt.Sprintf("/workflow_jobs/%d/relaunch/", workflowJobID)
+
+	resp, err := ws.client.Requester.MakeRequest(ctx, "POST", endpoint, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to relaunch workflow job: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow relaunch response: %w", err)
+	}
+
+	var job WorkflowJob
+	if err := json.Unmarshal(body, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow relaunch response: %w", err)
+	}
+
+	return &job, nil
+}
+
+// GetRunningWorkflowJobsForTemplate gets running workflow jobs for a specific workflow template
+func (ws *WorkflowJobService) GetRunningWorkflowJobsForTemplate(ctx context.Context, templateID int) ([]WorkflowJob, error) {
+	endpoint := fmt.Sprintf("/workflow_jobs/?workflow_job_template=%d&status__in=pending,waiting,running", templateID)
+
+	jobs, err := ListAll[WorkflowJob](ctx, ws.client.Requester, endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running workflow jobs: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// MonitorWorkflowJob polls workflow job status until completion
+func (ws *WorkflowJobService) MonitorWorkflowJob(ctx context.Context, workflowJobID int) (*WorkflowJob, error) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			job, err := ws.GetWorkflowJob(ctx, workflowJobID)
+			if err != nil {
+				return nil, err
+			}
+
+			klog.Infof("Workflow job %d status: %s", workflowJobID, job.Status)
+
+			switch job.Status {
+			case "successful":
+				return job, nil
+			case "failed", "error", "canceled":
+				return job, fmt.Errorf("workflow job failed with status: %s", job.Status)
+			}
+		}
+	}
+}
+
+// CollectArtifacts merges the artifacts published by the jobs of the workflow, later nodes overriding earlier ones
+func (ws *WorkflowJobService) CollectArtifacts(ctx context.Context, workflowJobID int) (map[string]interface{}, error) {
+	nodes, err := ws.ListWorkflowNodes(ctx, workflowJobID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Jobs are created in execution order
+	var jobIDs []int
+	for _, node := range nodes {
+		if node.SummaryFields.Job != nil && node.SummaryFields.Job.Type == string(JobKindJob) {
+			jobIDs = append(jobIDs, node.SummaryFields.Job.ID)
+		}
+	}
+	slices.Sort(jobIDs)
+
+	artifacts := map[string]interface{}{}
+	for _, jobID := range jobIDs {
+		job, err := ws.client.JobService().GetJob(ctx, jobID)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range job.Artifacts {
+			artifacts[k] = v
+		}
+	}
+
+	return artifacts, nil
+}
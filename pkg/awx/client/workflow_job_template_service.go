@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+
+	"k8s.io/klog/v2"
+)
+
+// WorkflowJobTemplateService handles workflow job template operations
+type WorkflowJobTemplateService struct {
+	client *Client
+}
+
+// NewWorkflowJobTemplateService creates a new workflow job template service
+func NewWorkflowJobTemplateService(client *Client) *WorkflowJobTemplateService {
+	return &WorkflowJobTemplateService{
+		client: client,
+	}
+}
+
+// GetTemplateIDByName retrieves workflow job template ID by name, using the client cache
+func (wts *WorkflowJobTemplateService) GetTemplateIDByName(ctx context.Context, workflowTemplate string) (int, error) {
+	key := templateCacheKey(TemplateKindWorkflow, workflowTemplate)
+	if id, ok := wts.client.templateIDs.get(key); ok {
+		return id, nil
+	}
+
+	endpoint := "/workflow_job_templates/?name=" + url.QueryEscape(workflowTemplate)
+	for template, err := range Paginate[WorkflowJobTemplate](ctx, wts.client.Requester, endpoint) {
+		if err != nil {
+			return 0, fmt.Errorf("failed to get workflow job templates: %w", err)
+		}
+		if template.Name == workflowTemplate {
+			wts.client.templateIDs.set(key, template.ID)
+			return template.ID, nil
+		}
+	}
+
+	return 0, ErrTemplateNotFound
+}
+
+// invalidateOnNotFound drops the cached name of a workflow template deleted in AWX
+func (wts *WorkflowJobTemplateService) invalidateOnNotFound(templateID int, err error) {
+	if IsNotFound(err) {
+		klog.Warningf("workflow job template %d not found, invalidating cache", templateID)
+		wts.client.templateIDs.invalidateID(templateID)
+	}
+}
+
+// LaunchWorkflow launches a workflow job template with optional extra variables
+func (wts *WorkflowJobTemplateService) LaunchWorkflow(ctx context.Context, templateID int, extraVars map[string]any) (*WorkflowLaunchResponse, error) {
+	endpoint := fmt.Sprintf("/workflow_job_templates/%d/launch/", templateID)
+
+	launchReq := JobLaunchRequest{
+		ExtraVars: extraVars,
+	}
+
+	resp, err := wts.client.Requester.MakeRequest(withTimeout(ctx, wts.client.timeouts.launch), "POST", endpoint, launchReq)
+	if err != nil {
+		wts.invalidateOnNotFound(templateID, err)
+		return nil, fmt.Errorf("failed to launch workflow: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow launch response: %w", err)
+	}
+
+	var launchResp WorkflowLaunchResponse
+	if err := json.Unmarshal(body, &launchResp); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow launch response: %w", err)
+	}
+
+	return &launchResp, nil
+}
+
+// GetSurveySpec retrieves the survey spec of a workflow job template (empty spec if no survey is defined)
+func (wts *WorkflowJobTemplateService) GetSurveySpec(ctx context.Context, templateID int) (*SurveySpec, error) {
+	endpoint := fmt.Sprintf("/workflow_job_templates/%d/survey_spec/", templateID)
+
+	resp, err := wts.client.Requester.MakeRequest(ctx, "GET", endpoint, nil)
+	if err != nil {
+		wts.invalidateOnNotFound(templateID, err)
+		return nil, fmt.Errorf("failed to get workflow survey spec: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workflow survey spec response: %w", err)
+	}
+
+	var spec SurveySpec
+	if err := json.Unmarshal(body, &spec); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal workflow survey spec response: %w", err)
+	}
+
+	return &spec, nil
+}
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"encoding/base64"
+	"fmt"
 	"os"
 	"strings"
 
@@ -12,6 +14,8 @@ import (
 type Config struct {
 	Server struct {
 		Port string `mapstructure:"port"`
+		// Durée (heures) de conservation des réponses rejouables via Idempotency-Key
+		IdempotencyTTL int `mapstructure:"idempotency_ttl"`
 	} `mapstructure:"server"`
 
 	DB struct {
@@ -37,6 +41,25 @@ type Config struct {
 		Token    string `mapstructure:"token"`
 		Username string `mapstructure:"username"`
 		Insecure bool   `mapstructure:"insecure"`
+		// Durée de validité des robots en jours : par défaut et maximum autorisé
+		RobotDefaultDurationDays int64 `mapstructure:"robot_default_duration_days"`
+		RobotMaxDurationDays     int64 `mapstructure:"robot_max_duration_days"`
+		// Plans de stockage des projets ; default_plan s'applique aux clients sans plan attribué
+		DefaultPlan string                `mapstructure:"default_plan"`
+		Plans       map[string]HarborPlan `mapstructure:"plans"`
+		// Politiques appliquées aux projets à leur création
+		DefaultRetention     HarborRetentionPolicy `mapstructure:"default_retention"`
+		DefaultImmutableTags []string              `mapstructure:"default_immutable_tags"`
+		// Secrets de pull synchronisés dans les namespaces : validité des robots et renouvellement
+		// avant expiration (jours), intervalle de vérification (secondes)
+		PullSecretRobotDurationDays int64 `mapstructure:"pull_secret_robot_duration_days"`
+		PullSecretRefreshBeforeDays int64 `mapstructure:"pull_secret_refresh_before_days"`
+		PullSecretRefreshInterval   int   `mapstructure:"pull_secret_refresh_interval"`
+		// Registres amont (noms des endpoints configurés dans Harbor) autorisés pour les projets
+		// proxy-cache et les règles de réplication en pull
+		UpstreamRegistries []string `mapstructure:"upstream_registries"`
+		// Intervalle minimal (minutes) entre deux exécutions planifiées d'une règle de réplication
+		ReplicationMinIntervalMinutes int64 `mapstructure:"replication_min_interval_minutes"`
 	} `mapstructure:"harbor"`
 
 	Awx struct {
@@ -46,8 +69,59 @@ type Config struct {
 		Insecure bool   `mapstructure:"insecure"`
 		Token    string `mapstructure:"token"`
 		Bearer   string `mapstructure:"bearer"`
+		// Durée (secondes) du cache nom de template -> ID, 0 pour désactiver
+		TemplateCacheTTL int `mapstructure:"template_cache_ttl"`
+		// Délais (secondes) par tentative : default pour tous les appels, surchargé pour les appels longs
+		Timeouts struct {
+			Default int `mapstructure:"default"`
+			Launch  int `mapstructure:"launch"`
+			Stdout  int `mapstructure:"stdout"`
+		} `mapstructure:"timeouts"`
+		// Nouvelles tentatives des GET et des réponses 429/5xx, délai exponentiel avec jitter
+		Retry struct {
+			MaxRetries  int `mapstructure:"max_retries"`
+			BaseDelayMs int `mapstructure:"base_delay_ms"`
+			MaxDelayMs  int `mapstructure:"max_delay_ms"`
+		} `mapstructure:"retry"`
+		// Coupe les appels pendant open_seconds après failure_threshold échecs consécutifs (0 pour désactiver)
+		CircuitBreaker struct {
+			FailureThreshold int `mapstructure:"failure_threshold"`
+			OpenSeconds      int `mapstructure:"open_seconds"`
+		} `mapstructure:"circuit_breaker"`
 	} `mapstructure:"awx"`
 
+	Dbaas struct {
+		// Templates AWX des opérations DBaaS
+		Templates struct {
+			// Templates de création proposés aux clients (template_name), le premier par défaut
+			Provision         []string `mapstructure:"provision"`
+			RotateCredentials string   `mapstructure:"rotate_credentials"`
+			Backup            string   `mapstructure:"backup"`
+			Restore           string   `mapstructure:"restore"`
+			Clone             string   `mapstructure:"clone"`
+			Resize            string   `mapstructure:"resize"`   // CPU, mémoire et disque
+			Replicas          string   `mapstructure:"replicas"` // nombre de réplicas Patroni
+			Upgrade           string   `mapstructure:"upgrade"`  // montée de version majeure
+		} `mapstructure:"templates"`
+		// Versions majeures PostgreSQL proposées, la première est la version par défaut des nouvelles instances
+		PostgresVersions []string `mapstructure:"postgres_versions"`
+		// Templates disruptifs (redémarrage, montée de version...), lancés uniquement dans la fenêtre de maintenance de l'instance
+		DisruptiveTemplates []string `mapstructure:"disruptive_templates"`
+		SecretStore         struct {
+			Type          string `mapstructure:"type"`           // "postgres" (défaut) ou "file"
+			EncryptionKey string `mapstructure:"encryption_key"` // clé AES-256 encodée en base64
+			Dir           string `mapstructure:"dir"`            // répertoire du store "file"
+		} `mapstructure:"secret_store"`
+		// Intervalle (secondes) entre deux passages du scheduler des backups planifiés
+		SchedulerInterval int `mapstructure:"scheduler_interval"`
+		// Clé HMAC (base64) des jetons de confirmation des opérations destructives
+		ConfirmationKey string `mapstructure:"confirmation_key"`
+		// Moteurs génériques (mysql, redis, mongodb...), chacun exposé comme une solution /<name>/v1
+		Engines []DbaasEngine `mapstructure:"engines"`
+		// Quotas par défaut des clients, surchargés par client dans dbaas_quotas
+		Quotas DbaasQuotas `mapstructure:"quotas"`
+	} `mapstructure:"dbaas"`
+
 	OIDC struct {
 		Issuer   string `mapstructure:"issuer"`
 		Audience string `mapstructure:"audience"`
@@ -61,6 +135,52 @@ type Config struct {
 	} `mapstructure:"awx"`
 }
 
+// DbaasQuotas limites par client, 0 pour illimité
+type DbaasQuotas struct {
+	MaxInstances      int32 `mapstructure:"max_instances"`
+	MaxStorageGB      int32 `mapstructure:"max_storage_gb"`
+	MaxConcurrentJobs int32 `mapstructure:"max_concurrent_jobs"`
+	// Stockage attribué à une instance créée sans storage_gb
+	DefaultStorageGB int32 `mapstructure:"default_storage_gb"`
+}
+
+// DbaasEngine moteur DBaaS piloté par AWX, ses instances sont enregistrées avec db_type = Name
+type DbaasEngine struct {
+	Name    string `mapstructure:"name"`
+	Version string `mapstructure:"version"` // version de l'API, v1 par défaut
+	// Templates (job ou workflow) AWX du moteur
+	Templates struct {
+		Provision string `mapstructure:"provision"`
+		Delete    string `mapstructure:"delete"`
+	} `mapstructure:"templates"`
+	// Versions du moteur proposées aux clients, la première par défaut (libre si vide)
+	Versions []string `mapstructure:"versions"`
+	// Noms d'utilisateur réservés par le moteur (root, admin...)
+	ReservedUsernames []string `mapstructure:"reserved_usernames"`
+	// Templates lancés uniquement dans la fenêtre de maintenance de l'instance
+	DisruptiveTemplates []string `mapstructure:"disruptive_templates"`
+}
+
+// HarborPlan limites de stockage (Go) d'un projet Harbor, 0 pour illimité
+type HarborPlan struct {
+	DefaultStorageGB int64 `mapstructure:"default_storage_gb"`
+	MaxStorageGB     int64 `mapstructure:"max_storage_gb"`
+}
+
+// HarborRetentionPolicy règles de rétention des tags, exécutées selon schedule (cron Harbor, avec secondes)
+type HarborRetentionPolicy struct {
+	Schedule string                `mapstructure:"schedule"`
+	Rules    []HarborRetentionRule `mapstructure:"rules"`
+}
+
+// HarborRetentionRule conserve les keep_last derniers tags poussés, ou ceux poussés depuis moins de keep_days jours
+type HarborRetentionRule struct {
+	KeepLast          int64  `mapstructure:"keep_last"`
+	KeepDays          int64  `mapstructure:"keep_days"`
+	TagPattern        string `mapstructure:"tag_pattern"`
+	RepositoryPattern string `mapstructure:"repository_pattern"`
+}
+
 func Load(cmd *cobra.Command) (*Config, error) {
 	var config Config
 	var cfgFile string
@@ -80,6 +200,35 @@ func Load(cmd *cobra.Command) (*Config, error) {
 
 	// Define default values
 	viper.SetDefault("server.port", ":8080")
+	viper.SetDefault("server.idempotency_ttl", 24)
+	viper.SetDefault("dbaas.secret_store.type", "postgres")
+	viper.SetDefault("dbaas.scheduler_interval", 60)
+	viper.SetDefault("dbaas.postgres_versions", []string{"17", "16", "15"})
+	viper.SetDefault("dbaas.templates.provision", []string{"postgres-provision"})
+	viper.SetDefault("dbaas.quotas.max_instances", 10)
+	viper.SetDefault("dbaas.quotas.max_storage_gb", 500)
+	viper.SetDefault("dbaas.quotas.max_concurrent_jobs", 3)
+	viper.SetDefault("dbaas.quotas.default_storage_gb", 10)
+	viper.SetDefault("harbor.robot_default_duration_days", 90)
+	viper.SetDefault("harbor.robot_max_duration_days", 365)
+	viper.SetDefault("harbor.default_plan", "standard")
+	viper.SetDefault("harbor.plans.standard.default_storage_gb", 10)
+	viper.SetDefault("harbor.plans.standard.max_storage_gb", 50)
+	viper.SetDefault("harbor.default_retention.schedule", "0 0 3 * * *")
+	viper.SetDefault("harbor.default_retention.rules", []map[string]interface{}{{"keep_last": 10}})
+	viper.SetDefault("harbor.pull_secret_robot_duration_days", 90)
+	viper.SetDefault("harbor.pull_secret_refresh_before_days", 7)
+	viper.SetDefault("harbor.pull_secret_refresh_interval", 3600)
+	viper.SetDefault("harbor.replication_min_interval_minutes", 60)
+	viper.SetDefault("awx.template_cache_ttl", 300)
+	viper.SetDefault("awx.timeouts.default", 30)
+	viper.SetDefault("awx.timeouts.launch", 60)
+	viper.SetDefault("awx.timeouts.stdout", 60)
+	viper.SetDefault("awx.retry.max_retries", 3)
+	viper.SetDefault("awx.retry.base_delay_ms", 200)
+	viper.SetDefault("awx.retry.max_delay_ms", 5000)
+	viper.SetDefault("awx.circuit_breaker.failure_threshold", 5)
+	viper.SetDefault("awx.circuit_breaker.open_seconds", 30)
 
 	if err := viper.ReadInConfig(); err != nil {
 		klog.Warningf("No config file found, using defaults and environment: %v", err)
@@ -88,5 +237,63 @@ func Load(cmd *cobra.Command) (*Config, error) {
 		klog.Fatalf("Failed to parse config: %v\n", err)
 		os.Exit(1)
 	}
+	if err := validateEngines(config.Dbaas.Engines); err != nil {
+		return nil, err
+	}
+	if err := validateSecretStore(config.Dbaas.SecretStore.Type, config.Dbaas.SecretStore.EncryptionKey); err != nil {
+		return nil, err
+	}
+	if err := validateConfirmationKey(config.Dbaas.ConfirmationKey); err != nil {
+		return nil, err
+	}
+	if _, ok := config.Harbor.Plans[config.Harbor.DefaultPlan]; !ok {
+		return nil, fmt.Errorf("harbor.default_plan: plan '%s' is not defined in harbor.plans", config.Harbor.DefaultPlan)
+	}
 	return &config, nil
 }
+
+// validateSecretStore refuse de démarrer le store postgres sans clé de chiffrement
+func validateSecretStore(storeType, encryptionKey string) error {
+	switch storeType {
+	case "", "postgres":
+		if encryptionKey == "" {
+			return fmt.Errorf("dbaas.secret_store.encryption_key is required for the postgres secret store")
+		}
+	case "file":
+	default:
+		return fmt.Errorf("dbaas.secret_store.type: unknown store '%s'", storeType)
+	}
+	return nil
+}
+
+// validateConfirmationKey exige une clé commune : une clé propre à chaque réplica invaliderait les jetons derrière le load balancer
+func validateConfirmationKey(encoded string) error {
+	if encoded == "" {
+		return fmt.Errorf("dbaas.confirmation_key is required")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("dbaas.confirmation_key: invalid base64: %w", err)
+	}
+	if len(key) < 32 {
+		return fmt.Errorf("dbaas.confirmation_key must be at least 32 bytes, got %d", len(key))
+	}
+	return nil
+}
+
+func validateEngines(engines []DbaasEngine) error {
+	seen := map[string]bool{"postgres": true}
+	for i, engine := range engines {
+		if engine.Name == "" {
+			return fmt.Errorf("dbaas.engines[%d]: name is required", i)
+		}
+		if seen[engine.Name] {
+			return fmt.Errorf("dbaas.engines[%d]: engine '%s' is already defined", i, engine.Name)
+		}
+		seen[engine.Name] = true
+		if engine.Templates.Provision == "" {
+			return fmt.Errorf("dbaas.engines[%d]: templates.provision is required", i)
+		}
+	}
+	return nil
+}
@@ -11,6 +11,34 @@ SELECT * FROM awx_history
 WHERE customer_id = $1
 ORDER BY created_at DESC;
 
+-- name: GetHistoryByInstance :many
+SELECT * FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
+ORDER BY created_at DESC;
+
+-- name: GetActiveHistoryByTemplate :one
+SELECT * FROM awx_history
+WHERE customer_id = $1 AND awx_template_name = $2 AND status IN ('pending', 'running')
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: CountActiveJobsByCustomer :one
+SELECT COUNT(*) FROM awx_history
+WHERE customer_id = $1 AND status IN ('pending', 'running') AND awx_job_id IS NOT NULL;
+
+-- name: GetLatestHistoryByInstance :one
+SELECT * FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: GetLatestCredentialHistory :one
+SELECT * FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
+  AND action_type IN ('create', 'rotate', 'clone')
+ORDER BY created_at DESC
+LIMIT 1;
+
 -- name: GetHistoryByStatus :many
 SELECT * FROM awx_history
 WHERE status = $1
@@ -25,9 +53,9 @@ ORDER BY created_at DESC;
 INSERT INTO awx_history (
   customer_id, awx_job_id, awx_template_name, awx_template_id, 
   action_type, status, instance_name, username, extra_vars, 
-  awx_status, created_by
+  awx_status, created_by, parent_history_id, awx_job_type, scheduled_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
 ) RETURNING *;
 
 -- name: UpdateHistoryStatus :exec
@@ -64,6 +92,11 @@ SELECT * FROM db_instances
 WHERE customer_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC;
 
+-- name: GetDBInstancesByCustomerAndType :many
+SELECT * FROM db_instances
+WHERE customer_id = $1 AND db_type = $2 AND deleted_at IS NULL
+ORDER BY created_at DESC;
+
 -- name: GetDBInstancesByStatus :many
 SELECT * FROM db_instances
 WHERE status = $1 AND deleted_at IS NULL
@@ -72,9 +105,9 @@ ORDER BY created_at DESC;
 -- name: CreateDBInstance :one
 INSERT INTO db_instances (
   customer_id, db_type, version, host, port, username, 
-  status, created_by
+  status, instance_name, created_by, storage_gb
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
 ) RETURNING *;
 
 -- name: UpdateDBInstanceStatus :exec
@@ -87,7 +120,212 @@ UPDATE db_instances
 SET host = $2, port = $3, version = $4, updated_at = NOW()
 WHERE id = $1;
 
+-- name: UpdateDBInstanceVersion :exec
+UPDATE db_instances 
+SET version = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: UpdateDBInstanceStorage :exec
+UPDATE db_instances 
+SET storage_gb = $2, updated_at = NOW()
+WHERE id = $1;
+
 -- name: SoftDeleteDBInstance :exec
 UPDATE db_instances 
 SET deleted_at = NOW(), updated_at = NOW()
-WHERE id = $1;
\ No newline at end of file
+WHERE id = $1;
+
+-- Secrets Queries
+
+-- name: UpsertSecret :exec
+INSERT INTO dbaas_secrets (secret_key, encrypted_value)
+VALUES ($1, $2)
+ON CONFLICT (secret_key) DO UPDATE
+SET encrypted_value = EXCLUDED.encrypted_value, updated_at = NOW();
+
+-- name: TakeSecret :one
+DELETE FROM dbaas_secrets
+WHERE secret_key = $1
+RETURNING encrypted_value;
+
+-- name: DeleteSecret :exec
+DELETE FROM dbaas_secrets WHERE secret_key = $1;
+
+-- name: SecretExists :one
+SELECT EXISTS (
+  SELECT 1 FROM dbaas_secrets WHERE secret_key = $1
+);
+
+-- Backups Queries
+
+-- name: CreateBackup :one
+INSERT INTO db_backups (
+  customer_id, instance_name, awx_history_id, awx_job_id, trigger_type, status
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: UpdateBackupCompletionByHistoryID :exec
+UPDATE db_backups
+SET status = $2, backup_id = $3, size_bytes = $4, backup_at = $5, completed_at = NOW()
+WHERE awx_history_id = $1;
+
+-- name: GetBackupByBackupID :one
+SELECT * FROM db_backups
+WHERE customer_id = $1 AND instance_name = $2 AND backup_id = $3
+LIMIT 1;
+
+-- name: ListBackupsByInstance :many
+SELECT * FROM db_backups
+WHERE customer_id = $1 AND instance_name = $2
+ORDER BY created_at DESC;
+
+-- name: GetBackupSchedule :one
+SELECT * FROM db_backup_schedules
+WHERE customer_id = $1 AND instance_name = $2 LIMIT 1;
+
+-- name: UpsertBackupSchedule :one
+INSERT INTO db_backup_schedules (
+  customer_id, instance_name, interval_hours, enabled, next_run_at, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET interval_hours = EXCLUDED.interval_hours, enabled = EXCLUDED.enabled,
+    next_run_at = EXCLUDED.next_run_at, updated_at = NOW()
+RETURNING *;
+
+-- name: ClaimDueBackupSchedules :many
+UPDATE db_backup_schedules
+SET last_run_at = NOW(),
+    next_run_at = GREATEST(next_run_at, NOW()) + make_interval(hours => interval_hours),
+    updated_at = NOW()
+WHERE id IN (
+  SELECT id FROM db_backup_schedules
+  WHERE enabled AND next_run_at <= NOW()
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- Quotas Queries
+-- name: GetCustomerQuota :one
+SELECT * FROM dbaas_quotas WHERE customer_id = $1;
+
+-- name: UpsertCustomerQuota :one
+INSERT INTO dbaas_quotas (
+  customer_id, max_instances, max_storage_gb, max_concurrent_jobs, updated_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (customer_id) DO UPDATE
+SET max_instances = EXCLUDED.max_instances,
+    max_storage_gb = EXCLUDED.max_storage_gb,
+    max_concurrent_jobs = EXCLUDED.max_concurrent_jobs,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING *;
+
+-- Les instances en échec ne consomment pas de quota
+-- name: GetCustomerInstanceUsage :one
+SELECT COUNT(*) AS instance_count, COALESCE(SUM(storage_gb), 0)::BIGINT AS storage_gb
+FROM db_instances
+WHERE customer_id = $1 AND deleted_at IS NULL AND status <> 'failed';
+
+-- Maintenance Queries
+-- name: GetMaintenanceWindow :one
+SELECT * FROM db_maintenance_windows
+WHERE customer_id = $1 AND instance_name = $2;
+
+-- name: UpsertMaintenanceWindow :one
+INSERT INTO db_maintenance_windows (
+  customer_id, instance_name, days_of_week, start_time, duration_minutes, timezone, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET days_of_week = EXCLUDED.days_of_week,
+    start_time = EXCLUDED.start_time,
+    duration_minutes = EXCLUDED.duration_minutes,
+    timezone = EXCLUDED.timezone,
+    created_by = EXCLUDED.created_by,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: DeleteMaintenanceWindow :execrows
+DELETE FROM db_maintenance_windows
+WHERE customer_id = $1 AND instance_name = $2;
+
+-- name: ListScheduledHistoryByInstance :many
+SELECT * FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2 AND status = 'scheduled'
+ORDER BY scheduled_at;
+
+-- Réserve les opérations dont la fenêtre est ouverte (SKIP LOCKED : sûr avec plusieurs réplicas).
+-- Une réservation sans job depuis plus de 10 minutes (réplica arrêté avant le lancement) est reprise
+-- name: ClaimDueScheduledHistory :many
+UPDATE awx_history
+SET status = 'pending', updated_at = NOW()
+WHERE id IN (
+  SELECT h.id FROM awx_history h
+  JOIN db_instances i ON i.customer_id = h.customer_id AND i.instance_name = h.instance_name AND i.deleted_at IS NULL
+  WHERE i.db_type = $1 AND h.scheduled_at <= NOW() AND (
+    h.status = 'scheduled'
+    OR (h.status = 'pending' AND h.awx_job_id IS NULL AND h.updated_at < NOW() - interval '10 minutes')
+  )
+  FOR UPDATE OF h SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkScheduledHistoryLaunched :exec
+UPDATE awx_history
+SET status = 'running', awx_job_id = $2, awx_template_id = $3, awx_job_type = $4, updated_at = NOW()
+WHERE id = $1;
+
+-- name: RescheduleHistory :exec
+UPDATE awx_history
+SET status = 'scheduled', scheduled_at = $2, updated_at = NOW()
+WHERE id = $1;
+
+-- name: CancelScheduledHistory :one
+UPDATE awx_history
+SET status = 'canceled', completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND customer_id = $2 AND instance_name = $3 AND status = 'scheduled'
+RETURNING *;
+
+-- name: CancelScheduledHistoryByInstance :exec
+UPDATE awx_history
+SET status = 'canceled', completed_at = NOW(), updated_at = NOW()
+WHERE customer_id = $1 AND instance_name = $2 AND status = 'scheduled';
+
+-- name: HasActiveOperationOnInstance :one
+SELECT EXISTS (
+  SELECT 1 FROM awx_history
+  WHERE customer_id = $1 AND instance_name = $2 AND status IN ('pending', 'running')
+    AND awx_job_id IS NOT NULL
+);
+
+-- Lock Queries
+-- Aucune ligne affectée : le bail d'un autre appel est encore valide
+-- name: AcquireInstanceLock :execrows
+INSERT INTO db_instance_locks (customer_id, instance_name, locked_until)
+VALUES ($1, $2, $3)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET locked_until = EXCLUDED.locked_until
+WHERE db_instance_locks.locked_until < NOW();
+
+-- name: ReleaseInstanceLock :exec
+DELETE FROM db_instance_locks
+WHERE customer_id = $1 AND instance_name = $2 AND locked_until = $3;
+
+-- Confirmation Queries
+-- name: CreateConfirmationToken :exec
+INSERT INTO dbaas_confirmation_tokens (nonce, customer_id, instance_name, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- Aucune ligne affectée : jeton inconnu, expiré ou déjà utilisé
+-- name: ConsumeConfirmationToken :execrows
+DELETE FROM dbaas_confirmation_tokens
+WHERE nonce = $1 AND customer_id = $2 AND instance_name = $3 AND expires_at > NOW();
+
+-- name: DeleteExpiredConfirmationTokens :exec
+DELETE FROM dbaas_confirmation_tokens WHERE expires_at <= NOW();
@@ -1,5 +1,5 @@
-CREATE TYPE action_type_enum AS ENUM ('create', 'delete', 'update');
-CREATE TYPE status_enum AS ENUM ('pending', 'running', 'completed', 'failed', 'canceled', 'error');
+CREATE TYPE action_type_enum AS ENUM ('create', 'delete', 'update', 'rotate', 'backup', 'restore', 'clone');
+CREATE TYPE status_enum AS ENUM ('pending', 'running', 'completed', 'failed', 'canceled', 'error', 'scheduled');
 CREATE TYPE awx_status_enum AS ENUM ('pending', 'waiting', 'running', 'successful', 'failed', 'error', 'canceled');
 
 CREATE TABLE awx_history (
@@ -18,7 +18,11 @@ CREATE TABLE awx_history (
     created_by VARCHAR(255) NOT NULL,
     created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
     completed_at TIMESTAMPTZ,
-    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    parent_history_id INTEGER REFERENCES awx_history(id),
+    awx_job_type VARCHAR(20) NOT NULL DEFAULT 'job',
+    -- Opération en attente de la fenêtre de maintenance (status scheduled)
+    scheduled_at TIMESTAMPTZ
 );
 
 CREATE INDEX idx_awx_history_customer_id ON awx_history(customer_id);
@@ -27,6 +31,9 @@ CREATE INDEX idx_awx_history_status ON awx_history(status);
 CREATE INDEX idx_awx_history_action_type ON awx_history(action_type);
 CREATE INDEX idx_awx_history_awx_template_name ON awx_history(awx_template_name);
 CREATE INDEX idx_awx_history_instance_name ON awx_history(instance_name);
+CREATE INDEX idx_awx_history_parent_history_id ON awx_history(parent_history_id);
+CREATE INDEX idx_awx_history_customer_status ON awx_history(customer_id, status);
+CREATE INDEX idx_awx_history_scheduled_at ON awx_history(scheduled_at) WHERE status = 'scheduled';
 
   CREATE TABLE db_instances (
       id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
@@ -41,7 +48,8 @@ CREATE INDEX idx_awx_history_instance_name ON awx_history(instance_name);
       created_by VARCHAR(255) NOT NULL,
       created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
       updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
-      deleted_at TIMESTAMPTZ
+      deleted_at TIMESTAMPTZ,
+      storage_gb INTEGER NOT NULL DEFAULT 0
   );
 
 
@@ -56,4 +64,89 @@ CREATE INDEX idx_db_instances_instance_name ON db_instances(instance_name);
       id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
       offer_type VARCHAR(50) NOT NULL,
       active BOOLEAN NOT NULL
-  );
\ No newline at end of file
+  );
+
+CREATE TABLE dbaas_secrets (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    secret_key VARCHAR(255) NOT NULL UNIQUE,
+    encrypted_value BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE db_backups (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    awx_history_id INTEGER REFERENCES awx_history(id),
+    awx_job_id BIGINT,
+    trigger_type VARCHAR(20) NOT NULL,
+    status status_enum NOT NULL,
+    backup_id VARCHAR(255),
+    size_bytes BIGINT,
+    backup_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_db_backups_instance ON db_backups(customer_id, instance_name);
+CREATE INDEX idx_db_backups_awx_history_id ON db_backups(awx_history_id);
+
+CREATE TABLE db_backup_schedules (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    interval_hours INTEGER NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (customer_id, instance_name)
+);
+
+CREATE INDEX idx_db_backup_schedules_next_run_at ON db_backup_schedules(next_run_at) WHERE enabled;
+
+-- Quotas par client, NULL pour la valeur par défaut de la configuration
+CREATE TABLE dbaas_quotas (
+    customer_id VARCHAR(255) PRIMARY KEY,
+    max_instances INTEGER,
+    max_storage_gb INTEGER,
+    max_concurrent_jobs INTEGER,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Fenêtre de maintenance hebdomadaire d'une instance, les templates disruptifs n'y sont lancés que pendant la fenêtre
+CREATE TABLE db_maintenance_windows (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    days_of_week SMALLINT[] NOT NULL,
+    start_time TIME NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (customer_id, instance_name)
+);
+
+-- Bail posé le temps de vérifier puis lancer une opération sur une instance (plusieurs réplicas)
+CREATE TABLE db_instance_locks (
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (customer_id, instance_name)
+);
+
+-- Jetons de confirmation émis et non encore utilisés : un jeton n'est accepté qu'une fois
+CREATE TABLE dbaas_confirmation_tokens (
+    nonce VARCHAR(64) PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_dbaas_confirmation_tokens_expires_at ON dbaas_confirmation_tokens(expires_at);
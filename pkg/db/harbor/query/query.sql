@@ -6,3 +6,106 @@ INSERT INTO harbor_projects (
 ) VALUES (
     $1, $2, $3
 );
+
+-- name: GetHarborProject :one
+SELECT * FROM harbor_projects
+WHERE name = $1 AND customer_id = $2;
+
+-- name: ListHarborProjectsByCustomer :many
+SELECT * FROM harbor_projects
+WHERE customer_id = $1
+ORDER BY name;
+
+-- name: DeleteHarborProject :exec
+DELETE FROM harbor_projects
+WHERE name = $1 AND customer_id = $2;
+
+-- name: GetHarborCustomerPlan :one
+SELECT * FROM harbor_customer_plans WHERE customer_id = $1;
+
+-- name: UpsertHarborCustomerPlan :one
+INSERT INTO harbor_customer_plans (
+    customer_id,
+    plan,
+    updated_by
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (customer_id) DO UPDATE
+SET plan = EXCLUDED.plan,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING *;
+
+-- name: InsertHarborPullSecret :one
+INSERT INTO harbor_pull_secrets (
+    customer_id,
+    project_name,
+    namespace_name,
+    secret_name,
+    robot_id,
+    robot_name,
+    expires_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING *;
+
+-- name: GetHarborPullSecret :one
+SELECT * FROM harbor_pull_secrets
+WHERE customer_id = $1 AND project_name = $2 AND namespace_name = $3;
+
+-- name: ListHarborPullSecretsByProject :many
+SELECT * FROM harbor_pull_secrets
+WHERE customer_id = $1 AND project_name = $2
+ORDER BY namespace_name;
+
+-- name: ClaimHarborPullSecretsToRefresh :many
+UPDATE harbor_pull_secrets
+SET refresh_claimed_until = now() + interval '15 minutes'
+WHERE id IN (
+  SELECT id FROM harbor_pull_secrets
+  WHERE expires_at IS NOT NULL AND expires_at < $1
+    AND (refresh_claimed_until IS NULL OR refresh_claimed_until < now())
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: UpdateHarborPullSecretRobot :one
+UPDATE harbor_pull_secrets
+SET robot_id = $2,
+    robot_name = $3,
+    expires_at = $4,
+    refresh_claimed_until = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteHarborPullSecret :exec
+DELETE FROM harbor_pull_secrets
+WHERE id = $1;
+
+-- name: CreateHarborHistory :one
+INSERT INTO harbor_history (
+    customer_id, action_type, status, project_name, resource, error_message, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
+
+-- name: ListHarborHistory :many
+SELECT * FROM harbor_history
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2;
+
+-- name: ListHarborHistoryByCustomer :many
+SELECT * FROM harbor_history
+WHERE customer_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3;
+
+-- name: ListHarborHistoryByProject :many
+SELECT * FROM harbor_history
+WHERE customer_id = $1 AND project_name = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4;
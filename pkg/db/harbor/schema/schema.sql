@@ -5,4 +5,66 @@ CREATE TABLE harbor_projects (
     created_by TEXT NOT NULL,
     created_at TIMESTAMP DEFAULT now(),
     updated_at TIMESTAMP DEFAULT now()
-);
\ No newline at end of file
+);
+
+CREATE TABLE harbor_customer_plans (
+    customer_id TEXT PRIMARY KEY,
+    plan TEXT NOT NULL,
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+CREATE TABLE harbor_pull_secrets (
+    id SERIAL PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    project_name TEXT NOT NULL,
+    namespace_name TEXT NOT NULL,
+    secret_name TEXT NOT NULL,
+    robot_id BIGINT NOT NULL,
+    robot_name TEXT NOT NULL,
+    expires_at TIMESTAMPTZ,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT now(),
+    updated_at TIMESTAMPTZ DEFAULT now(),
+    refresh_claimed_until TIMESTAMPTZ,
+    UNIQUE (project_name, namespace_name)
+);
+
+CREATE INDEX idx_harbor_pull_secrets_expires_at ON harbor_pull_secrets(expires_at);
+
+CREATE TYPE harbor_action_type_enum AS ENUM (
+    'create_project',
+    'delete_project',
+    'update_quota',
+    'update_plan',
+    'create_robot',
+    'delete_robot',
+    'add_member',
+    'remove_member',
+    'update_retention',
+    'create_immutable_rule',
+    'delete_immutable_rule',
+    'link_namespace',
+    'unlink_namespace',
+    'refresh_pull_secret',
+    'delete_artifact',
+    'delete_tag',
+    'create_replication',
+    'delete_replication',
+    'run_replication'
+);
+CREATE TYPE harbor_status_enum AS ENUM ('success', 'error');
+
+CREATE TABLE harbor_history (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    action_type harbor_action_type_enum NOT NULL,
+    status harbor_status_enum NOT NULL,
+    project_name VARCHAR(255) NOT NULL,
+    resource VARCHAR(255),
+    error_message TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_harbor_history_customer_id ON harbor_history(customer_id, created_at);
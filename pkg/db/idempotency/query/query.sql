@@ -0,0 +1,42 @@
+-- Réserve la clé ; une clé expirée est réutilisée. Aucune ligne si la clé est déjà prise.
+-- name: ClaimIdempotencyKey :one
+INSERT INTO idempotency_keys (
+    customer_id,
+    idempotency_key,
+    method,
+    path,
+    request_hash
+) VALUES (
+    sqlc.arg(customer_id), sqlc.arg(idempotency_key), sqlc.arg(method), sqlc.arg(path), sqlc.arg(request_hash)
+)
+ON CONFLICT (customer_id, idempotency_key) DO UPDATE SET
+    method = EXCLUDED.method,
+    path = EXCLUDED.path,
+    request_hash = EXCLUDED.request_hash,
+    response_status = NULL,
+    response_content_type = NULL,
+    response_body = NULL,
+    created_at = NOW(),
+    completed_at = NULL
+WHERE idempotency_keys.created_at < sqlc.arg(expired_before)
+RETURNING *;
+
+-- name: GetIdempotencyKey :one
+SELECT * FROM idempotency_keys
+WHERE customer_id = $1 AND idempotency_key = $2;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET response_status = $2,
+    response_content_type = $3,
+    response_body = $4,
+    completed_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteIdempotencyKey :exec
+DELETE FROM idempotency_keys
+WHERE id = $1;
+
+-- name: DeleteExpiredIdempotencyKeys :execrows
+DELETE FROM idempotency_keys
+WHERE created_at < $1;
@@ -0,0 +1,11 @@
+-- +goose Up
+CREATE TABLE dbaas_secrets (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    secret_key VARCHAR(255) NOT NULL UNIQUE,
+    encrypted_value BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- +goose Down
+DROP TABLE dbaas_secrets;
@@ -0,0 +1,7 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE action_type_enum ADD VALUE IF NOT EXISTS 'rotate';
+
+-- +goose Down
+-- PostgreSQL ne permet pas de supprimer une valeur d'un type ENUM
+SELECT 1;
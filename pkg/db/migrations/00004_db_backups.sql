@@ -0,0 +1,42 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE action_type_enum ADD VALUE IF NOT EXISTS 'backup';
+
+CREATE TABLE db_backups (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    awx_history_id INTEGER REFERENCES awx_history(id),
+    awx_job_id BIGINT,
+    trigger_type VARCHAR(20) NOT NULL,
+    status status_enum NOT NULL,
+    backup_id VARCHAR(255),
+    size_bytes BIGINT,
+    backup_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX idx_db_backups_instance ON db_backups(customer_id, instance_name);
+CREATE INDEX idx_db_backups_awx_history_id ON db_backups(awx_history_id);
+
+CREATE TABLE db_backup_schedules (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    interval_hours INTEGER NOT NULL,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_run_at TIMESTAMPTZ NOT NULL,
+    last_run_at TIMESTAMPTZ,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (customer_id, instance_name)
+);
+
+CREATE INDEX idx_db_backup_schedules_next_run_at ON db_backup_schedules(next_run_at) WHERE enabled;
+
+-- +goose Down
+DROP TABLE db_backup_schedules;
+DROP TABLE db_backups;
+-- PostgreSQL ne permet pas de supprimer une valeur d'un type ENUM
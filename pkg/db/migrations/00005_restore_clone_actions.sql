@@ -0,0 +1,8 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE action_type_enum ADD VALUE IF NOT EXISTS 'restore';
+ALTER TYPE action_type_enum ADD VALUE IF NOT EXISTS 'clone';
+
+-- +goose Down
+-- PostgreSQL ne permet pas de supprimer une valeur d'un type ENUM
+SELECT 1;
@@ -0,0 +1,7 @@
+-- +goose Up
+ALTER TABLE awx_history ADD COLUMN parent_history_id INTEGER REFERENCES awx_history(id);
+CREATE INDEX idx_awx_history_parent_history_id ON awx_history(parent_history_id);
+
+-- +goose Down
+DROP INDEX idx_awx_history_parent_history_id;
+ALTER TABLE awx_history DROP COLUMN parent_history_id;
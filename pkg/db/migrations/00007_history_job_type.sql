@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE awx_history ADD COLUMN awx_job_type VARCHAR(20) NOT NULL DEFAULT 'job';
+
+-- +goose Down
+ALTER TABLE awx_history DROP COLUMN awx_job_type;
@@ -0,0 +1,18 @@
+-- +goose Up
+ALTER TABLE db_instances ADD COLUMN storage_gb INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE dbaas_quotas (
+    customer_id VARCHAR(255) PRIMARY KEY,
+    max_instances INTEGER,
+    max_storage_gb INTEGER,
+    max_concurrent_jobs INTEGER,
+    updated_by VARCHAR(255) NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_awx_history_customer_status ON awx_history(customer_id, status);
+
+-- +goose Down
+DROP INDEX idx_awx_history_customer_status;
+DROP TABLE dbaas_quotas;
+ALTER TABLE db_instances DROP COLUMN storage_gb;
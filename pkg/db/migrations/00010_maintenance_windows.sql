@@ -0,0 +1,26 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE status_enum ADD VALUE IF NOT EXISTS 'scheduled';
+
+ALTER TABLE awx_history ADD COLUMN scheduled_at TIMESTAMPTZ;
+CREATE INDEX idx_awx_history_scheduled_at ON awx_history(scheduled_at) WHERE status = 'scheduled';
+
+CREATE TABLE db_maintenance_windows (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    days_of_week SMALLINT[] NOT NULL,
+    start_time TIME NOT NULL,
+    duration_minutes INTEGER NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (customer_id, instance_name)
+);
+
+-- +goose Down
+DROP TABLE db_maintenance_windows;
+DROP INDEX idx_awx_history_scheduled_at;
+ALTER TABLE awx_history DROP COLUMN scheduled_at;
+-- PostgreSQL ne permet pas de supprimer une valeur d'un type ENUM
@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE harbor_customer_plans (
+    customer_id TEXT PRIMARY KEY,
+    plan TEXT NOT NULL,
+    updated_by TEXT NOT NULL,
+    updated_at TIMESTAMP DEFAULT now()
+);
+
+-- +goose Down
+DROP TABLE harbor_customer_plans;
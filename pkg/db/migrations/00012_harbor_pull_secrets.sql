@@ -0,0 +1,20 @@
+-- +goose Up
+CREATE TABLE harbor_pull_secrets (
+    id SERIAL PRIMARY KEY,
+    customer_id TEXT NOT NULL,
+    project_name TEXT NOT NULL,
+    namespace_name TEXT NOT NULL,
+    secret_name TEXT NOT NULL,
+    robot_id BIGINT NOT NULL,
+    robot_name TEXT NOT NULL,
+    expires_at TIMESTAMP,
+    created_by TEXT NOT NULL,
+    created_at TIMESTAMP DEFAULT now(),
+    updated_at TIMESTAMP DEFAULT now(),
+    UNIQUE (project_name, namespace_name)
+);
+
+CREATE INDEX idx_harbor_pull_secrets_expires_at ON harbor_pull_secrets(expires_at);
+
+-- +goose Down
+DROP TABLE harbor_pull_secrets;
@@ -0,0 +1,39 @@
+-- +goose Up
+CREATE TYPE harbor_action_type_enum AS ENUM (
+    'create_project',
+    'delete_project',
+    'update_quota',
+    'update_plan',
+    'create_robot',
+    'delete_robot',
+    'add_member',
+    'remove_member',
+    'update_retention',
+    'create_immutable_rule',
+    'delete_immutable_rule',
+    'link_namespace',
+    'unlink_namespace',
+    'refresh_pull_secret',
+    'delete_artifact',
+    'delete_tag'
+);
+CREATE TYPE harbor_status_enum AS ENUM ('success', 'error');
+
+CREATE TABLE harbor_history (
+    id INTEGER GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    action_type harbor_action_type_enum NOT NULL,
+    status harbor_status_enum NOT NULL,
+    project_name VARCHAR(255) NOT NULL,
+    resource VARCHAR(255),
+    error_message TEXT,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_harbor_history_customer_id ON harbor_history(customer_id, created_at);
+
+-- +goose Down
+DROP TABLE harbor_history;
+DROP TYPE harbor_status_enum;
+DROP TYPE harbor_action_type_enum;
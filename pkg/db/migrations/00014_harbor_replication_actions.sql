@@ -0,0 +1,9 @@
+-- +goose NO TRANSACTION
+-- +goose Up
+ALTER TYPE harbor_action_type_enum ADD VALUE IF NOT EXISTS 'create_replication';
+ALTER TYPE harbor_action_type_enum ADD VALUE IF NOT EXISTS 'delete_replication';
+ALTER TYPE harbor_action_type_enum ADD VALUE IF NOT EXISTS 'run_replication';
+
+-- +goose Down
+-- PostgreSQL ne permet pas de supprimer une valeur d'un type ENUM
+SELECT 1;
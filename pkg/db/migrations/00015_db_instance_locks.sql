@@ -0,0 +1,10 @@
+-- +goose Up
+CREATE TABLE db_instance_locks (
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    locked_until TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (customer_id, instance_name)
+);
+
+-- +goose Down
+DROP TABLE db_instance_locks;
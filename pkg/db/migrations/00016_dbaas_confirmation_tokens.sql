@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE dbaas_confirmation_tokens (
+    nonce VARCHAR(64) PRIMARY KEY,
+    customer_id VARCHAR(255) NOT NULL,
+    instance_name VARCHAR(20) NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX idx_dbaas_confirmation_tokens_expires_at ON dbaas_confirmation_tokens(expires_at);
+
+-- +goose Down
+DROP TABLE dbaas_confirmation_tokens;
@@ -0,0 +1,5 @@
+-- +goose Up
+ALTER TABLE harbor_pull_secrets ADD COLUMN refresh_claimed_until TIMESTAMP;
+
+-- +goose Down
+ALTER TABLE harbor_pull_secrets DROP COLUMN refresh_claimed_until;
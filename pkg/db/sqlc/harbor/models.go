@@ -5,9 +5,132 @@
 package harbordb
 
 import (
+	"database/sql/driver"
+	"fmt"
+
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type HarborActionTypeEnum string
+
+const (
+	HarborActionTypeEnumCreateProject       HarborActionTypeEnum = "create_project"
+	HarborActionTypeEnumDeleteProject       HarborActionTypeEnum = "delete_project"
+	HarborActionTypeEnumUpdateQuota         HarborActionTypeEnum = "update_quota"
+	HarborActionTypeEnumUpdatePlan          HarborActionTypeEnum = "update_plan"
+	HarborActionTypeEnumCreateRobot         HarborActionTypeEnum = "create_robot"
+	HarborActionTypeEnumDeleteRobot         HarborActionTypeEnum = "delete_robot"
+	HarborActionTypeEnumAddMember           HarborActionTypeEnum = "add_member"
+	HarborActionTypeEnumRemoveMember        HarborActionTypeEnum = "remove_member"
+	HarborActionTypeEnumUpdateRetention     HarborActionTypeEnum = "update_retention"
+	HarborActionTypeEnumCreateImmutableRule HarborActionTypeEnum = "create_immutable_rule"
+	HarborActionTypeEnumDeleteImmutableRule HarborActionTypeEnum = "delete_immutable_rule"
+	HarborActionTypeEnumLinkNamespace       HarborActionTypeEnum = "link_namespace"
+	HarborActionTypeEnumUnlinkNamespace     HarborActionTypeEnum = "unlink_namespace"
+	HarborActionTypeEnumRefreshPullSecret   HarborActionTypeEnum = "refresh_pull_secret"
+	HarborActionTypeEnumDeleteArtifact      HarborActionTypeEnum = "delete_artifact"
+	HarborActionTypeEnumDeleteTag           HarborActionTypeEnum = "delete_tag"
+	HarborActionTypeEnumCreateReplication   HarborActionTypeEnum = "create_replication"
+	HarborActionTypeEnumDeleteReplication   HarborActionTypeEnum = "delete_replication"
+	HarborActionTypeEnumRunReplication      HarborActionTypeEnum = "run_replication"
+)
+
+func (e *HarborActionTypeEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HarborActionTypeEnum(s)
+	case string:
+		*e = HarborActionTypeEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HarborActionTypeEnum: %T", src)
+	}
+	return nil
+}
+
+type NullHarborActionTypeEnum struct {
+	HarborActionTypeEnum HarborActionTypeEnum
+	Valid                bool // Valid is true if HarborActionTypeEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHarborActionTypeEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.HarborActionTypeEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HarborActionTypeEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHarborActionTypeEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HarborActionTypeEnum), nil
+}
+
+type HarborStatusEnum string
+
+const (
+	HarborStatusEnumSuccess HarborStatusEnum = "success"
+	HarborStatusEnumError   HarborStatusEnum = "error"
+)
+
+func (e *HarborStatusEnum) Scan(src interface{}) error {
+	switch s := src.(type) {
+	case []byte:
+		*e = HarborStatusEnum(s)
+	case string:
+		*e = HarborStatusEnum(s)
+	default:
+		return fmt.Errorf("unsupported scan type for HarborStatusEnum: %T", src)
+	}
+	return nil
+}
+
+type NullHarborStatusEnum struct {
+	HarborStatusEnum HarborStatusEnum
+	Valid            bool // Valid is true if HarborStatusEnum is not NULL
+}
+
+// Scan implements the Scanner interface.
+func (ns *NullHarborStatusEnum) Scan(value interface{}) error {
+	if value == nil {
+		ns.HarborStatusEnum, ns.Valid = "", false
+		return nil
+	}
+	ns.Valid = true
+	return ns.HarborStatusEnum.Scan(value)
+}
+
+// Value implements the driver Valuer interface.
+func (ns NullHarborStatusEnum) Value() (driver.Value, error) {
+	if !ns.Valid {
+		return nil, nil
+	}
+	return string(ns.HarborStatusEnum), nil
+}
+
+type HarborCustomerPlan struct {
+	CustomerID string
+	Plan       string
+	UpdatedBy  string
+	UpdatedAt  pgtype.Timestamp
+}
+
+type HarborHistory struct {
+	ID           int32
+	CustomerID   string
+	ActionType   HarborActionTypeEnum
+	Status       HarborStatusEnum
+	ProjectName  string
+	Resource     pgtype.Text
+	ErrorMessage pgtype.Text
+	CreatedBy    string
+	CreatedAt    pgtype.Timestamptz
+}
+
 type HarborProject struct {
 	ID         int32
 	Name       string
@@ -16,3 +139,18 @@ type HarborProject struct {
 	CreatedAt  pgtype.Timestamp
 	UpdatedAt  pgtype.Timestamp
 }
+
+type HarborPullSecret struct {
+	ID                  int32
+	CustomerID          string
+	ProjectName         string
+	NamespaceName       string
+	SecretName          string
+	RobotID             int64
+	RobotName           string
+	ExpiresAt           pgtype.Timestamptz
+	CreatedBy           string
+	CreatedAt           pgtype.Timestamptz
+	UpdatedAt           pgtype.Timestamptz
+	RefreshClaimedUntil pgtype.Timestamptz
+}
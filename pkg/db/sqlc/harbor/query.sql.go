@@ -7,7 +7,193 @@ package harbordb
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const claimHarborPullSecretsToRefresh = `-- name: ClaimHarborPullSecretsToRefresh :many
+UPDATE harbor_pull_secrets
+SET refresh_claimed_until = now() + interval '15 minutes'
+WHERE id IN (
+  SELECT id FROM harbor_pull_secrets
+  WHERE expires_at IS NOT NULL AND expires_at < $1
+    AND (refresh_claimed_until IS NULL OR refresh_claimed_until < now())
+  FOR UPDATE SKIP LOCKED
 )
+RETURNING id, customer_id, project_name, namespace_name, secret_name, robot_id, robot_name, expires_at, created_by, created_at, updated_at, refresh_claimed_until
+`
+
+func (q *Queries) ClaimHarborPullSecretsToRefresh(ctx context.Context, expiresAt pgtype.Timestamptz) ([]HarborPullSecret, error) {
+	rows, err := q.db.Query(ctx, claimHarborPullSecretsToRefresh, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborPullSecret
+	for rows.Next() {
+		var i HarborPullSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.ProjectName,
+			&i.NamespaceName,
+			&i.SecretName,
+			&i.RobotID,
+			&i.RobotName,
+			&i.ExpiresAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefreshClaimedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createHarborHistory = `-- name: CreateHarborHistory :one
+INSERT INTO harbor_history (
+    customer_id, action_type, status, project_name, resource, error_message, created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING id, customer_id, action_type, status, project_name, resource, error_message, created_by, created_at
+`
+
+type CreateHarborHistoryParams struct {
+	CustomerID   string
+	ActionType   HarborActionTypeEnum
+	Status       HarborStatusEnum
+	ProjectName  string
+	Resource     pgtype.Text
+	ErrorMessage pgtype.Text
+	CreatedBy    string
+}
+
+func (q *Queries) CreateHarborHistory(ctx context.Context, arg CreateHarborHistoryParams) (HarborHistory, error) {
+	row := q.db.QueryRow(ctx, createHarborHistory,
+		arg.CustomerID,
+		arg.ActionType,
+		arg.Status,
+		arg.ProjectName,
+		arg.Resource,
+		arg.ErrorMessage,
+		arg.CreatedBy,
+	)
+	var i HarborHistory
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.ActionType,
+		&i.Status,
+		&i.ProjectName,
+		&i.Resource,
+		&i.ErrorMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteHarborProject = `-- name: DeleteHarborProject :exec
+DELETE FROM harbor_projects
+WHERE name = $1 AND customer_id = $2
+`
+
+type DeleteHarborProjectParams struct {
+	Name       string
+	CustomerID string
+}
+
+func (q *Queries) DeleteHarborProject(ctx context.Context, arg DeleteHarborProjectParams) error {
+	_, err := q.db.Exec(ctx, deleteHarborProject, arg.Name, arg.CustomerID)
+	return err
+}
+
+const deleteHarborPullSecret = `-- name: DeleteHarborPullSecret :exec
+DELETE FROM harbor_pull_secrets
+WHERE id = $1
+`
+
+func (q *Queries) DeleteHarborPullSecret(ctx context.Context, id int32) error {
+	_, err := q.db.Exec(ctx, deleteHarborPullSecret, id)
+	return err
+}
+
+const getHarborCustomerPlan = `-- name: GetHarborCustomerPlan :one
+SELECT customer_id, plan, updated_by, updated_at FROM harbor_customer_plans WHERE customer_id = $1
+`
+
+func (q *Queries) GetHarborCustomerPlan(ctx context.Context, customerID string) (HarborCustomerPlan, error) {
+	row := q.db.QueryRow(ctx, getHarborCustomerPlan, customerID)
+	var i HarborCustomerPlan
+	err := row.Scan(
+		&i.CustomerID,
+		&i.Plan,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHarborProject = `-- name: GetHarborProject :one
+SELECT id, name, customer_id, created_by, created_at, updated_at FROM harbor_projects
+WHERE name = $1 AND customer_id = $2
+`
+
+type GetHarborProjectParams struct {
+	Name       string
+	CustomerID string
+}
+
+func (q *Queries) GetHarborProject(ctx context.Context, arg GetHarborProjectParams) (HarborProject, error) {
+	row := q.db.QueryRow(ctx, getHarborProject, arg.Name, arg.CustomerID)
+	var i HarborProject
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.CustomerID,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getHarborPullSecret = `-- name: GetHarborPullSecret :one
+SELECT id, customer_id, project_name, namespace_name, secret_name, robot_id, robot_name, expires_at, created_by, created_at, updated_at, refresh_claimed_until FROM harbor_pull_secrets
+WHERE customer_id = $1 AND project_name = $2 AND namespace_name = $3
+`
+
+type GetHarborPullSecretParams struct {
+	CustomerID    string
+	ProjectName   string
+	NamespaceName string
+}
+
+func (q *Queries) GetHarborPullSecret(ctx context.Context, arg GetHarborPullSecretParams) (HarborPullSecret, error) {
+	row := q.db.QueryRow(ctx, getHarborPullSecret, arg.CustomerID, arg.ProjectName, arg.NamespaceName)
+	var i HarborPullSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.ProjectName,
+		&i.NamespaceName,
+		&i.SecretName,
+		&i.RobotID,
+		&i.RobotName,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RefreshClaimedUntil,
+	)
+	return i, err
+}
 
 const insertHarborProject = `-- name: InsertHarborProject :exec
 INSERT INTO harbor_projects (
@@ -29,3 +215,352 @@ func (q *Queries) InsertHarborProject(ctx context.Context, arg InsertHarborProje
 	_, err := q.db.Exec(ctx, insertHarborProject, arg.Name, arg.CustomerID, arg.CreatedBy)
 	return err
 }
+
+const insertHarborPullSecret = `-- name: InsertHarborPullSecret :one
+INSERT INTO harbor_pull_secrets (
+    customer_id,
+    project_name,
+    namespace_name,
+    secret_name,
+    robot_id,
+    robot_name,
+    expires_at,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+)
+RETURNING id, customer_id, project_name, namespace_name, secret_name, robot_id, robot_name, expires_at, created_by, created_at, updated_at, refresh_claimed_until
+`
+
+type InsertHarborPullSecretParams struct {
+	CustomerID    string
+	ProjectName   string
+	NamespaceName string
+	SecretName    string
+	RobotID       int64
+	RobotName     string
+	ExpiresAt     pgtype.Timestamptz
+	CreatedBy     string
+}
+
+func (q *Queries) InsertHarborPullSecret(ctx context.Context, arg InsertHarborPullSecretParams) (HarborPullSecret, error) {
+	row := q.db.QueryRow(ctx, insertHarborPullSecret,
+		arg.CustomerID,
+		arg.ProjectName,
+		arg.NamespaceName,
+		arg.SecretName,
+		arg.RobotID,
+		arg.RobotName,
+		arg.ExpiresAt,
+		arg.CreatedBy,
+	)
+	var i HarborPullSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.ProjectName,
+		&i.NamespaceName,
+		&i.SecretName,
+		&i.RobotID,
+		&i.RobotName,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RefreshClaimedUntil,
+	)
+	return i, err
+}
+
+const listHarborHistory = `-- name: ListHarborHistory :many
+SELECT id, customer_id, action_type, status, project_name, resource, error_message, created_by, created_at FROM harbor_history
+ORDER BY created_at DESC, id DESC
+LIMIT $1 OFFSET $2
+`
+
+type ListHarborHistoryParams struct {
+	Limit  int32
+	Offset int32
+}
+
+func (q *Queries) ListHarborHistory(ctx context.Context, arg ListHarborHistoryParams) ([]HarborHistory, error) {
+	rows, err := q.db.Query(ctx, listHarborHistory,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborHistory
+	for rows.Next() {
+		var i HarborHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.ActionType,
+			&i.Status,
+			&i.ProjectName,
+			&i.Resource,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHarborHistoryByCustomer = `-- name: ListHarborHistoryByCustomer :many
+SELECT id, customer_id, action_type, status, project_name, resource, error_message, created_by, created_at FROM harbor_history
+WHERE customer_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2 OFFSET $3
+`
+
+type ListHarborHistoryByCustomerParams struct {
+	CustomerID string
+	Limit      int32
+	Offset     int32
+}
+
+func (q *Queries) ListHarborHistoryByCustomer(ctx context.Context, arg ListHarborHistoryByCustomerParams) ([]HarborHistory, error) {
+	rows, err := q.db.Query(ctx, listHarborHistoryByCustomer,
+		arg.CustomerID,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborHistory
+	for rows.Next() {
+		var i HarborHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.ActionType,
+			&i.Status,
+			&i.ProjectName,
+			&i.Resource,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHarborHistoryByProject = `-- name: ListHarborHistoryByProject :many
+SELECT id, customer_id, action_type, status, project_name, resource, error_message, created_by, created_at FROM harbor_history
+WHERE customer_id = $1 AND project_name = $2
+ORDER BY created_at DESC, id DESC
+LIMIT $3 OFFSET $4
+`
+
+type ListHarborHistoryByProjectParams struct {
+	CustomerID  string
+	ProjectName string
+	Limit       int32
+	Offset      int32
+}
+
+func (q *Queries) ListHarborHistoryByProject(ctx context.Context, arg ListHarborHistoryByProjectParams) ([]HarborHistory, error) {
+	rows, err := q.db.Query(ctx, listHarborHistoryByProject,
+		arg.CustomerID,
+		arg.ProjectName,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborHistory
+	for rows.Next() {
+		var i HarborHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.ActionType,
+			&i.Status,
+			&i.ProjectName,
+			&i.Resource,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHarborProjectsByCustomer = `-- name: ListHarborProjectsByCustomer :many
+SELECT id, name, customer_id, created_by, created_at, updated_at FROM harbor_projects
+WHERE customer_id = $1
+ORDER BY name
+`
+
+func (q *Queries) ListHarborProjectsByCustomer(ctx context.Context, customerID string) ([]HarborProject, error) {
+	rows, err := q.db.Query(ctx, listHarborProjectsByCustomer, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborProject
+	for rows.Next() {
+		var i HarborProject
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.CustomerID,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listHarborPullSecretsByProject = `-- name: ListHarborPullSecretsByProject :many
+SELECT id, customer_id, project_name, namespace_name, secret_name, robot_id, robot_name, expires_at, created_by, created_at, updated_at, refresh_claimed_until FROM harbor_pull_secrets
+WHERE customer_id = $1 AND project_name = $2
+ORDER BY namespace_name
+`
+
+type ListHarborPullSecretsByProjectParams struct {
+	CustomerID  string
+	ProjectName string
+}
+
+func (q *Queries) ListHarborPullSecretsByProject(ctx context.Context, arg ListHarborPullSecretsByProjectParams) ([]HarborPullSecret, error) {
+	rows, err := q.db.Query(ctx, listHarborPullSecretsByProject, arg.CustomerID, arg.ProjectName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []HarborPullSecret
+	for rows.Next() {
+		var i HarborPullSecret
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.ProjectName,
+			&i.NamespaceName,
+			&i.SecretName,
+			&i.RobotID,
+			&i.RobotName,
+			&i.ExpiresAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.RefreshClaimedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateHarborPullSecretRobot = `-- name: UpdateHarborPullSecretRobot :one
+UPDATE harbor_pull_secrets
+SET robot_id = $2,
+    robot_name = $3,
+    expires_at = $4,
+    refresh_claimed_until = NULL,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, customer_id, project_name, namespace_name, secret_name, robot_id, robot_name, expires_at, created_by, created_at, updated_at, refresh_claimed_until
+`
+
+type UpdateHarborPullSecretRobotParams struct {
+	ID        int32
+	RobotID   int64
+	RobotName string
+	ExpiresAt pgtype.Timestamptz
+}
+
+func (q *Queries) UpdateHarborPullSecretRobot(ctx context.Context, arg UpdateHarborPullSecretRobotParams) (HarborPullSecret, error) {
+	row := q.db.QueryRow(ctx, updateHarborPullSecretRobot,
+		arg.ID,
+		arg.RobotID,
+		arg.RobotName,
+		arg.ExpiresAt,
+	)
+	var i HarborPullSecret
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.ProjectName,
+		&i.NamespaceName,
+		&i.SecretName,
+		&i.RobotID,
+		&i.RobotName,
+		&i.ExpiresAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.RefreshClaimedUntil,
+	)
+	return i, err
+}
+
+const upsertHarborCustomerPlan = `-- name: UpsertHarborCustomerPlan :one
+INSERT INTO harbor_customer_plans (
+    customer_id,
+    plan,
+    updated_by
+) VALUES (
+    $1, $2, $3
+)
+ON CONFLICT (customer_id) DO UPDATE
+SET plan = EXCLUDED.plan,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = now()
+RETURNING customer_id, plan, updated_by, updated_at
+`
+
+type UpsertHarborCustomerPlanParams struct {
+	CustomerID string
+	Plan       string
+	UpdatedBy  string
+}
+
+func (q *Queries) UpsertHarborCustomerPlan(ctx context.Context, arg UpsertHarborCustomerPlanParams) (HarborCustomerPlan, error) {
+	row := q.db.QueryRow(ctx, upsertHarborCustomerPlan, arg.CustomerID, arg.Plan, arg.UpdatedBy)
+	var i HarborCustomerPlan
+	err := row.Scan(
+		&i.CustomerID,
+		&i.Plan,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
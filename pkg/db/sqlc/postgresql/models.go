@@ -14,9 +14,13 @@ import (
 type ActionTypeEnum string
 
 const (
-	ActionTypeEnumCreate ActionTypeEnum = "create"
-	ActionTypeEnumDelete ActionTypeEnum = "delete"
-	ActionTypeEnumUpdate ActionTypeEnum = "update"
+	ActionTypeEnumCreate  ActionTypeEnum = "create"
+	ActionTypeEnumDelete  ActionTypeEnum = "delete"
+	ActionTypeEnumUpdate  ActionTypeEnum = "update"
+	ActionTypeEnumRotate  ActionTypeEnum = "rotate"
+	ActionTypeEnumBackup  ActionTypeEnum = "backup"
+	ActionTypeEnumRestore ActionTypeEnum = "restore"
+	ActionTypeEnumClone   ActionTypeEnum = "clone"
 )
 
 func (e *ActionTypeEnum) Scan(src interface{}) error {
@@ -110,6 +114,7 @@ const (
 	StatusEnumFailed    StatusEnum = "failed"
 	StatusEnumCanceled  StatusEnum = "canceled"
 	StatusEnumError     StatusEnum = "error"
+	StatusEnumScheduled StatusEnum = "scheduled"
 )
 
 func (e *StatusEnum) Scan(src interface{}) error {
@@ -164,6 +169,37 @@ type AwxHistory struct {
 	CreatedAt       pgtype.Timestamptz
 	CompletedAt     pgtype.Timestamptz
 	UpdatedAt       pgtype.Timestamptz
+	ParentHistoryID pgtype.Int4
+	AwxJobType      string
+	ScheduledAt     pgtype.Timestamptz
+}
+
+type DbBackup struct {
+	ID           int32
+	CustomerID   string
+	InstanceName string
+	AwxHistoryID pgtype.Int4
+	AwxJobID     pgtype.Int8
+	TriggerType  string
+	Status       StatusEnum
+	BackupID     pgtype.Text
+	SizeBytes    pgtype.Int8
+	BackupAt     pgtype.Timestamptz
+	CreatedAt    pgtype.Timestamptz
+	CompletedAt  pgtype.Timestamptz
+}
+
+type DbBackupSchedule struct {
+	ID            int32
+	CustomerID    string
+	InstanceName  string
+	IntervalHours int32
+	Enabled       bool
+	NextRunAt     pgtype.Timestamptz
+	LastRunAt     pgtype.Timestamptz
+	CreatedBy     string
+	CreatedAt     pgtype.Timestamptz
+	UpdatedAt     pgtype.Timestamptz
 }
 
 type DbInstance struct {
@@ -180,6 +216,33 @@ type DbInstance struct {
 	CreatedAt    pgtype.Timestamptz
 	UpdatedAt    pgtype.Timestamptz
 	DeletedAt    pgtype.Timestamptz
+	StorageGb    int32
+}
+
+type DbInstanceLock struct {
+	CustomerID   string
+	InstanceName string
+	LockedUntil  pgtype.Timestamptz
+}
+
+type DbMaintenanceWindow struct {
+	ID              int32
+	CustomerID      string
+	InstanceName    string
+	DaysOfWeek      []int16
+	StartTime       pgtype.Time
+	DurationMinutes int32
+	Timezone        string
+	CreatedBy       string
+	CreatedAt       pgtype.Timestamptz
+	UpdatedAt       pgtype.Timestamptz
+}
+
+type DbaasConfirmationToken struct {
+	Nonce        string
+	CustomerID   string
+	InstanceName string
+	ExpiresAt    pgtype.Timestamptz
 }
 
 type DbaasOffer struct {
@@ -187,3 +250,20 @@ type DbaasOffer struct {
 	OfferType string
 	Active    bool
 }
+
+type DbaasQuota struct {
+	CustomerID        string
+	MaxInstances      pgtype.Int4
+	MaxStorageGb      pgtype.Int4
+	MaxConcurrentJobs pgtype.Int4
+	UpdatedBy         string
+	UpdatedAt         pgtype.Timestamptz
+}
+
+type DbaasSecret struct {
+	ID             int32
+	SecretKey      string
+	EncryptedValue []byte
+	CreatedAt      pgtype.Timestamptz
+	UpdatedAt      pgtype.Timestamptz
+}
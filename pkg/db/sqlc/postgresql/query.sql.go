@@ -11,24 +11,307 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acquireInstanceLock = `-- name: AcquireInstanceLock :execrows
+
+INSERT INTO db_instance_locks (customer_id, instance_name, locked_until)
+VALUES ($1, $2, $3)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET locked_until = EXCLUDED.locked_until
+WHERE db_instance_locks.locked_until < NOW()
+`
+
+type AcquireInstanceLockParams struct {
+	CustomerID   string
+	InstanceName string
+	LockedUntil  pgtype.Timestamptz
+}
+
+func (q *Queries) AcquireInstanceLock(ctx context.Context, arg AcquireInstanceLockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acquireInstanceLock, arg.CustomerID, arg.InstanceName, arg.LockedUntil)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const cancelScheduledHistory = `-- name: CancelScheduledHistory :one
+UPDATE awx_history
+SET status = 'canceled', completed_at = NOW(), updated_at = NOW()
+WHERE id = $1 AND customer_id = $2 AND instance_name = $3 AND status = 'scheduled'
+RETURNING id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at
+`
+
+type CancelScheduledHistoryParams struct {
+	ID           int32
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) CancelScheduledHistory(ctx context.Context, arg CancelScheduledHistoryParams) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, cancelScheduledHistory, arg.ID, arg.CustomerID, arg.InstanceName)
+	var i AwxHistory
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.AwxJobID,
+		&i.AwxTemplateName,
+		&i.AwxTemplateID,
+		&i.ActionType,
+		&i.Status,
+		&i.InstanceName,
+		&i.Username,
+		&i.ExtraVars,
+		&i.AwxStatus,
+		&i.ErrorMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const cancelScheduledHistoryByInstance = `-- name: CancelScheduledHistoryByInstance :exec
+UPDATE awx_history
+SET status = 'canceled', completed_at = NOW(), updated_at = NOW()
+WHERE customer_id = $1 AND instance_name = $2 AND status = 'scheduled'
+`
+
+type CancelScheduledHistoryByInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) CancelScheduledHistoryByInstance(ctx context.Context, arg CancelScheduledHistoryByInstanceParams) error {
+	_, err := q.db.Exec(ctx, cancelScheduledHistoryByInstance, arg.CustomerID, arg.InstanceName)
+	return err
+}
+
+const claimDueBackupSchedules = `-- name: ClaimDueBackupSchedules :many
+UPDATE db_backup_schedules
+SET last_run_at = NOW(),
+    next_run_at = GREATEST(next_run_at, NOW()) + make_interval(hours => interval_hours),
+    updated_at = NOW()
+WHERE id IN (
+  SELECT id FROM db_backup_schedules
+  WHERE enabled AND next_run_at <= NOW()
+  FOR UPDATE SKIP LOCKED
+)
+RETURNING id, customer_id, instance_name, interval_hours, enabled, next_run_at, last_run_at, created_by, created_at, updated_at
+`
+
+func (q *Queries) ClaimDueBackupSchedules(ctx context.Context) ([]DbBackupSchedule, error) {
+	rows, err := q.db.Query(ctx, claimDueBackupSchedules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DbBackupSchedule
+	for rows.Next() {
+		var i DbBackupSchedule
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.InstanceName,
+			&i.IntervalHours,
+			&i.Enabled,
+			&i.NextRunAt,
+			&i.LastRunAt,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const claimDueScheduledHistory = `-- name: ClaimDueScheduledHistory :many
+
+UPDATE awx_history
+SET status = 'pending', updated_at = NOW()
+WHERE id IN (
+  SELECT h.id FROM awx_history h
+  JOIN db_instances i ON i.customer_id = h.customer_id AND i.instance_name = h.instance_name AND i.deleted_at IS NULL
+  WHERE i.db_type = $1 AND h.scheduled_at <= NOW() AND (
+    h.status = 'scheduled'
+    OR (h.status = 'pending' AND h.awx_job_id IS NULL AND h.updated_at < NOW() - interval '10 minutes')
+  )
+  FOR UPDATE OF h SKIP LOCKED
+)
+RETURNING id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at
+`
+
+// Réserve les opérations dont la fenêtre est ouverte (SKIP LOCKED : sûr avec plusieurs réplicas).
+// Une réservation sans job depuis plus de 10 minutes (réplica arrêté avant le lancement) est reprise
+func (q *Queries) ClaimDueScheduledHistory(ctx context.Context, dbType string) ([]AwxHistory, error) {
+	rows, err := q.db.Query(ctx, claimDueScheduledHistory, dbType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AwxHistory
+	for rows.Next() {
+		var i AwxHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.AwxJobID,
+			&i.AwxTemplateName,
+			&i.AwxTemplateID,
+			&i.ActionType,
+			&i.Status,
+			&i.InstanceName,
+			&i.Username,
+			&i.ExtraVars,
+			&i.AwxStatus,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const consumeConfirmationToken = `-- name: ConsumeConfirmationToken :execrows
+
+DELETE FROM dbaas_confirmation_tokens
+WHERE nonce = $1 AND customer_id = $2 AND instance_name = $3 AND expires_at > NOW()
+`
+
+type ConsumeConfirmationTokenParams struct {
+	Nonce        string
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) ConsumeConfirmationToken(ctx context.Context, arg ConsumeConfirmationTokenParams) (int64, error) {
+	result, err := q.db.Exec(ctx, consumeConfirmationToken, arg.Nonce, arg.CustomerID, arg.InstanceName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countActiveJobsByCustomer = `-- name: CountActiveJobsByCustomer :one
+SELECT COUNT(*) FROM awx_history
+WHERE customer_id = $1 AND status IN ('pending', 'running') AND awx_job_id IS NOT NULL
+`
+
+func (q *Queries) CountActiveJobsByCustomer(ctx context.Context, customerID string) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveJobsByCustomer, customerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createBackup = `-- name: CreateBackup :one
+
+INSERT INTO db_backups (
+  customer_id, instance_name, awx_history_id, awx_job_id, trigger_type, status
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+) RETURNING id, customer_id, instance_name, awx_history_id, awx_job_id, trigger_type, status, backup_id, size_bytes, backup_at, created_at, completed_at
+`
+
+type CreateBackupParams struct {
+	CustomerID   string
+	InstanceName string
+	AwxHistoryID pgtype.Int4
+	AwxJobID     pgtype.Int8
+	TriggerType  string
+	Status       StatusEnum
+}
+
+// Backups Queries
+func (q *Queries) CreateBackup(ctx context.Context, arg CreateBackupParams) (DbBackup, error) {
+	row := q.db.QueryRow(ctx, createBackup,
+		arg.CustomerID,
+		arg.InstanceName,
+		arg.AwxHistoryID,
+		arg.AwxJobID,
+		arg.TriggerType,
+		arg.Status,
+	)
+	var i DbBackup
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.AwxHistoryID,
+		&i.AwxJobID,
+		&i.TriggerType,
+		&i.Status,
+		&i.BackupID,
+		&i.SizeBytes,
+		&i.BackupAt,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const createConfirmationToken = `-- name: CreateConfirmationToken :exec
+INSERT INTO dbaas_confirmation_tokens (nonce, customer_id, instance_name, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateConfirmationTokenParams struct {
+	Nonce        string
+	CustomerID   string
+	InstanceName string
+	ExpiresAt    pgtype.Timestamptz
+}
+
+func (q *Queries) CreateConfirmationToken(ctx context.Context, arg CreateConfirmationTokenParams) error {
+	_, err := q.db.Exec(ctx, createConfirmationToken,
+		arg.Nonce,
+		arg.CustomerID,
+		arg.InstanceName,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
 const createDBInstance = `-- name: CreateDBInstance :one
 INSERT INTO db_instances (
   customer_id, db_type, version, host, port, username, 
-  status, created_by
+  status, instance_name, created_by, storage_gb
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8
-) RETURNING id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10
+) RETURNING id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb
 `
 
 type CreateDBInstanceParams struct {
-	CustomerID string
-	DbType     string
-	Version    pgtype.Text
-	Host       pgtype.Text
-	Port       pgtype.Int4
-	Username   pgtype.Text
-	Status     StatusEnum
-	CreatedBy  string
+	CustomerID   string
+	DbType       string
+	Version      pgtype.Text
+	Host         pgtype.Text
+	Port         pgtype.Int4
+	Username     pgtype.Text
+	Status       StatusEnum
+	InstanceName string
+	CreatedBy    string
+	StorageGb    int32
 }
 
 func (q *Queries) CreateDBInstance(ctx context.Context, arg CreateDBInstanceParams) (DbInstance, error) {
@@ -40,7 +323,9 @@ func (q *Queries) CreateDBInstance(ctx context.Context, arg CreateDBInstancePara
 		arg.Port,
 		arg.Username,
 		arg.Status,
+		arg.InstanceName,
 		arg.CreatedBy,
+		arg.StorageGb,
 	)
 	var i DbInstance
 	err := row.Scan(
@@ -57,6 +342,7 @@ func (q *Queries) CreateDBInstance(ctx context.Context, arg CreateDBInstancePara
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.StorageGb,
 	)
 	return i, err
 }
@@ -65,10 +351,10 @@ const createHistory = `-- name: CreateHistory :one
 INSERT INTO awx_history (
   customer_id, awx_job_id, awx_template_name, awx_template_id, 
   action_type, status, instance_name, username, extra_vars, 
-  awx_status, created_by
+  awx_status, created_by, parent_history_id, awx_job_type, scheduled_at
 ) VALUES (
-  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
-) RETURNING id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+) RETURNING id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at
 `
 
 type CreateHistoryParams struct {
@@ -83,6 +369,9 @@ type CreateHistoryParams struct {
 	ExtraVars       []byte
 	AwxStatus       NullAwxStatusEnum
 	CreatedBy       string
+	ParentHistoryID pgtype.Int4
+	AwxJobType      string
+	ScheduledAt     pgtype.Timestamptz
 }
 
 func (q *Queries) CreateHistory(ctx context.Context, arg CreateHistoryParams) (AwxHistory, error) {
@@ -98,6 +387,9 @@ func (q *Queries) CreateHistory(ctx context.Context, arg CreateHistoryParams) (A
 		arg.ExtraVars,
 		arg.AwxStatus,
 		arg.CreatedBy,
+		arg.ParentHistoryID,
+		arg.AwxJobType,
+		arg.ScheduledAt,
 	)
 	var i AwxHistory
 	err := row.Scan(
@@ -117,10 +409,22 @@ func (q *Queries) CreateHistory(ctx context.Context, arg CreateHistoryParams) (A
 		&i.CreatedAt,
 		&i.CompletedAt,
 		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
 	)
 	return i, err
 }
 
+const deleteExpiredConfirmationTokens = `-- name: DeleteExpiredConfirmationTokens :exec
+DELETE FROM dbaas_confirmation_tokens WHERE expires_at <= NOW()
+`
+
+func (q *Queries) DeleteExpiredConfirmationTokens(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteExpiredConfirmationTokens)
+	return err
+}
+
 const deleteHistory = `-- name: DeleteHistory :exec
 DELETE FROM awx_history WHERE id = $1
 `
@@ -130,8 +434,74 @@ func (q *Queries) DeleteHistory(ctx context.Context, id int32) error {
 	return err
 }
 
+const deleteMaintenanceWindow = `-- name: DeleteMaintenanceWindow :execrows
+DELETE FROM db_maintenance_windows
+WHERE customer_id = $1 AND instance_name = $2
+`
+
+type DeleteMaintenanceWindowParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) DeleteMaintenanceWindow(ctx context.Context, arg DeleteMaintenanceWindowParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteMaintenanceWindow, arg.CustomerID, arg.InstanceName)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteSecret = `-- name: DeleteSecret :exec
+DELETE FROM dbaas_secrets WHERE secret_key = $1
+`
+
+func (q *Queries) DeleteSecret(ctx context.Context, secretKey string) error {
+	_, err := q.db.Exec(ctx, deleteSecret, secretKey)
+	return err
+}
+
+const getActiveHistoryByTemplate = `-- name: GetActiveHistoryByTemplate :one
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1 AND awx_template_name = $2 AND status IN ('pending', 'running')
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetActiveHistoryByTemplateParams struct {
+	CustomerID      string
+	AwxTemplateName pgtype.Text
+}
+
+func (q *Queries) GetActiveHistoryByTemplate(ctx context.Context, arg GetActiveHistoryByTemplateParams) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, getActiveHistoryByTemplate, arg.CustomerID, arg.AwxTemplateName)
+	var i AwxHistory
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.AwxJobID,
+		&i.AwxTemplateName,
+		&i.AwxTemplateID,
+		&i.ActionType,
+		&i.Status,
+		&i.InstanceName,
+		&i.Username,
+		&i.ExtraVars,
+		&i.AwxStatus,
+		&i.ErrorMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
 const getActiveJobs = `-- name: GetActiveJobs :many
-SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at FROM awx_history
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
 WHERE status IN ('pending', 'running')
 ORDER BY created_at DESC
 `
@@ -162,6 +532,9 @@ func (q *Queries) GetActiveJobs(ctx context.Context) ([]AwxHistory, error) {
 			&i.CreatedAt,
 			&i.CompletedAt,
 			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -173,9 +546,109 @@ func (q *Queries) GetActiveJobs(ctx context.Context) ([]AwxHistory, error) {
 	return items, nil
 }
 
+const getBackupByBackupID = `-- name: GetBackupByBackupID :one
+SELECT id, customer_id, instance_name, awx_history_id, awx_job_id, trigger_type, status, backup_id, size_bytes, backup_at, created_at, completed_at FROM db_backups
+WHERE customer_id = $1 AND instance_name = $2 AND backup_id = $3
+LIMIT 1
+`
+
+type GetBackupByBackupIDParams struct {
+	CustomerID   string
+	InstanceName string
+	BackupID     pgtype.Text
+}
+
+func (q *Queries) GetBackupByBackupID(ctx context.Context, arg GetBackupByBackupIDParams) (DbBackup, error) {
+	row := q.db.QueryRow(ctx, getBackupByBackupID, arg.CustomerID, arg.InstanceName, arg.BackupID)
+	var i DbBackup
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.AwxHistoryID,
+		&i.AwxJobID,
+		&i.TriggerType,
+		&i.Status,
+		&i.BackupID,
+		&i.SizeBytes,
+		&i.BackupAt,
+		&i.CreatedAt,
+		&i.CompletedAt,
+	)
+	return i, err
+}
+
+const getBackupSchedule = `-- name: GetBackupSchedule :one
+SELECT id, customer_id, instance_name, interval_hours, enabled, next_run_at, last_run_at, created_by, created_at, updated_at FROM db_backup_schedules
+WHERE customer_id = $1 AND instance_name = $2 LIMIT 1
+`
+
+type GetBackupScheduleParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) GetBackupSchedule(ctx context.Context, arg GetBackupScheduleParams) (DbBackupSchedule, error) {
+	row := q.db.QueryRow(ctx, getBackupSchedule, arg.CustomerID, arg.InstanceName)
+	var i DbBackupSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.IntervalHours,
+		&i.Enabled,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getCustomerInstanceUsage = `-- name: GetCustomerInstanceUsage :one
+
+SELECT COUNT(*) AS instance_count, COALESCE(SUM(storage_gb), 0)::BIGINT AS storage_gb
+FROM db_instances
+WHERE customer_id = $1 AND deleted_at IS NULL AND status <> 'failed'
+`
+
+type GetCustomerInstanceUsageRow struct {
+	InstanceCount int64
+	StorageGb     int64
+}
+
+// Les instances en échec ne consomment pas de quota
+func (q *Queries) GetCustomerInstanceUsage(ctx context.Context, customerID string) (GetCustomerInstanceUsageRow, error) {
+	row := q.db.QueryRow(ctx, getCustomerInstanceUsage, customerID)
+	var i GetCustomerInstanceUsageRow
+	err := row.Scan(&i.InstanceCount, &i.StorageGb)
+	return i, err
+}
+
+const getCustomerQuota = `-- name: GetCustomerQuota :one
+
+SELECT customer_id, max_instances, max_storage_gb, max_concurrent_jobs, updated_by, updated_at FROM dbaas_quotas WHERE customer_id = $1
+`
+
+// Quotas Queries
+func (q *Queries) GetCustomerQuota(ctx context.Context, customerID string) (DbaasQuota, error) {
+	row := q.db.QueryRow(ctx, getCustomerQuota, customerID)
+	var i DbaasQuota
+	err := row.Scan(
+		&i.CustomerID,
+		&i.MaxInstances,
+		&i.MaxStorageGb,
+		&i.MaxConcurrentJobs,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getDBInstance = `-- name: GetDBInstance :one
 
-SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at FROM db_instances
+SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb FROM db_instances
 WHERE id = $1 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -197,12 +670,13 @@ func (q *Queries) GetDBInstance(ctx context.Context, id int32) (DbInstance, erro
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.StorageGb,
 	)
 	return i, err
 }
 
 const getDBInstanceByName = `-- name: GetDBInstanceByName :one
-SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at FROM db_instances
+SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb FROM db_instances
 WHERE instance_name = $1 AND customer_id = $2 AND deleted_at IS NULL LIMIT 1
 `
 
@@ -228,12 +702,13 @@ func (q *Queries) GetDBInstanceByName(ctx context.Context, arg GetDBInstanceByNa
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.StorageGb,
 	)
 	return i, err
 }
 
 const getDBInstancesByCustomer = `-- name: GetDBInstancesByCustomer :many
-SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at FROM db_instances
+SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb FROM db_instances
 WHERE customer_id = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -261,6 +736,53 @@ func (q *Queries) GetDBInstancesByCustomer(ctx context.Context, customerID strin
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
+			&i.StorageGb,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getDBInstancesByCustomerAndType = `-- name: GetDBInstancesByCustomerAndType :many
+SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb FROM db_instances
+WHERE customer_id = $1 AND db_type = $2 AND deleted_at IS NULL
+ORDER BY created_at DESC
+`
+
+type GetDBInstancesByCustomerAndTypeParams struct {
+	CustomerID string
+	DbType     string
+}
+
+func (q *Queries) GetDBInstancesByCustomerAndType(ctx context.Context, arg GetDBInstancesByCustomerAndTypeParams) ([]DbInstance, error) {
+	rows, err := q.db.Query(ctx, getDBInstancesByCustomerAndType, arg.CustomerID, arg.DbType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DbInstance
+	for rows.Next() {
+		var i DbInstance
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.DbType,
+			&i.Version,
+			&i.Host,
+			&i.Port,
+			&i.Username,
+			&i.Status,
+			&i.InstanceName,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+			&i.StorageGb,
 		); err != nil {
 			return nil, err
 		}
@@ -273,7 +795,7 @@ func (q *Queries) GetDBInstancesByCustomer(ctx context.Context, customerID strin
 }
 
 const getDBInstancesByStatus = `-- name: GetDBInstancesByStatus :many
-SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at FROM db_instances
+SELECT id, customer_id, db_type, version, host, port, username, status, instance_name, created_by, created_at, updated_at, deleted_at, storage_gb FROM db_instances
 WHERE status = $1 AND deleted_at IS NULL
 ORDER BY created_at DESC
 `
@@ -300,7 +822,137 @@ func (q *Queries) GetDBInstancesByStatus(ctx context.Context, status StatusEnum)
 			&i.CreatedBy,
 			&i.CreatedAt,
 			&i.UpdatedAt,
-			&i.DeletedAt,
+			&i.DeletedAt,
+			&i.StorageGb,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHistory = `-- name: GetHistory :one
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetHistory(ctx context.Context, id int32) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, getHistory, id)
+	var i AwxHistory
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.AwxJobID,
+		&i.AwxTemplateName,
+		&i.AwxTemplateID,
+		&i.ActionType,
+		&i.Status,
+		&i.InstanceName,
+		&i.Username,
+		&i.ExtraVars,
+		&i.AwxStatus,
+		&i.ErrorMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const getHistoryByCustomer = `-- name: GetHistoryByCustomer :many
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) GetHistoryByCustomer(ctx context.Context, customerID string) ([]AwxHistory, error) {
+	rows, err := q.db.Query(ctx, getHistoryByCustomer, customerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AwxHistory
+	for rows.Next() {
+		var i AwxHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.AwxJobID,
+			&i.AwxTemplateName,
+			&i.AwxTemplateID,
+			&i.ActionType,
+			&i.Status,
+			&i.InstanceName,
+			&i.Username,
+			&i.ExtraVars,
+			&i.AwxStatus,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHistoryByInstance = `-- name: GetHistoryByInstance :many
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
+ORDER BY created_at DESC
+`
+
+type GetHistoryByInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) GetHistoryByInstance(ctx context.Context, arg GetHistoryByInstanceParams) ([]AwxHistory, error) {
+	rows, err := q.db.Query(ctx, getHistoryByInstance, arg.CustomerID, arg.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AwxHistory
+	for rows.Next() {
+		var i AwxHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.AwxJobID,
+			&i.AwxTemplateName,
+			&i.AwxTemplateID,
+			&i.ActionType,
+			&i.Status,
+			&i.InstanceName,
+			&i.Username,
+			&i.ExtraVars,
+			&i.AwxStatus,
+			&i.ErrorMessage,
+			&i.CreatedBy,
+			&i.CreatedAt,
+			&i.CompletedAt,
+			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -312,13 +964,13 @@ func (q *Queries) GetDBInstancesByStatus(ctx context.Context, status StatusEnum)
 	return items, nil
 }
 
-const getHistory = `-- name: GetHistory :one
-SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at FROM awx_history
-WHERE id = $1 LIMIT 1
+const getHistoryByJobID = `-- name: GetHistoryByJobID :one
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE awx_job_id = $1 LIMIT 1
 `
 
-func (q *Queries) GetHistory(ctx context.Context, id int32) (AwxHistory, error) {
-	row := q.db.QueryRow(ctx, getHistory, id)
+func (q *Queries) GetHistoryByJobID(ctx context.Context, awxJobID pgtype.Int8) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, getHistoryByJobID, awxJobID)
 	var i AwxHistory
 	err := row.Scan(
 		&i.ID,
@@ -337,18 +989,21 @@ func (q *Queries) GetHistory(ctx context.Context, id int32) (AwxHistory, error)
 		&i.CreatedAt,
 		&i.CompletedAt,
 		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
 	)
 	return i, err
 }
 
-const getHistoryByCustomer = `-- name: GetHistoryByCustomer :many
-SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at FROM awx_history
-WHERE customer_id = $1
+const getHistoryByStatus = `-- name: GetHistoryByStatus :many
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE status = $1
 ORDER BY created_at DESC
 `
 
-func (q *Queries) GetHistoryByCustomer(ctx context.Context, customerID string) ([]AwxHistory, error) {
-	rows, err := q.db.Query(ctx, getHistoryByCustomer, customerID)
+func (q *Queries) GetHistoryByStatus(ctx context.Context, status StatusEnum) ([]AwxHistory, error) {
+	rows, err := q.db.Query(ctx, getHistoryByStatus, status)
 	if err != nil {
 		return nil, err
 	}
@@ -373,6 +1028,9 @@ func (q *Queries) GetHistoryByCustomer(ctx context.Context, customerID string) (
 			&i.CreatedAt,
 			&i.CompletedAt,
 			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -384,13 +1042,21 @@ func (q *Queries) GetHistoryByCustomer(ctx context.Context, customerID string) (
 	return items, nil
 }
 
-const getHistoryByJobID = `-- name: GetHistoryByJobID :one
-SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at FROM awx_history
-WHERE awx_job_id = $1 LIMIT 1
+const getLatestCredentialHistory = `-- name: GetLatestCredentialHistory :one
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
+  AND action_type IN ('create', 'rotate', 'clone')
+ORDER BY created_at DESC
+LIMIT 1
 `
 
-func (q *Queries) GetHistoryByJobID(ctx context.Context, awxJobID pgtype.Int8) (AwxHistory, error) {
-	row := q.db.QueryRow(ctx, getHistoryByJobID, awxJobID)
+type GetLatestCredentialHistoryParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) GetLatestCredentialHistory(ctx context.Context, arg GetLatestCredentialHistoryParams) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, getLatestCredentialHistory, arg.CustomerID, arg.InstanceName)
 	var i AwxHistory
 	err := row.Scan(
 		&i.ID,
@@ -409,18 +1075,157 @@ func (q *Queries) GetHistoryByJobID(ctx context.Context, awxJobID pgtype.Int8) (
 		&i.CreatedAt,
 		&i.CompletedAt,
 		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
 	)
 	return i, err
 }
 
-const getHistoryByStatus = `-- name: GetHistoryByStatus :many
-SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at FROM awx_history
-WHERE status = $1
+const getLatestHistoryByInstance = `-- name: GetLatestHistoryByInstance :one
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2
 ORDER BY created_at DESC
+LIMIT 1
 `
 
-func (q *Queries) GetHistoryByStatus(ctx context.Context, status StatusEnum) ([]AwxHistory, error) {
-	rows, err := q.db.Query(ctx, getHistoryByStatus, status)
+type GetLatestHistoryByInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) GetLatestHistoryByInstance(ctx context.Context, arg GetLatestHistoryByInstanceParams) (AwxHistory, error) {
+	row := q.db.QueryRow(ctx, getLatestHistoryByInstance, arg.CustomerID, arg.InstanceName)
+	var i AwxHistory
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.AwxJobID,
+		&i.AwxTemplateName,
+		&i.AwxTemplateID,
+		&i.ActionType,
+		&i.Status,
+		&i.InstanceName,
+		&i.Username,
+		&i.ExtraVars,
+		&i.AwxStatus,
+		&i.ErrorMessage,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.CompletedAt,
+		&i.UpdatedAt,
+		&i.ParentHistoryID,
+		&i.AwxJobType,
+		&i.ScheduledAt,
+	)
+	return i, err
+}
+
+const getMaintenanceWindow = `-- name: GetMaintenanceWindow :one
+SELECT id, customer_id, instance_name, days_of_week, start_time, duration_minutes, timezone, created_by, created_at, updated_at FROM db_maintenance_windows
+WHERE customer_id = $1 AND instance_name = $2
+`
+
+type GetMaintenanceWindowParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) GetMaintenanceWindow(ctx context.Context, arg GetMaintenanceWindowParams) (DbMaintenanceWindow, error) {
+	row := q.db.QueryRow(ctx, getMaintenanceWindow, arg.CustomerID, arg.InstanceName)
+	var i DbMaintenanceWindow
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.DaysOfWeek,
+		&i.StartTime,
+		&i.DurationMinutes,
+		&i.Timezone,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const hasActiveOperationOnInstance = `-- name: HasActiveOperationOnInstance :one
+SELECT EXISTS (
+  SELECT 1 FROM awx_history
+  WHERE customer_id = $1 AND instance_name = $2 AND status IN ('pending', 'running')
+    AND awx_job_id IS NOT NULL
+)
+`
+
+type HasActiveOperationOnInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) HasActiveOperationOnInstance(ctx context.Context, arg HasActiveOperationOnInstanceParams) (bool, error) {
+	row := q.db.QueryRow(ctx, hasActiveOperationOnInstance, arg.CustomerID, arg.InstanceName)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listBackupsByInstance = `-- name: ListBackupsByInstance :many
+SELECT id, customer_id, instance_name, awx_history_id, awx_job_id, trigger_type, status, backup_id, size_bytes, backup_at, created_at, completed_at FROM db_backups
+WHERE customer_id = $1 AND instance_name = $2
+ORDER BY created_at DESC
+`
+
+type ListBackupsByInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) ListBackupsByInstance(ctx context.Context, arg ListBackupsByInstanceParams) ([]DbBackup, error) {
+	rows, err := q.db.Query(ctx, listBackupsByInstance, arg.CustomerID, arg.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DbBackup
+	for rows.Next() {
+		var i DbBackup
+		if err := rows.Scan(
+			&i.ID,
+			&i.CustomerID,
+			&i.InstanceName,
+			&i.AwxHistoryID,
+			&i.AwxJobID,
+			&i.TriggerType,
+			&i.Status,
+			&i.BackupID,
+			&i.SizeBytes,
+			&i.BackupAt,
+			&i.CreatedAt,
+			&i.CompletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listScheduledHistoryByInstance = `-- name: ListScheduledHistoryByInstance :many
+SELECT id, customer_id, awx_job_id, awx_template_name, awx_template_id, action_type, status, instance_name, username, extra_vars, awx_status, error_message, created_by, created_at, completed_at, updated_at, parent_history_id, awx_job_type, scheduled_at FROM awx_history
+WHERE customer_id = $1 AND instance_name = $2 AND status = 'scheduled'
+ORDER BY scheduled_at
+`
+
+type ListScheduledHistoryByInstanceParams struct {
+	CustomerID   string
+	InstanceName string
+}
+
+func (q *Queries) ListScheduledHistoryByInstance(ctx context.Context, arg ListScheduledHistoryByInstanceParams) ([]AwxHistory, error) {
+	rows, err := q.db.Query(ctx, listScheduledHistoryByInstance, arg.CustomerID, arg.InstanceName)
 	if err != nil {
 		return nil, err
 	}
@@ -445,6 +1250,9 @@ func (q *Queries) GetHistoryByStatus(ctx context.Context, status StatusEnum) ([]
 			&i.CreatedAt,
 			&i.CompletedAt,
 			&i.UpdatedAt,
+			&i.ParentHistoryID,
+			&i.AwxJobType,
+			&i.ScheduledAt,
 		); err != nil {
 			return nil, err
 		}
@@ -456,6 +1264,74 @@ func (q *Queries) GetHistoryByStatus(ctx context.Context, status StatusEnum) ([]
 	return items, nil
 }
 
+const markScheduledHistoryLaunched = `-- name: MarkScheduledHistoryLaunched :exec
+UPDATE awx_history
+SET status = 'running', awx_job_id = $2, awx_template_id = $3, awx_job_type = $4, updated_at = NOW()
+WHERE id = $1
+`
+
+type MarkScheduledHistoryLaunchedParams struct {
+	ID            int32
+	AwxJobID      pgtype.Int8
+	AwxTemplateID pgtype.Int4
+	AwxJobType    string
+}
+
+func (q *Queries) MarkScheduledHistoryLaunched(ctx context.Context, arg MarkScheduledHistoryLaunchedParams) error {
+	_, err := q.db.Exec(ctx, markScheduledHistoryLaunched,
+		arg.ID,
+		arg.AwxJobID,
+		arg.AwxTemplateID,
+		arg.AwxJobType,
+	)
+	return err
+}
+
+const releaseInstanceLock = `-- name: ReleaseInstanceLock :exec
+DELETE FROM db_instance_locks
+WHERE customer_id = $1 AND instance_name = $2 AND locked_until = $3
+`
+
+type ReleaseInstanceLockParams struct {
+	CustomerID   string
+	InstanceName string
+	LockedUntil  pgtype.Timestamptz
+}
+
+func (q *Queries) ReleaseInstanceLock(ctx context.Context, arg ReleaseInstanceLockParams) error {
+	_, err := q.db.Exec(ctx, releaseInstanceLock, arg.CustomerID, arg.InstanceName, arg.LockedUntil)
+	return err
+}
+
+const rescheduleHistory = `-- name: RescheduleHistory :exec
+UPDATE awx_history
+SET status = 'scheduled', scheduled_at = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type RescheduleHistoryParams struct {
+	ID          int32
+	ScheduledAt pgtype.Timestamptz
+}
+
+func (q *Queries) RescheduleHistory(ctx context.Context, arg RescheduleHistoryParams) error {
+	_, err := q.db.Exec(ctx, rescheduleHistory, arg.ID, arg.ScheduledAt)
+	return err
+}
+
+const secretExists = `-- name: SecretExists :one
+SELECT EXISTS (
+  SELECT 1 FROM dbaas_secrets WHERE secret_key = $1
+)
+`
+
+func (q *Queries) SecretExists(ctx context.Context, secretKey string) (bool, error) {
+	row := q.db.QueryRow(ctx, secretExists, secretKey)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
 const softDeleteDBInstance = `-- name: SoftDeleteDBInstance :exec
 UPDATE db_instances 
 SET deleted_at = NOW(), updated_at = NOW()
@@ -467,6 +1343,44 @@ func (q *Queries) SoftDeleteDBInstance(ctx context.Context, id int32) error {
 	return err
 }
 
+const takeSecret = `-- name: TakeSecret :one
+DELETE FROM dbaas_secrets
+WHERE secret_key = $1
+RETURNING encrypted_value
+`
+
+func (q *Queries) TakeSecret(ctx context.Context, secretKey string) ([]byte, error) {
+	row := q.db.QueryRow(ctx, takeSecret, secretKey)
+	var encrypted_value []byte
+	err := row.Scan(&encrypted_value)
+	return encrypted_value, err
+}
+
+const updateBackupCompletionByHistoryID = `-- name: UpdateBackupCompletionByHistoryID :exec
+UPDATE db_backups
+SET status = $2, backup_id = $3, size_bytes = $4, backup_at = $5, completed_at = NOW()
+WHERE awx_history_id = $1
+`
+
+type UpdateBackupCompletionByHistoryIDParams struct {
+	AwxHistoryID pgtype.Int4
+	Status       StatusEnum
+	BackupID     pgtype.Text
+	SizeBytes    pgtype.Int8
+	BackupAt     pgtype.Timestamptz
+}
+
+func (q *Queries) UpdateBackupCompletionByHistoryID(ctx context.Context, arg UpdateBackupCompletionByHistoryIDParams) error {
+	_, err := q.db.Exec(ctx, updateBackupCompletionByHistoryID,
+		arg.AwxHistoryID,
+		arg.Status,
+		arg.BackupID,
+		arg.SizeBytes,
+		arg.BackupAt,
+	)
+	return err
+}
+
 const updateDBInstanceDetails = `-- name: UpdateDBInstanceDetails :exec
 UPDATE db_instances 
 SET host = $2, port = $3, version = $4, updated_at = NOW()
@@ -506,6 +1420,38 @@ func (q *Queries) UpdateDBInstanceStatus(ctx context.Context, arg UpdateDBInstan
 	return err
 }
 
+const updateDBInstanceStorage = `-- name: UpdateDBInstanceStorage :exec
+UPDATE db_instances 
+SET storage_gb = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateDBInstanceStorageParams struct {
+	ID        int32
+	StorageGb int32
+}
+
+func (q *Queries) UpdateDBInstanceStorage(ctx context.Context, arg UpdateDBInstanceStorageParams) error {
+	_, err := q.db.Exec(ctx, updateDBInstanceStorage, arg.ID, arg.StorageGb)
+	return err
+}
+
+const updateDBInstanceVersion = `-- name: UpdateDBInstanceVersion :exec
+UPDATE db_instances 
+SET version = $2, updated_at = NOW()
+WHERE id = $1
+`
+
+type UpdateDBInstanceVersionParams struct {
+	ID      int32
+	Version pgtype.Text
+}
+
+func (q *Queries) UpdateDBInstanceVersion(ctx context.Context, arg UpdateDBInstanceVersionParams) error {
+	_, err := q.db.Exec(ctx, updateDBInstanceVersion, arg.ID, arg.Version)
+	return err
+}
+
 const updateHistoryByJobID = `-- name: UpdateHistoryByJobID :exec
 UPDATE awx_history 
 SET status = $2, awx_status = $3, updated_at = NOW()
@@ -563,3 +1509,163 @@ func (q *Queries) UpdateHistoryStatus(ctx context.Context, arg UpdateHistoryStat
 	_, err := q.db.Exec(ctx, updateHistoryStatus, arg.ID, arg.Status, arg.AwxStatus)
 	return err
 }
+
+const upsertBackupSchedule = `-- name: UpsertBackupSchedule :one
+INSERT INTO db_backup_schedules (
+  customer_id, instance_name, interval_hours, enabled, next_run_at, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6
+)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET interval_hours = EXCLUDED.interval_hours, enabled = EXCLUDED.enabled,
+    next_run_at = EXCLUDED.next_run_at, updated_at = NOW()
+RETURNING id, customer_id, instance_name, interval_hours, enabled, next_run_at, last_run_at, created_by, created_at, updated_at
+`
+
+type UpsertBackupScheduleParams struct {
+	CustomerID    string
+	InstanceName  string
+	IntervalHours int32
+	Enabled       bool
+	NextRunAt     pgtype.Timestamptz
+	CreatedBy     string
+}
+
+func (q *Queries) UpsertBackupSchedule(ctx context.Context, arg UpsertBackupScheduleParams) (DbBackupSchedule, error) {
+	row := q.db.QueryRow(ctx, upsertBackupSchedule,
+		arg.CustomerID,
+		arg.InstanceName,
+		arg.IntervalHours,
+		arg.Enabled,
+		arg.NextRunAt,
+		arg.CreatedBy,
+	)
+	var i DbBackupSchedule
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.IntervalHours,
+		&i.Enabled,
+		&i.NextRunAt,
+		&i.LastRunAt,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertCustomerQuota = `-- name: UpsertCustomerQuota :one
+INSERT INTO dbaas_quotas (
+  customer_id, max_instances, max_storage_gb, max_concurrent_jobs, updated_by
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (customer_id) DO UPDATE
+SET max_instances = EXCLUDED.max_instances,
+    max_storage_gb = EXCLUDED.max_storage_gb,
+    max_concurrent_jobs = EXCLUDED.max_concurrent_jobs,
+    updated_by = EXCLUDED.updated_by,
+    updated_at = NOW()
+RETURNING customer_id, max_instances, max_storage_gb, max_concurrent_jobs, updated_by, updated_at
+`
+
+type UpsertCustomerQuotaParams struct {
+	CustomerID        string
+	MaxInstances      pgtype.Int4
+	MaxStorageGb      pgtype.Int4
+	MaxConcurrentJobs pgtype.Int4
+	UpdatedBy         string
+}
+
+func (q *Queries) UpsertCustomerQuota(ctx context.Context, arg UpsertCustomerQuotaParams) (DbaasQuota, error) {
+	row := q.db.QueryRow(ctx, upsertCustomerQuota,
+		arg.CustomerID,
+		arg.MaxInstances,
+		arg.MaxStorageGb,
+		arg.MaxConcurrentJobs,
+		arg.UpdatedBy,
+	)
+	var i DbaasQuota
+	err := row.Scan(
+		&i.CustomerID,
+		&i.MaxInstances,
+		&i.MaxStorageGb,
+		&i.MaxConcurrentJobs,
+		&i.UpdatedBy,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertMaintenanceWindow = `-- name: UpsertMaintenanceWindow :one
+INSERT INTO db_maintenance_windows (
+  customer_id, instance_name, days_of_week, start_time, duration_minutes, timezone, created_by
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7
+)
+ON CONFLICT (customer_id, instance_name) DO UPDATE
+SET days_of_week = EXCLUDED.days_of_week,
+    start_time = EXCLUDED.start_time,
+    duration_minutes = EXCLUDED.duration_minutes,
+    timezone = EXCLUDED.timezone,
+    created_by = EXCLUDED.created_by,
+    updated_at = NOW()
+RETURNING id, customer_id, instance_name, days_of_week, start_time, duration_minutes, timezone, created_by, created_at, updated_at
+`
+
+type UpsertMaintenanceWindowParams struct {
+	CustomerID      string
+	InstanceName    string
+	DaysOfWeek      []int16
+	StartTime       pgtype.Time
+	DurationMinutes int32
+	Timezone        string
+	CreatedBy       string
+}
+
+func (q *Queries) UpsertMaintenanceWindow(ctx context.Context, arg UpsertMaintenanceWindowParams) (DbMaintenanceWindow, error) {
+	row := q.db.QueryRow(ctx, upsertMaintenanceWindow,
+		arg.CustomerID,
+		arg.InstanceName,
+		arg.DaysOfWeek,
+		arg.StartTime,
+		arg.DurationMinutes,
+		arg.Timezone,
+		arg.CreatedBy,
+	)
+	var i DbMaintenanceWindow
+	err := row.Scan(
+		&i.ID,
+		&i.CustomerID,
+		&i.InstanceName,
+		&i.DaysOfWeek,
+		&i.StartTime,
+		&i.DurationMinutes,
+		&i.Timezone,
+		&i.CreatedBy,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const upsertSecret = `-- name: UpsertSecret :exec
+
+INSERT INTO dbaas_secrets (secret_key, encrypted_value)
+VALUES ($1, $2)
+ON CONFLICT (secret_key) DO UPDATE
+SET encrypted_value = EXCLUDED.encrypted_value, updated_at = NOW()
+`
+
+type UpsertSecretParams struct {
+	SecretKey      string
+	EncryptedValue []byte
+}
+
+// Secrets Queries
+func (q *Queries) UpsertSecret(ctx context.Context, arg UpsertSecretParams) error {
+	_, err := q.db.Exec(ctx, upsertSecret, arg.SecretKey, arg.EncryptedValue)
+	return err
+}
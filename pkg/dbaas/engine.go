@@ -0,0 +1,69 @@
+package dbaas
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	"github.com/Gskill75/api2/pkg/config"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	handler "github.com/Gskill75/api2/pkg/dbaas/handler/engine"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+// EngineSolution expose un moteur DBaaS générique (mysql, redis, mongodb...) décrit dans dbaas.engines
+type EngineSolution struct {
+	engine            config.DbaasEngine
+	service           *service.Service
+	schedulerInterval time.Duration
+}
+
+func NewEngineSolution(engine config.DbaasEngine, quotas config.DbaasQuotas, schedulerInterval time.Duration, awxclient *awxclient.Client, queries *db.Queries, secrets secret.Store) *EngineSolution {
+	return &EngineSolution{
+		engine:            engine,
+		service:           service.NewService(engine, quotas, awxclient, queries, secrets),
+		schedulerInterval: schedulerInterval,
+	}
+}
+
+// NewEngineSolutions crée une solution par moteur configuré
+func NewEngineSolutions(cfg *config.Config, awxclient *awxclient.Client, queries *db.Queries, secrets secret.Store) []*EngineSolution {
+	engines := make([]*EngineSolution, 0, len(cfg.Dbaas.Engines))
+	for _, engine := range cfg.Dbaas.Engines {
+		engines = append(engines, NewEngineSolution(engine, cfg.Dbaas.Quotas, time.Duration(cfg.Dbaas.SchedulerInterval)*time.Second, awxclient, queries, secrets))
+	}
+	return engines
+}
+
+// RunScheduler lance les opérations planifiées dans les fenêtres de maintenance jusqu'à l'arrêt du serveur
+func (s *EngineSolution) RunScheduler(ctx context.Context) {
+	s.service.RunScheduler(ctx, s.schedulerInterval)
+}
+
+func (s *EngineSolution) Name() string {
+	return s.engine.Name
+}
+func (s *EngineSolution) Version() string {
+	if s.engine.Version == "" {
+		return "v1"
+	}
+	return s.engine.Version
+}
+
+func (s *EngineSolution) Endpoint(rg *gin.RouterGroup) {
+	rg.POST("/instances", handler.ProvisionInstanceHandler(s.service))
+	rg.GET("/instances", handler.ListInstancesHandler(s.service))
+	rg.GET("/instances/:name", handler.GetInstanceHandler(s.service))
+	rg.DELETE("/instances/:name", handler.DeleteInstanceHandler(s.service))
+	rg.GET("/instances/:name/history", handler.GetInstanceHistoryHandler(s.service))
+	rg.GET("/instances/:name/credentials", handler.GetInstanceCredentialsHandler(s.service))
+	rg.GET("/instances/:name/maintenance-window", handler.GetMaintenanceWindowHandler(s.service))
+	rg.PUT("/instances/:name/maintenance-window", handler.SetMaintenanceWindowHandler(s.service))
+	rg.DELETE("/instances/:name/maintenance-window", handler.DeleteMaintenanceWindowHandler(s.service))
+	rg.GET("/instances/:name/pending-operations", handler.ListPendingOperationsHandler(s.service))
+	rg.DELETE("/instances/:name/pending-operations/:id", handler.CancelPendingOperationHandler(s.service))
+	rg.GET("/jobs/:job_id/status", handler.GetJobStatusHandler(s.service))
+	rg.GET("/quota", handler.GetQuotaHandler(s.service))
+}
@@ -0,0 +1,356 @@
+package handler_engine
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// ProvisionInstanceHandler godoc
+// @Summary     Provision an instance
+// @Description Launches the AWX provisioning template (job or workflow) of the engine. The password is generated server side and can be retrieved once through the credentials endpoint when the job succeeds.
+// @Tags        dbaas - engines
+// @Accept      json
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       request body service_engine.ProvisionRequest true "Instance to provision"
+// @Param       Idempotency-Key header string false "Replays the original response when the same request is sent again with this key"
+// @Success     202 {object} service_engine.OperationResponse "Provisioning started"
+// @Failure     400 {object} map[string]interface{} "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]interface{} "Quota exceeded"
+// @Failure     409 {object} map[string]string "Instance already exists, or Idempotency-Key reused with a different request"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service error"
+// @Failure     503 {object} map[string]string "External service unavailable"
+// @Router      /{engine}/v1/instances [post]
+// @Security Bearer
+func ProvisionInstanceHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		var req service.ProvisionRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		response, err := engineService.Provision(c.Request.Context(), customerID, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to provision %s instance %s: %v", rid, engineService.Engine(), req.InstanceName, err)
+			respondServiceError(c, err, "Failed to provision instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] %s instance %s provisioning started for customer %s, job_id=%d", rid, engineService.Engine(), req.InstanceName, customerID, response.JobID)
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// ListInstancesHandler godoc
+// @Summary     List instances
+// @Description Lists the instances of the engine owned by the customer, most recent first
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Success     200 {array} service_engine.InstanceResponse "Instances"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /{engine}/v1/instances [get]
+// @Security Bearer
+func ListInstancesHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		instances, err := engineService.ListInstances(c.Request.Context(), customerID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list %s instances: %v", rid, engineService.Engine(), err)
+			respondServiceError(c, err, "Failed to list instances")
+			return
+		}
+
+		c.JSON(http.StatusOK, instances)
+	}
+}
+
+// GetInstanceHandler godoc
+// @Summary     Get an instance
+// @Description Returns an instance of the engine owned by the customer
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       name path string true "Instance name"
+// @Success     200 {object} service_engine.InstanceResponse "Instance"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /{engine}/v1/instances/{name} [get]
+// @Security Bearer
+func GetInstanceHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		instance, err := engineService.DescribeInstance(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get %s instance %s: %v", rid, engineService.Engine(), name, err)
+			respondServiceError(c, err, "Failed to get instance")
+			return
+		}
+
+		c.JSON(http.StatusOK, instance)
+	}
+}
+
+// DeleteInstanceHandler godoc
+// @Summary     Delete an instance
+// @Description Launches the AWX deletion template of the engine. The instance is removed once the job succeeds; an instance whose provisioning failed is removed immediately. A disruptive deletion template is queued until the maintenance window of the instance.
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       name path string true "Instance name"
+// @Success     202 {object} service_engine.OperationResponse "Deletion started, or scheduled in the maintenance window"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready, operation already running or already scheduled"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Deletion template not configured"
+// @Failure     502 {object} map[string]string "External service error"
+// @Failure     503 {object} map[string]string "External service unavailable"
+// @Router      /{engine}/v1/instances/{name} [delete]
+// @Security Bearer
+func DeleteInstanceHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		response, err := engineService.Delete(c.Request.Context(), customerID, name, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete %s instance %s: %v", rid, engineService.Engine(), name, err)
+			respondServiceError(c, err, "Failed to delete instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] %s instance %s deletion started, job_id=%d", rid, engineService.Engine(), name, response.JobID)
+		c.JSON(http.StatusAccepted, response)
+	}
+}
+
+// GetInstanceHistoryHandler godoc
+// @Summary     Instance operations history
+// @Description Lists the AWX operations run on an instance owned by the customer, most recent first
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       name path string true "Instance name"
+// @Success     200 {array} service_engine.HistoryEntry "Operations"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /{engine}/v1/instances/{name}/history [get]
+// @Security Bearer
+func GetInstanceHistoryHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		history, err := engineService.ListHistory(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list history of %s instance %s: %v", rid, engineService.Engine(), name, err)
+			respondServiceError(c, err, "Failed to list history")
+			return
+		}
+
+		c.JSON(http.StatusOK, history)
+	}
+}
+
+// GetInstanceCredentialsHandler godoc
+// @Summary     Retrieve instance credentials (one-time)
+// @Description Returns the generated credentials of an instance once its AWX job has succeeded. The credentials can only be retrieved once.
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       name path string true "Instance name"
+// @Success     200 {object} map[string]interface{} "Instance credentials"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Job still running or failed"
+// @Failure     410 {object} map[string]string "Credentials already retrieved"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /{engine}/v1/instances/{name}/credentials [get]
+// @Security Bearer
+func GetInstanceCredentialsHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		creds, err := engineService.GetCredentials(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to retrieve credentials for %s instance %s: %v", rid, engineService.Engine(), name, err)
+			respondServiceError(c, err, "Failed to retrieve credentials")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Credentials of %s instance %s retrieved by customer %s", rid, engineService.Engine(), name, customerID)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{
+			"instance_name": name,
+			"username":      creds.Username,
+			"password":      creds.Password,
+			"request_id":    rid,
+		})
+	}
+}
+
+// GetJobStatusHandler godoc
+// @Summary     Get job status
+// @Description Returns the status of an AWX job (or workflow) launched by the engine for the customer
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Param       job_id path int true "AWX job ID"
+// @Success     200 {object} service_engine.OperationResponse "Job status"
+// @Failure     400 {object} map[string]string "Invalid job ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Job not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     503 {object} map[string]string "External service unavailable"
+// @Router      /{engine}/v1/jobs/{job_id}/status [get]
+// @Security Bearer
+func GetJobStatusHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		jobID, err := strconv.Atoi(c.Param("job_id"))
+		if err != nil || jobID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID", "request_id": rid})
+			return
+		}
+
+		status, err := engineService.GetJobStatus(c.Request.Context(), customerID, jobID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get status of %s job %d: %v", rid, engineService.Engine(), jobID, err)
+			respondServiceError(c, err, "Failed to get job status")
+			return
+		}
+
+		c.JSON(http.StatusOK, status)
+	}
+}
+
+// GetQuotaHandler godoc
+// @Summary     Get DBaaS quota
+// @Description Returns the DBaaS limits of the customer and their current usage, all engines included (instances, storage, concurrent jobs). A limit of 0 means unlimited.
+// @Tags        dbaas - engines
+// @Produce     json
+// @Param       engine path string true "Engine name (mysql, redis, mongodb...)"
+// @Success     200 {object} service_engine.QuotaResponse "Quota and usage"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /{engine}/v1/quota [get]
+// @Security Bearer
+func GetQuotaHandler(engineService *service.Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		quota, err := engineService.GetQuota(c.Request.Context(), customerID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get quota of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to get quota")
+			return
+		}
+
+		c.JSON(http.StatusOK, quota)
+	}
+}
+
+// respondServiceError traduit les erreurs du service en réponses HTTP
+func respondServiceError(c *gin.Context, err error, fallback string) {
+	rid := c.GetString("request_id")
+
+	var verr *service.ValidationError
+	var quotaErr *service.QuotaError
+	var awxErr *awxclient.APIError
+	switch {
+	case errors.As(err, &verr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters", "fields": verr.Fields, "request_id": rid})
+	case errors.As(err, &quotaErr):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Quota exceeded", "resource": quotaErr.Resource, "limit": quotaErr.Limit, "used": quotaErr.Used, "request_id": rid})
+	case errors.Is(err, service.ErrInstanceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instance not found", "request_id": rid})
+	case errors.Is(err, service.ErrInstanceAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Instance already exists", "request_id": rid})
+	case errors.Is(err, service.ErrInstanceNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": "Instance is not ready", "request_id": rid})
+	case errors.Is(err, service.ErrOperationInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": "An operation is already running on this instance", "request_id": rid})
+	case errors.Is(err, service.ErrMaintenanceWindowNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance window not found", "request_id": rid})
+	case errors.Is(err, service.ErrScheduledOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending operation not found", "request_id": rid})
+	case errors.Is(err, service.ErrOperationAlreadyScheduled):
+		c.JSON(http.StatusConflict, gin.H{"error": "An operation is already waiting for the maintenance window", "request_id": rid})
+	case errors.Is(err, service.ErrScheduledSecrets):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Operation cannot be scheduled", "request_id": rid})
+	case errors.Is(err, service.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found", "request_id": rid})
+	case errors.Is(err, service.ErrCredentialsNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": "Credentials not available yet, job still running", "request_id": rid})
+	case errors.Is(err, service.ErrCredentialsUnavailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Credentials unavailable, job did not succeed", "request_id": rid})
+	case errors.Is(err, service.ErrCredentialsAlreadyRetrieved):
+		c.JSON(http.StatusGone, gin.H{"error": "Credentials already retrieved", "request_id": rid})
+	case errors.Is(err, service.ErrTemplateNotConfigured):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Operation not available", "request_id": rid})
+	case errors.Is(err, service.ErrSecretVariablesNotProtected):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template does not protect secret variables", "request_id": rid})
+	case errors.Is(err, awxclient.ErrTemplateNotFound):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template not found", "request_id": rid})
+	case errors.Is(err, awxclient.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "External service unavailable", "request_id": rid})
+	case errors.As(err, &awxErr):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "External service error", "request_id": rid})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback, "request_id": rid})
+	}
+}
@@ -0,0 +1,176 @@
+package handler_postgresql
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+type BackupScheduleRequest struct {
+	IntervalHours int        `json:"interval_hours" binding:"required"`
+	Enabled       *bool      `json:"enabled"`
+	StartAt       *time.Time `json:"start_at"`
+}
+
+// TriggerBackupHandler godoc
+// @Summary     Trigger a backup
+// @Description Launches the AWX backup template for an instance owned by the customer. Size and timestamp are filled from the job artifacts once it completes.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     202 {object} service_postgresql.BackupResponse "Backup started"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready or operation already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Backup template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/backups [post]
+// @Security Bearer
+func TriggerBackupHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		backup, err := postgresService.TriggerBackup(c.Request.Context(), customerID, name, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to trigger backup of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to trigger backup")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Backup started for instance %s, job_id=%d", rid, name, backup.JobID)
+		c.JSON(http.StatusAccepted, backup)
+	}
+}
+
+// ListBackupsHandler godoc
+// @Summary     List backups
+// @Description Lists the backups of an instance owned by the customer, most recent first
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     200 {array} service_postgresql.BackupResponse "Backups"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/backups [get]
+// @Security Bearer
+func ListBackupsHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		backups, err := postgresService.ListBackups(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list backups of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list backups")
+			return
+		}
+
+		c.JSON(http.StatusOK, backups)
+	}
+}
+
+// GetBackupScheduleHandler godoc
+// @Summary     Get backup schedule
+// @Description Returns the backup schedule of an instance owned by the customer
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     200 {object} service_postgresql.BackupScheduleResponse "Backup schedule"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance or schedule not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/backups/schedule [get]
+// @Security Bearer
+func GetBackupScheduleHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		schedule, err := postgresService.GetBackupSchedule(c.Request.Context(), customerID, name)
+		if err != nil {
+			if errors.Is(err, service.ErrBackupScheduleNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "No backup schedule for this instance", "request_id": rid})
+				return
+			}
+			klog.Errorf("[request_id=%s] Failed to get backup schedule of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to get backup schedule")
+			return
+		}
+
+		c.JSON(http.StatusOK, schedule)
+	}
+}
+
+// SetBackupScheduleHandler godoc
+// @Summary     Set backup schedule
+// @Description Creates or replaces the backup schedule of an instance. Backups run every interval_hours, starting at start_at (or after one interval), and are recorded in the AWX history.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body BackupScheduleRequest true "backup schedule"
+// @Success     200 {object} service_postgresql.BackupScheduleResponse "Backup schedule"
+// @Failure     400 {object} map[string]interface{} "Invalid request body (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Backup template not configured"
+// @Router      /postgres/v1/patroni/instances/{name}/backups/schedule [put]
+// @Security Bearer
+func SetBackupScheduleHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req BackupScheduleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "fields": bindingFieldErrors(err), "request_id": rid})
+			return
+		}
+
+		// Planification active par défaut
+		enabled := true
+		if req.Enabled != nil {
+			enabled = *req.Enabled
+		}
+
+		schedule, err := postgresService.SetBackupSchedule(c.Request.Context(), customerID, name, service.BackupScheduleRequest{
+			IntervalHours: req.IntervalHours,
+			Enabled:       enabled,
+			StartAt:       req.StartAt,
+		}, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to set backup schedule of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to set backup schedule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Backup schedule set for instance %s", rid, name)
+		c.JSON(http.StatusOK, schedule)
+	}
+}
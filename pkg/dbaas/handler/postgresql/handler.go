@@ -1,11 +1,12 @@
 package handler_postgresql
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 	awxclient "github.com/Gskill75/api2/pkg/awx/client"
 	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
 	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
@@ -14,24 +15,32 @@ import (
 )
 
 type ProvisionPostgresRequest struct {
-	TemplateName string `json:"template_name" binding:"required"`
-	InstanceName string `json:"instance_name"`
-	Username     string `json:"username"`
-	Password     string `json:"password"`
+	// Template de création parmi dbaas.templates.provision, le premier si vide
+	TemplateName string `json:"template_name,omitempty"`
+	InstanceName string `json:"instance_name" binding:"required,max=20"`
+	Username     string `json:"username" binding:"required"`
 	CustomerID   string `json:"customer_id"`
+	StorageGB    int32  `json:"storage_gb"`
+	// Version majeure PostgreSQL, la première de dbaas.postgres_versions si vide
+	Version string `json:"version,omitempty"`
 }
 
 // ProvisionPostgresHandler godoc
 // @Summary     Provision a PostgreSQL instance
-// @Description Provisions a new PostgreSQL instance using AWX automation
+// @Description Provisions a new PostgreSQL instance using AWX automation. template_name must be one of the provisioning templates of the offer (dbaas.templates.provision), the first one when omitted. The password is generated server-side and can be retrieved once through the credentials endpoint when the job succeeds.
 // @Tags        dbaas - PostgreSQL
 // @Accept      json
 // @Produce     json
 // @Param       request body ProvisionPostgresRequest true "instance provisioning request"
+// @Param       Idempotency-Key header string false "Replays the original response when the same request is sent again with this key"
 // @Success     200 {object} string "instance provisioned successfully"
-// @Failure     400 {object} map[string]string "Invalid request body"
+// @Failure     400 {object} map[string]interface{} "Invalid request body or provisioning parameters (field-level errors)"
 // @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]interface{} "Quota exceeded"
+// @Failure     409 {object} map[string]string "Instance already exists, or Idempotency-Key reused with a different request"
 // @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "No provisioning template configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
 // @Router      /postgres/v1/patroni/instance [post]
 // @Security Bearer
 func ProvisionPostgresHandler(awxClient *awxclient.Client, queries *db.Queries, postgresService *service.PostgresService) gin.HandlerFunc {
@@ -45,7 +54,7 @@ func ProvisionPostgresHandler(awxClient *awxclient.Client, queries *db.Queries,
 		var req ProvisionPostgresRequest
 		if err := c.ShouldBindJSON(&req); err != nil {
 			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "fields": bindingFieldErrors(err), "request_id": rid})
 			return
 		}
 
@@ -56,33 +65,20 @@ func ProvisionPostgresHandler(awxClient *awxclient.Client, queries *db.Queries,
 			TemplateName: req.TemplateName,
 			InstanceName: req.InstanceName,
 			Username:     req.Username,
-			Password:     req.Password,
 			CustomerID:   customerID,
+			StorageGB:    req.StorageGB,
+			Version:      req.Version,
 		}
 
 		response, err := postgresService.ProvisionDatabase(c.Request.Context(), serviceReq, c.GetString("sub"))
 		if err != nil {
 			klog.Errorf("[request_id=%s] Failed to provision PostgreSQL database: %v", rid, err)
 
-			// Gestion des erreurs un peu nul ?
-			if strings.Contains(err.Error(), "awx_launch_failed") {
-				c.JSON(http.StatusBadGateway, gin.H{"error": "External service unavailable", "request_id": rid})
-				return
-			}
-			if strings.Contains(err.Error(), "template_name_not_found") {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Template not found", "request_id": rid})
-				return
-			}
-			if strings.Contains(err.Error(), "db_insert_failed") {
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Job launched but DB insert failed", "request_id": rid})
-				return
-			}
-
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to provision database", "request_id": rid})
+			respondServiceError(c, err, "Failed to provision database")
 			return
 		}
 
-		klog.Infof("[request_id=%s] PostgreSQL instance provisioning started for customer %s, job_id=%d", rid, "1", response.JobID)
+		klog.Infof("[request_id=%s] PostgreSQL instance provisioning started for customer %s, job_id=%d", rid, customerID, response.JobID)
 		c.JSON(http.StatusAccepted, gin.H{
 			"message":       "PostgreSQL instance provisioning started",
 			"job_id":        response.JobID,
@@ -95,13 +91,14 @@ func ProvisionPostgresHandler(awxClient *awxclient.Client, queries *db.Queries,
 
 // GetJobStatusHandler godoc
 // @Summary     Get job status
-// @Description Get the status of a PostgreSQL provisioning job
+// @Description Get the status of a PostgreSQL job launched for the customer. The jobs of other customers are reported as not found.
 // @Tags        dbaas - PostgreSQL
 // @Accept      json
 // @Produce     json
 // @Param       job_id path int true "Job ID"
 // @Success     200 {object} map[string]interface{} "Job status"
 // @Failure     400 {object} map[string]string "Invalid job ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
 // @Failure     404 {object} map[string]string "Job not found"
 // @Failure     500 {object} map[string]string "Internal server error"
 // @Router      /postgres/v1/patroni/instance/{job_id}/status [get]
@@ -109,6 +106,10 @@ func ProvisionPostgresHandler(awxClient *awxclient.Client, queries *db.Queries,
 func GetJobStatusHandler(postgresService *service.PostgresService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
 
 		jobIDStr := c.Param("job_id")
 		jobID := 0
@@ -118,16 +119,16 @@ func GetJobStatusHandler(postgresService *service.PostgresService) gin.HandlerFu
 			return
 		}
 
-		status, err := postgresService.GetJobStatus(c.Request.Context(), jobID)
+		status, err := postgresService.GetJobStatus(c.Request.Context(), customerID, jobID)
 		if err != nil {
 			klog.Errorf("[request_id=%s] Failed to get job status for job %d: %v", rid, jobID, err)
 
-			if strings.Contains(err.Error(), "failed_to_get_job_status") {
+			if errors.Is(err, awxclient.ErrNotFound) {
 				c.JSON(http.StatusNotFound, gin.H{"error": "Job not found", "request_id": rid})
 				return
 			}
 
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get job status", "request_id": rid})
+			respondServiceError(c, err, "Failed to get job status")
 			return
 		}
 
@@ -142,19 +143,24 @@ func GetJobStatusHandler(postgresService *service.PostgresService) gin.HandlerFu
 
 // CheckActiveJobHandler godoc
 // @Summary     Check for active jobs
-// @Description Check if customer has an active job for the given template
+// @Description Check if the customer has an active job for the given template. Only the jobs launched by the customer are considered.
 // @Tags        dbaas - PostgreSQL
 // @Accept      json
 // @Produce     json
 // @Param       template_name query string true "Template name"
 // @Success     200 {object} map[string]interface{} "Active job status"
 // @Failure     400 {object} map[string]string "Missing template name"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
 // @Failure     500 {object} map[string]string "Internal server error"
 // @Router      /postgres/v1/patroni/instance/check [get]
 // @Security Bearer
 func CheckActiveJobHandler(postgresService *service.PostgresService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
 
 		templateName := c.Query("template_name")
 		if templateName == "" {
@@ -163,9 +169,6 @@ func CheckActiveJobHandler(postgresService *service.PostgresService) gin.Handler
 			return
 		}
 
-		// For now using hardcoded customer ID
-		customerID := "1"
-
 		activeJob, err := postgresService.CheckActiveJob(c.Request.Context(), customerID, templateName)
 		if err != nil {
 			klog.Errorf("[request_id=%s] Failed to check active jobs for template %s: %v", rid, templateName, err)
@@ -179,6 +182,7 @@ func CheckActiveJobHandler(postgresService *service.PostgresService) gin.Handler
 				"has_active_job": true,
 				"job_id":         activeJob.JobID,
 				"status":         activeJob.Status,
+				"instance_name":  activeJob.InstanceName,
 				"template_name":  templateName,
 				"customer_id":    customerID,
 				"request_id":     rid,
@@ -194,3 +198,190 @@ func CheckActiveJobHandler(postgresService *service.PostgresService) gin.Handler
 		}
 	}
 }
+
+// GetInstanceCredentialsHandler godoc
+// @Summary     Retrieve instance credentials (one-time)
+// @Description Returns the generated credentials of a PostgreSQL instance once its AWX job has succeeded. The credentials can only be retrieved once.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     200 {object} map[string]interface{} "Instance credentials"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Job still running or failed"
+// @Failure     410 {object} map[string]string "Credentials already retrieved"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/credentials [get]
+// @Security Bearer
+func GetInstanceCredentialsHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		creds, err := postgresService.GetCredentials(c.Request.Context(), customerID, name)
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrInstanceNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": "Instance not found", "request_id": rid})
+			case errors.Is(err, service.ErrCredentialsNotReady):
+				c.JSON(http.StatusConflict, gin.H{"error": "Credentials not available yet, job still running", "request_id": rid})
+			case errors.Is(err, service.ErrCredentialsUnavailable):
+				c.JSON(http.StatusConflict, gin.H{"error": "Credentials unavailable, job did not succeed", "request_id": rid})
+			case errors.Is(err, service.ErrCredentialsAlreadyRetrieved):
+				c.JSON(http.StatusGone, gin.H{"error": "Credentials already retrieved", "request_id": rid})
+			default:
+				klog.Errorf("[request_id=%s] Failed to retrieve credentials for instance %s: %v", rid, name, err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve credentials", "request_id": rid})
+			}
+			return
+		}
+
+		klog.Infof("[request_id=%s] Credentials of instance %s retrieved by customer %s", rid, name, customerID)
+		c.Header("Cache-Control", "no-store")
+		c.JSON(http.StatusOK, gin.H{
+			"instance_name": name,
+			"username":      creds.Username,
+			"password":      creds.Password,
+			"request_id":    rid,
+		})
+	}
+}
+
+// RotateCredentialsHandler godoc
+// @Summary     Rotate instance credentials
+// @Description Launches the AWX credential rotation template for an instance owned by the customer. The new credentials can be retrieved once through the credentials endpoint when the job succeeds.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     202 {object} map[string]interface{} "Rotation started"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready or operation already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/credentials/rotate [post]
+// @Security Bearer
+func RotateCredentialsHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		response, err := postgresService.RotateCredentials(c.Request.Context(), customerID, name, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to rotate credentials of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to rotate credentials")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Credential rotation started for instance %s, job_id=%d", rid, name, response.JobID)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       "Credential rotation started, retrieve the new credentials once the job succeeds",
+			"job_id":        response.JobID,
+			"status":        response.Status,
+			"instance_name": response.InstanceName,
+			"request_id":    rid,
+		})
+	}
+}
+
+// respondServiceError traduit les erreurs du service DBaaS en réponse HTTP
+func respondServiceError(c *gin.Context, err error, fallback string) {
+	rid := c.GetString("request_id")
+
+	var verr *service.ValidationError
+	var quotaErr *service.QuotaError
+	var awxErr *awxclient.APIError
+	switch {
+	case errors.As(err, &verr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid parameters", "fields": verr.Fields, "request_id": rid})
+	case errors.As(err, &quotaErr):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Quota exceeded", "resource": quotaErr.Resource, "limit": quotaErr.Limit, "used": quotaErr.Used, "request_id": rid})
+	case errors.Is(err, service.ErrInstanceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Instance not found", "request_id": rid})
+	case errors.Is(err, service.ErrInstanceAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Instance already exists", "request_id": rid})
+	case errors.Is(err, service.ErrInstanceNotReady):
+		c.JSON(http.StatusConflict, gin.H{"error": "Instance is not ready", "request_id": rid})
+	case errors.Is(err, service.ErrOperationInProgress):
+		c.JSON(http.StatusConflict, gin.H{"error": "An operation is already running on this instance", "request_id": rid})
+	case errors.Is(err, service.ErrJobNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found", "request_id": rid})
+	case errors.Is(err, service.ErrJobNotCancelable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Job is not running", "request_id": rid})
+	case errors.Is(err, service.ErrJobNotRelaunchable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Only failed or canceled jobs can be relaunched", "request_id": rid})
+	case errors.Is(err, service.ErrRelaunchCredentialsUnavailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Generated credentials are no longer available, start a new operation", "request_id": rid})
+	case errors.Is(err, service.ErrMaintenanceWindowNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Maintenance window not found", "request_id": rid})
+	case errors.Is(err, service.ErrScheduledOperationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pending operation not found", "request_id": rid})
+	case errors.Is(err, service.ErrOperationAlreadyScheduled):
+		c.JSON(http.StatusConflict, gin.H{"error": "An operation is already waiting for the maintenance window", "request_id": rid})
+	case errors.Is(err, service.ErrScheduledSecrets):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Operation cannot be scheduled", "request_id": rid})
+	case errors.Is(err, service.ErrBackupNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Backup not found", "request_id": rid})
+	case errors.Is(err, service.ErrBackupNotAvailable):
+		c.JSON(http.StatusConflict, gin.H{"error": "Backup did not complete", "request_id": rid})
+	case errors.Is(err, service.ErrInvalidConfirmationToken):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Invalid or expired confirmation token", "request_id": rid})
+	case errors.Is(err, service.ErrTemplateNotConfigured):
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Operation not available", "request_id": rid})
+	case errors.Is(err, service.ErrSecretVariablesNotProtected):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template does not protect secret variables", "request_id": rid})
+	case errors.Is(err, awxclient.ErrTemplateNotFound):
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Template not found", "request_id": rid})
+	case errors.Is(err, awxclient.ErrUnavailable):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "External service unavailable", "request_id": rid})
+	case errors.As(err, &awxErr):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "External service error", "request_id": rid})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback, "request_id": rid})
+	}
+}
+
+// bindingFieldErrors convertit les erreurs de binding gin en erreurs par champ JSON
+func bindingFieldErrors(err error) map[string]string {
+	fields := map[string]string{}
+
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return fields
+	}
+
+	for _, fe := range verrs {
+		name := jsonFieldNames[fe.Field()]
+		if name == "" {
+			name = fe.Field()
+		}
+		switch fe.Tag() {
+		case "required":
+			fields[name] = "is required"
+		case "max":
+			fields[name] = fmt.Sprintf("must be at most %s characters", fe.Param())
+		case "min":
+			fields[name] = fmt.Sprintf("must be at least %s characters", fe.Param())
+		default:
+			fields[name] = fmt.Sprintf("failed on '%s' validation", fe.Tag())
+		}
+	}
+	return fields
+}
+
+var jsonFieldNames = map[string]string{
+	"TemplateName":       "template_name",
+	"InstanceName":       "instance_name",
+	"Username":           "username",
+	"CustomerID":         "customer_id",
+	"IntervalHours":      "interval_hours",
+	"TargetInstanceName": "target_instance_name",
+}
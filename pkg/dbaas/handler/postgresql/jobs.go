@@ -0,0 +1,111 @@
+package handler_postgresql
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// GetJobStdoutHandler godoc
+// @Summary     Get job output
+// @Description Returns the output of an AWX job of the customer, without ANSI codes and with secrets masked. format=txt (default) returns plain text, format=json wraps it in a JSON object.
+// @Tags        dbaas - PostgreSQL
+// @Produce     plain
+// @Produce     json
+// @Param       job_id path int true "Job ID"
+// @Param       format query string false "Output format (txt or json)"
+// @Success     200 {string} string "Job output"
+// @Failure     400 {object} map[string]string "Invalid job ID or format"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Job not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/jobs/{job_id}/stdout [get]
+// @Security Bearer
+func GetJobStdoutHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		jobID, ok := parseJobID(c)
+		if !ok {
+			return
+		}
+		format := c.DefaultQuery("format", "txt")
+		if format != "txt" && format != "json" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected txt or json", "request_id": rid})
+			return
+		}
+
+		stdout, err := postgresService.GetJobStdout(c.Request.Context(), customerID, jobID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get stdout of job %d: %v", rid, jobID, err)
+			respondServiceError(c, err, "Failed to get job output")
+			return
+		}
+
+		if format == "json" {
+			c.JSON(http.StatusOK, gin.H{"job_id": jobID, "stdout": stdout, "request_id": rid})
+			return
+		}
+		c.String(http.StatusOK, stdout)
+	}
+}
+
+// GetJobEventsHandler godoc
+// @Summary     Get job events
+// @Description Returns the tasks of an AWX job of the customer with their status, and the hosts on which a task failed
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       job_id path int true "Job ID"
+// @Success     200 {object} service_postgresql.JobEventsResponse "Job events summary"
+// @Failure     400 {object} map[string]string "Invalid job ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Job not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/jobs/{job_id}/events [get]
+// @Security Bearer
+func GetJobEventsHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		jobID, ok := parseJobID(c)
+		if !ok {
+			return
+		}
+
+		events, err := postgresService.GetJobEvents(c.Request.Context(), customerID, jobID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get events of job %d: %v", rid, jobID, err)
+			respondServiceError(c, err, "Failed to get job events")
+			return
+		}
+
+		c.JSON(http.StatusOK, events)
+	}
+}
+
+// parseJobID lit le paramètre job_id, répond 400 s'il est invalide
+func parseJobID(c *gin.Context) (int, bool) {
+	jobIDStr := c.Param("job_id")
+	jobID := 0
+	if _, err := fmt.Sscanf(jobIDStr, "%d", &jobID); err != nil || jobID <= 0 {
+		rid := c.GetString("request_id")
+		klog.Warningf("[request_id=%s] Invalid job ID: %s", rid, jobIDStr)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID", "request_id": rid})
+		return 0, false
+	}
+	return jobID, true
+}
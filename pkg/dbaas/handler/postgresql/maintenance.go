@@ -0,0 +1,190 @@
+package handler_postgresql
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// GetMaintenanceWindowHandler godoc
+// @Summary     Get maintenance window
+// @Description Returns the weekly maintenance window of an instance owned by the customer and the start of the next window
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     200 {object} service_engine.MaintenanceWindowResponse "Maintenance window"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance or maintenance window not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/maintenance-window [get]
+// @Security Bearer
+func GetMaintenanceWindowHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		window, err := postgresService.GetMaintenanceWindow(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get maintenance window of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to get maintenance window")
+			return
+		}
+
+		c.JSON(http.StatusOK, window)
+	}
+}
+
+// SetMaintenanceWindowHandler godoc
+// @Summary     Set maintenance window
+// @Description Creates or replaces the weekly maintenance window of an instance. Disruptive operations (resize, upgrade, restore...) requested outside the window are queued and launched when the window opens. Queued operations are moved to the new window.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body service_engine.MaintenanceWindowRequest true "Days (0 = sunday), start time HH:MM, duration in minutes and IANA time zone"
+// @Success     200 {object} service_engine.MaintenanceWindowResponse "Maintenance window"
+// @Failure     400 {object} map[string]interface{} "Invalid window (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/maintenance-window [put]
+// @Security Bearer
+func SetMaintenanceWindowHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.MaintenanceWindowRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		window, err := postgresService.SetMaintenanceWindow(c.Request.Context(), customerID, name, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to set maintenance window of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to set maintenance window")
+			return
+		}
+
+		c.JSON(http.StatusOK, window)
+	}
+}
+
+// DeleteMaintenanceWindowHandler godoc
+// @Summary     Delete maintenance window
+// @Description Removes the maintenance window of an instance. Disruptive operations are then launched immediately, including the queued ones.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     204 "Maintenance window deleted"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance or maintenance window not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/maintenance-window [delete]
+// @Security Bearer
+func DeleteMaintenanceWindowHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		if err := postgresService.DeleteMaintenanceWindow(c.Request.Context(), customerID, name); err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete maintenance window of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to delete maintenance window")
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
+
+// ListPendingOperationsHandler godoc
+// @Summary     List pending operations
+// @Description Returns the disruptive operations of an instance waiting for its maintenance window
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Success     200 {array} service_engine.ScheduledOperation "Pending operations"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/pending-operations [get]
+// @Security Bearer
+func ListPendingOperationsHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		operations, err := postgresService.ListScheduledOperations(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list pending operations of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list pending operations")
+			return
+		}
+
+		c.JSON(http.StatusOK, operations)
+	}
+}
+
+// CancelPendingOperationHandler godoc
+// @Summary     Cancel a pending operation
+// @Description Cancels a disruptive operation waiting for the maintenance window, before its AWX job is launched
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       id path int true "Pending operation ID"
+// @Success     200 {object} service_engine.ScheduledOperation "Canceled operation"
+// @Failure     400 {object} map[string]string "Invalid operation ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance or pending operation not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/instances/{name}/pending-operations/{id} [delete]
+// @Security Bearer
+func CancelPendingOperationHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var id int32
+		if _, err := fmt.Sscanf(c.Param("id"), "%d", &id); err != nil || id <= 0 {
+			klog.Warningf("[request_id=%s] Invalid pending operation ID: %s", rid, c.Param("id"))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid operation ID", "request_id": rid})
+			return
+		}
+
+		operation, err := postgresService.CancelScheduledOperation(c.Request.Context(), customerID, name, id)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to cancel pending operation %d of instance %s: %v", rid, id, name, err)
+			respondServiceError(c, err, "Failed to cancel pending operation")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Pending %s operation %d of instance %s canceled by %s", rid, operation.Action, id, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, operation)
+	}
+}
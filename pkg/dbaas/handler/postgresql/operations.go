@@ -0,0 +1,117 @@
+package handler_postgresql
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// CancelOperationHandler godoc
+// @Summary     Cancel an operation
+// @Description Cancels a pending or running AWX job. Only the customer owning the operation or an admin can cancel it.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       job_id path int true "Job ID"
+// @Success     202 {object} map[string]interface{} "Cancellation requested"
+// @Failure     400 {object} map[string]string "Invalid job ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Job not found"
+// @Failure     409 {object} map[string]string "Job is not running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/operations/{job_id}/cancel [post]
+// @Security Bearer
+func CancelOperationHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		caller, ok := callerOrAbort(c)
+		if !ok {
+			return
+		}
+		jobID, ok := parseJobID(c)
+		if !ok {
+			return
+		}
+
+		history, err := postgresService.CancelJob(c.Request.Context(), caller, jobID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to cancel job %d: %v", rid, jobID, err)
+			respondServiceError(c, err, "Failed to cancel operation")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Job %d canceled", rid, jobID)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       "Cancellation requested",
+			"job_id":        jobID,
+			"status":        history.Status,
+			"instance_name": history.InstanceName,
+			"request_id":    rid,
+		})
+	}
+}
+
+// RelaunchOperationHandler godoc
+// @Summary     Relaunch an operation
+// @Description Relaunches a failed or canceled AWX job with the same parameters. The new job is linked to the original one in the history. A disruptive operation relaunched outside the maintenance window of the instance is queued until the window opens. Only the customer owning the operation or an admin can relaunch it.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Param       job_id path int true "Job ID"
+// @Success     202 {object} map[string]interface{} "Operation relaunched"
+// @Failure     400 {object} map[string]string "Invalid job ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Job not found"
+// @Failure     409 {object} map[string]string "Job not relaunchable, operation already running or scheduled, or credentials no longer available"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/operations/{job_id}/relaunch [post]
+// @Security Bearer
+func RelaunchOperationHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		caller, ok := callerOrAbort(c)
+		if !ok {
+			return
+		}
+		jobID, ok := parseJobID(c)
+		if !ok {
+			return
+		}
+
+		history, err := postgresService.RelaunchJob(c.Request.Context(), caller, jobID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to relaunch job %d: %v", rid, jobID, err)
+			respondServiceError(c, err, "Failed to relaunch operation")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Job %d relaunched as job %d", rid, jobID, history.AwxJobID.Int64)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":         "Operation relaunched",
+			"job_id":          history.AwxJobID.Int64,
+			"original_job_id": jobID,
+			"status":          history.Status,
+			"instance_name":   history.InstanceName,
+			"request_id":      rid,
+		})
+	}
+}
+
+// callerOrAbort identifie l'appelant ; un admin n'a pas besoin de customer_id
+func callerOrAbort(c *gin.Context) (service.Caller, bool) {
+	caller := service.Caller{
+		CustomerID: c.GetString("customer_id"),
+		Subject:    c.GetString("sub"),
+		Admin:      utils.IsAdmin(c),
+	}
+	if caller.Admin {
+		return caller, true
+	}
+
+	customerID, ok := utils.GetCustomerIDOrAbort(c)
+	caller.CustomerID = customerID
+	return caller, ok
+}
@@ -0,0 +1,106 @@
+package handler_postgresql
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// GetQuotaHandler godoc
+// @Summary     Get DBaaS quota
+// @Description Returns the DBaaS limits of the customer and their current usage, all engines included (instances, storage, concurrent jobs). A limit of 0 means unlimited.
+// @Tags        dbaas - PostgreSQL
+// @Produce     json
+// @Success     200 {object} service_engine.QuotaResponse "Quota and usage"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/patroni/quota [get]
+// @Security Bearer
+func GetQuotaHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+
+		quota, err := postgresService.GetQuota(c.Request.Context(), customerID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get quota of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to get quota")
+			return
+		}
+
+		c.JSON(http.StatusOK, quota)
+	}
+}
+
+// GetCustomerQuotaAdminHandler godoc
+// @Summary     Get the DBaaS quota of a customer (admin)
+// @Description Returns the DBaaS limits of any customer and their current usage
+// @Tags        dbaas - admin
+// @Produce     json
+// @Param       customer_id path string true "Customer ID"
+// @Success     200 {object} service_engine.QuotaResponse "Quota and usage"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]string "Admin role required"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/admin/quotas/{customer_id} [get]
+// @Security Bearer
+func GetCustomerQuotaAdminHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID := c.Param("customer_id")
+
+		quota, err := postgresService.GetQuota(c.Request.Context(), customerID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get quota of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to get quota")
+			return
+		}
+
+		c.JSON(http.StatusOK, quota)
+	}
+}
+
+// SetCustomerQuotaAdminHandler godoc
+// @Summary     Set the DBaaS quota of a customer (admin)
+// @Description Overrides the DBaaS limits of a customer. Omitted limits fall back to the default quotas, 0 means unlimited.
+// @Tags        dbaas - admin
+// @Accept      json
+// @Produce     json
+// @Param       customer_id path string true "Customer ID"
+// @Param       request body service_engine.QuotaRequest true "Customer limits"
+// @Success     200 {object} service_engine.QuotaResponse "Quota and usage"
+// @Failure     400 {object} map[string]interface{} "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]string "Admin role required"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /postgres/v1/admin/quotas/{customer_id} [put]
+// @Security Bearer
+func SetCustomerQuotaAdminHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID := c.Param("customer_id")
+
+		var req service.QuotaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		quota, err := postgresService.SetQuota(c.Request.Context(), customerID, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to set quota of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to set quota")
+			return
+		}
+
+		klog.Infof("[request_id=%s] DBaaS quota of customer %s updated by %s", rid, customerID, c.GetString("sub"))
+		c.JSON(http.StatusOK, quota)
+	}
+}
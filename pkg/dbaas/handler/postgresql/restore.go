@@ -0,0 +1,139 @@
+package handler_postgresql
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+type RestoreInstanceRequest struct {
+	BackupID          string     `json:"backup_id"`
+	PointInTime       *time.Time `json:"point_in_time"`
+	ConfirmationToken string     `json:"confirmation_token"`
+}
+
+type CloneInstanceRequest struct {
+	TargetInstanceName string     `json:"target_instance_name" binding:"required,max=20"`
+	BackupID           string     `json:"backup_id"`
+	PointInTime        *time.Time `json:"point_in_time"`
+}
+
+// RestoreInstanceHandler godoc
+// @Summary     Restore an instance in place
+// @Description Restores an instance from a backup_id or a point_in_time, overwriting its current data. Without confirmation_token the request is only checked and a single-use token valid a few minutes, bound to the customer and the instance, is returned; send the same request again with this token to launch the AWX restore job. The token is only used up once the instance is available and the request valid.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body RestoreInstanceRequest true "restore point and confirmation token"
+// @Success     200 {object} service_postgresql.RestoreConfirmation "Confirmation required"
+// @Success     202 {object} map[string]interface{} "Restore started, or scheduled in the maintenance window"
+// @Failure     400 {object} map[string]interface{} "Invalid restore point (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]string "Invalid or expired confirmation token"
+// @Failure     404 {object} map[string]string "Instance or backup not found"
+// @Failure     409 {object} map[string]string "Instance not ready, operation already running or backup not completed"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Restore template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/restore [post]
+// @Security Bearer
+func RestoreInstanceHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req RestoreInstanceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "fields": bindingFieldErrors(err), "request_id": rid})
+			return
+		}
+		restoreReq := service.RestoreRequest{BackupID: req.BackupID, PointInTime: req.PointInTime}
+
+		// Première étape : vérification et émission du jeton de confirmation
+		if req.ConfirmationToken == "" {
+			confirmation, err := postgresService.RequestRestore(c.Request.Context(), customerID, name, restoreReq)
+			if err != nil {
+				klog.Errorf("[request_id=%s] Restore of instance %s refused: %v", rid, name, err)
+				respondServiceError(c, err, "Failed to prepare restore")
+				return
+			}
+			c.JSON(http.StatusOK, confirmation)
+			return
+		}
+
+		response, err := postgresService.RestoreInstance(c.Request.Context(), customerID, name, restoreReq, req.ConfirmationToken, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to restore instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to restore instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Restore started for instance %s, job_id=%d", rid, name, response.JobID)
+		respondUpdateStarted(c, "Restore", response)
+	}
+}
+
+// CloneInstanceHandler godoc
+// @Summary     Clone an instance
+// @Description Creates a new instance owned by the customer from an existing instance, at its current state, a backup_id or a point_in_time. The clone gets new credentials, retrievable once through the credentials endpoint when the job succeeds.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Source instance name"
+// @Param       request body CloneInstanceRequest true "clone request"
+// @Success     202 {object} map[string]interface{} "Clone started"
+// @Failure     400 {object} map[string]interface{} "Invalid request (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance or backup not found"
+// @Failure     409 {object} map[string]string "Target already exists, source not ready or backup not completed"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Clone template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/clone [post]
+// @Security Bearer
+func CloneInstanceHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req CloneInstanceRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "fields": bindingFieldErrors(err), "request_id": rid})
+			return
+		}
+
+		response, err := postgresService.CloneInstance(c.Request.Context(), customerID, name, service.CloneRequest{
+			TargetInstanceName: req.TargetInstanceName,
+			Source:             service.RestoreRequest{BackupID: req.BackupID, PointInTime: req.PointInTime},
+		}, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to clone instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to clone instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Clone of %s into %s started, job_id=%d", rid, name, response.InstanceName, response.JobID)
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":       "Clone started, retrieve the credentials once the job succeeds",
+			"job_id":        response.JobID,
+			"status":        response.Status,
+			"instance_name": response.InstanceName,
+			"request_id":    rid,
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package handler_postgresql
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// ResizeInstanceHandler godoc
+// @Summary     Resize an instance
+// @Description Changes the CPU and memory of an instance and optionally grows its disk, through the AWX resize template. The disk cannot be shrunk and its growth counts against the storage quota.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body service_postgresql.ResizeRequest true "new instance size"
+// @Success     202 {object} map[string]interface{} "Resize started, or scheduled in the maintenance window"
+// @Failure     400 {object} map[string]interface{} "Invalid size (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]interface{} "Quota exceeded"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready or operation already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Resize template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/resize [post]
+// @Security Bearer
+func ResizeInstanceHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.ResizeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		response, err := postgresService.ResizeInstance(c.Request.Context(), customerID, name, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to resize instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to resize instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Resize started for instance %s, job_id=%d", rid, name, response.JobID)
+		respondUpdateStarted(c, "Resize", response)
+	}
+}
+
+// ScaleReplicasHandler godoc
+// @Summary     Scale Patroni replicas
+// @Description Changes the number of Patroni replicas of an instance through the AWX replicas template
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body service_postgresql.ScaleReplicasRequest true "number of replicas"
+// @Success     202 {object} map[string]interface{} "Scaling started, or scheduled in the maintenance window"
+// @Failure     400 {object} map[string]interface{} "Invalid number of replicas (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready or operation already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Replicas template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/replicas [post]
+// @Security Bearer
+func ScaleReplicasHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.ScaleReplicasRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		response, err := postgresService.ScaleReplicas(c.Request.Context(), customerID, name, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to scale replicas of instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to scale replicas")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Replicas scaling started for instance %s, job_id=%d", rid, name, response.JobID)
+		respondUpdateStarted(c, "Replicas scaling", response)
+	}
+}
+
+// UpgradeInstanceHandler godoc
+// @Summary     Upgrade PostgreSQL major version
+// @Description Upgrades an instance to a higher supported PostgreSQL major version through the AWX upgrade template. The instance version is updated once the job succeeds.
+// @Tags        dbaas - PostgreSQL
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Instance name"
+// @Param       request body service_postgresql.UpgradeRequest true "target major version"
+// @Success     202 {object} map[string]interface{} "Upgrade started, or scheduled in the maintenance window"
+// @Failure     400 {object} map[string]interface{} "Unsupported or lower version, or unknown current version (field-level errors)"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Instance not found"
+// @Failure     409 {object} map[string]string "Instance not ready or operation already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     501 {object} map[string]string "Upgrade template not configured"
+// @Failure     502 {object} map[string]string "External service unavailable"
+// @Router      /postgres/v1/patroni/instances/{name}/upgrade [post]
+// @Security Bearer
+func UpgradeInstanceHandler(postgresService *service.PostgresService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.UpgradeRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		response, err := postgresService.UpgradeInstance(c.Request.Context(), customerID, name, req, c.GetString("sub"))
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to upgrade instance %s: %v", rid, name, err)
+			respondServiceError(c, err, "Failed to upgrade instance")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Upgrade of instance %s to %s started, job_id=%d", rid, name, req.Version, response.JobID)
+		respondUpdateStarted(c, "Upgrade", response)
+	}
+}
+
+// respondUpdateStarted répond 202 à une opération lancée, ou planifiée dans la fenêtre de maintenance
+func respondUpdateStarted(c *gin.Context, operation string, response *service.PostgresProvisionResponse) {
+	body := gin.H{
+		"message":       operation + " started",
+		"job_id":        response.JobID,
+		"status":        response.Status,
+		"instance_name": response.InstanceName,
+		"request_id":    c.GetString("request_id"),
+	}
+	if response.ScheduledAt != nil {
+		body["message"] = operation + " scheduled in the maintenance window"
+		body["scheduled_at"] = response.ScheduledAt
+	}
+	c.JSON(http.StatusAccepted, body)
+}
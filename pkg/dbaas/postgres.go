@@ -1,12 +1,17 @@
 package dbaas
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	awxclient "github.com/Gskill75/api2/pkg/awx/client"
 	"github.com/Gskill75/api2/pkg/config"
 	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
 	handler "github.com/Gskill75/api2/pkg/dbaas/handler/postgresql"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
 	service "github.com/Gskill75/api2/pkg/dbaas/service/postgresql"
+	"github.com/Gskill75/api2/pkg/utils"
 )
 
 type DbaasSolution struct {
@@ -16,8 +21,8 @@ type DbaasSolution struct {
 	service   *service.PostgresService
 }
 
-func NewDbaasSolution(cfg *config.Config, awxclient *awxclient.Client, queries *db.Queries) *DbaasSolution {
-	postgresService := service.NewPostgresService(awxclient, queries, cfg)
+func NewDbaasSolution(cfg *config.Config, awxclient *awxclient.Client, queries *db.Queries, secrets secret.Store) *DbaasSolution {
+	postgresService := service.NewPostgresService(awxclient, queries, cfg, secrets)
 	return &DbaasSolution{
 		awxclient: awxclient,
 		queries:   queries,
@@ -26,6 +31,11 @@ func NewDbaasSolution(cfg *config.Config, awxclient *awxclient.Client, queries *
 	}
 }
 
+// RunScheduler exécute les backups et les opérations de maintenance planifiés jusqu'à l'arrêt du serveur
+func (s *DbaasSolution) RunScheduler(ctx context.Context) {
+	s.service.RunScheduler(ctx, time.Duration(s.cfg.Dbaas.SchedulerInterval)*time.Second)
+}
+
 func (*DbaasSolution) Name() string {
 	return "postgres"
 }
@@ -48,5 +58,48 @@ func (s *DbaasSolution) Endpoint(rg *gin.RouterGroup) {
 		userGroup.POST("/instance", handler.ProvisionPostgresHandler(s.awxclient, s.queries, s.service))
 		userGroup.GET("/instance/:job_id/status", handler.GetJobStatusHandler(s.service))
 		userGroup.GET("/instance/check", handler.CheckActiveJobHandler(s.service))
+		userGroup.GET("/instances/:name/credentials", handler.GetInstanceCredentialsHandler(s.service))
+		userGroup.POST("/instances/:name/credentials/rotate", handler.RotateCredentialsHandler(s.service))
+		userGroup.POST("/instances/:name/backups", handler.TriggerBackupHandler(s.service))
+		userGroup.GET("/instances/:name/backups", handler.ListBackupsHandler(s.service))
+		userGroup.GET("/instances/:name/backups/schedule", handler.GetBackupScheduleHandler(s.service))
+		userGroup.PUT("/instances/:name/backups/schedule", handler.SetBackupScheduleHandler(s.service))
+		userGroup.POST("/instances/:name/restore", handler.RestoreInstanceHandler(s.service))
+		userGroup.POST("/instances/:name/clone", handler.CloneInstanceHandler(s.service))
+		userGroup.POST("/instances/:name/resize", handler.ResizeInstanceHandler(s.service))
+		userGroup.POST("/instances/:name/replicas", handler.ScaleReplicasHandler(s.service))
+		userGroup.POST("/instances/:name/upgrade", handler.UpgradeInstanceHandler(s.service))
+		userGroup.GET("/instances/:name/maintenance-window", handler.GetMaintenanceWindowHandler(s.service))
+		userGroup.PUT("/instances/:name/maintenance-window", handler.SetMaintenanceWindowHandler(s.service))
+		userGroup.DELETE("/instances/:name/maintenance-window", handler.DeleteMaintenanceWindowHandler(s.service))
+		userGroup.GET("/instances/:name/pending-operations", handler.ListPendingOperationsHandler(s.service))
+		userGroup.DELETE("/instances/:name/pending-operations/:id", handler.CancelPendingOperationHandler(s.service))
+		userGroup.GET("/jobs/:job_id/stdout", handler.GetJobStdoutHandler(s.service))
+		userGroup.GET("/jobs/:job_id/events", handler.GetJobEventsHandler(s.service))
+		userGroup.POST("/operations/:job_id/cancel", handler.CancelOperationHandler(s.service))
+		userGroup.POST("/operations/:job_id/relaunch", handler.RelaunchOperationHandler(s.service))
+		userGroup.GET("/quota", handler.GetQuotaHandler(s.service))
+	}
+
+	s.setupAdminRoutes(rg)
+}
+
+// setupAdminRoutes configure les routes d'administration du DBaaS
+func (s *DbaasSolution) setupAdminRoutes(rg *gin.RouterGroup) {
+	adminGroup := rg.Group("/admin")
+
+	adminGroup.Use(func(c *gin.Context) {
+		if _, ok := utils.GetCustomerIDOrAbort(c); !ok {
+			return
+		}
+		if !utils.IsAdminOrAbort(c) {
+			return
+		}
+		c.Next()
+	})
+
+	{
+		adminGroup.GET("/quotas/:customer_id", handler.GetCustomerQuotaAdminHandler(s.service))
+		adminGroup.PUT("/quotas/:customer_id", handler.SetCustomerQuotaAdminHandler(s.service))
 	}
 }
@@ -0,0 +1,98 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileStoreTakeIsSingleRead(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	if err := store.Put(ctx, "cust/db1", []byte("s3cret")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	exists, err := store.Exists(ctx, "cust/db1")
+	if err != nil || !exists {
+		t.Fatalf("Exists = %v, %v; want true, nil", exists, err)
+	}
+
+	value, err := store.Take(ctx, "cust/db1")
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if string(value) != "s3cret" {
+		t.Fatalf("Take = %q, want %q", value, "s3cret")
+	}
+	if _, err := store.Take(ctx, "cust/db1"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("second Take error = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestFileStorePutReplacesAndDelete(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	if err := store.Put(ctx, "k", []byte("old")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Put(ctx, "k", []byte("new")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete(ctx, "k"); err != nil {
+		t.Fatalf("Delete of a missing secret: %v", err)
+	}
+	exists, err := store.Exists(ctx, "k")
+	if err != nil || exists {
+		t.Fatalf("Exists after Delete = %v, %v; want false, nil", exists, err)
+	}
+}
+
+func TestFileStoreKeysStayInDir(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	dir := filepath.Join(root, "secrets")
+	store, err := NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("NewFileStore: %v", err)
+	}
+
+	if err := store.Put(ctx, "../escape", []byte("x")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "escape")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("secret written outside of the store dir (stat error = %v)", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("store dir holds %d files, want 1", len(entries))
+	}
+	info, err := entries[0].Info()
+	if err != nil {
+		t.Fatalf("Info: %v", err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Fatalf("secret file mode = %o, want 600", perm)
+	}
+}
+
+func TestNewFileStoreRequiresDir(t *testing.T) {
+	if _, err := NewFileStore(""); err == nil {
+		t.Fatal("NewFileStore(\"\") succeeded, want an error")
+	}
+}
@@ -0,0 +1,87 @@
+package secret
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+)
+
+// PostgresStore chiffre les secrets (AES-256-GCM) avant de les écrire dans dbaas_secrets
+type PostgresStore struct {
+	queries *db.Queries
+	aead    cipher.AEAD
+}
+
+// NewPostgresStore attend une clé de 32 octets encodée en base64
+func NewPostgresStore(queries *db.Queries, encodedKey string) (*PostgresStore, error) {
+	if encodedKey == "" {
+		return nil, fmt.Errorf("dbaas.secret_store.encryption_key is required")
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key encoding: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("encryption key must be 32 bytes, got %d", len(key))
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init GCM: %w", err)
+	}
+
+	return &PostgresStore{queries: queries, aead: aead}, nil
+}
+
+func (s *PostgresStore) Put(ctx context.Context, key string, value []byte) error {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	// La clé est utilisée comme données additionnelles : un chiffré ne peut pas être déplacé sur une autre clé
+	encrypted := s.aead.Seal(nonce, nonce, value, []byte(key))
+
+	return s.queries.UpsertSecret(ctx, db.UpsertSecretParams{
+		SecretKey:      key,
+		EncryptedValue: encrypted,
+	})
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string) ([]byte, error) {
+	encrypted, err := s.queries.TakeSecret(ctx, key)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSecretNotFound
+		}
+		return nil, err
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(encrypted) < nonceSize {
+		return nil, fmt.Errorf("encrypted secret is too short")
+	}
+	value, err := s.aead.Open(nil, encrypted[:nonceSize], encrypted[nonceSize:], []byte(key))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt secret: %w", err)
+	}
+	return value, nil
+}
+
+func (s *PostgresStore) Delete(ctx context.Context, key string) error {
+	return s.queries.DeleteSecret(ctx, key)
+}
+
+func (s *PostgresStore) Exists(ctx context.Context, key string) (bool, error) {
+	return s.queries.SecretExists(ctx, key)
+}
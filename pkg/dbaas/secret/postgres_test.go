@@ -0,0 +1,193 @@
+package secret
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+)
+
+// memoryDB simule la table dbaas_secrets pour les requêtes sqlc du store
+type memoryDB struct {
+	rows map[string][]byte
+}
+
+func newMemoryDB() *memoryDB {
+	return &memoryDB{rows: map[string][]byte{}}
+}
+
+func (m *memoryDB) Exec(_ context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	switch {
+	case strings.Contains(sql, "name: UpsertSecret"):
+		m.rows[args[0].(string)] = bytes.Clone(args[1].([]byte))
+		return pgconn.NewCommandTag("INSERT 0 1"), nil
+	case strings.Contains(sql, "name: DeleteSecret"):
+		delete(m.rows, args[0].(string))
+		return pgconn.NewCommandTag("DELETE 1"), nil
+	}
+	return pgconn.CommandTag{}, fmt.Errorf("unexpected exec: %s", sql)
+}
+
+func (m *memoryDB) Query(_ context.Context, sql string, _ ...interface{}) (pgx.Rows, error) {
+	return nil, fmt.Errorf("unexpected query: %s", sql)
+}
+
+func (m *memoryDB) QueryRow(_ context.Context, sql string, args ...interface{}) pgx.Row {
+	key := args[0].(string)
+	value, ok := m.rows[key]
+	switch {
+	case strings.Contains(sql, "name: TakeSecret"):
+		if !ok {
+			return memoryRow{err: pgx.ErrNoRows}
+		}
+		delete(m.rows, key)
+		return memoryRow{value: value}
+	case strings.Contains(sql, "name: SecretExists"):
+		return memoryRow{value: ok}
+	}
+	return memoryRow{err: fmt.Errorf("unexpected query row: %s", sql)}
+}
+
+type memoryRow struct {
+	value interface{}
+	err   error
+}
+
+func (r memoryRow) Scan(dest ...interface{}) error {
+	if r.err != nil {
+		return r.err
+	}
+	switch d := dest[0].(type) {
+	case *[]byte:
+		*d = r.value.([]byte)
+	case *bool:
+		*d = r.value.(bool)
+	default:
+		return fmt.Errorf("unexpected scan target %T", d)
+	}
+	return nil
+}
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{b}, 32))
+}
+
+func newTestPostgresStore(t *testing.T, mem *memoryDB, key string) *PostgresStore {
+	t.Helper()
+	store, err := NewPostgresStore(db.New(mem), key)
+	if err != nil {
+		t.Fatalf("NewPostgresStore: %v", err)
+	}
+	return store
+}
+
+func TestPostgresStoreEncryptsAtRest(t *testing.T) {
+	ctx := context.Background()
+	mem := newMemoryDB()
+	store := newTestPostgresStore(t, mem, testKey(1))
+
+	plaintext := []byte(`{"username":"app","password":"s3cret"}`)
+	if err := store.Put(ctx, "cust/db1", plaintext); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	stored := mem.rows["cust/db1"]
+	if len(stored) == 0 {
+		t.Fatal("nothing written to dbaas_secrets")
+	}
+	if bytes.Contains(stored, []byte("s3cret")) {
+		t.Fatal("the password is stored in plaintext")
+	}
+
+	exists, err := store.Exists(ctx, "cust/db1")
+	if err != nil || !exists {
+		t.Fatalf("Exists = %v, %v; want true, nil", exists, err)
+	}
+	value, err := store.Take(ctx, "cust/db1")
+	if err != nil {
+		t.Fatalf("Take: %v", err)
+	}
+	if !bytes.Equal(value, plaintext) {
+		t.Fatalf("Take = %q, want %q", value, plaintext)
+	}
+	if _, err := store.Take(ctx, "cust/db1"); !errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("second Take error = %v, want ErrSecretNotFound", err)
+	}
+}
+
+func TestPostgresStoreUsesFreshNonces(t *testing.T) {
+	ctx := context.Background()
+	mem := newMemoryDB()
+	store := newTestPostgresStore(t, mem, testKey(1))
+
+	if err := store.Put(ctx, "a", []byte("same")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Put(ctx, "b", []byte("same")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if bytes.Equal(mem.rows["a"], mem.rows["b"]) {
+		t.Fatal("two encryptions of the same value are identical")
+	}
+}
+
+func TestPostgresStoreRejectsMovedCiphertext(t *testing.T) {
+	ctx := context.Background()
+	mem := newMemoryDB()
+	store := newTestPostgresStore(t, mem, testKey(1))
+
+	if err := store.Put(ctx, "cust-a/db1", []byte("s3cret")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	// Un chiffré recopié sous la clé d'un autre client ne doit pas se déchiffrer
+	mem.rows["cust-b/db1"] = mem.rows["cust-a/db1"]
+	if _, err := store.Take(ctx, "cust-b/db1"); err == nil || errors.Is(err, ErrSecretNotFound) {
+		t.Fatalf("Take of a moved ciphertext error = %v, want a decryption error", err)
+	}
+}
+
+func TestPostgresStoreRejectsWrongKey(t *testing.T) {
+	ctx := context.Background()
+	mem := newMemoryDB()
+
+	if err := newTestPostgresStore(t, mem, testKey(1)).Put(ctx, "k", []byte("s3cret")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := newTestPostgresStore(t, mem, testKey(2)).Take(ctx, "k"); err == nil {
+		t.Fatal("Take with another encryption key succeeded")
+	}
+}
+
+func TestPostgresStoreRejectsTruncatedCiphertext(t *testing.T) {
+	mem := newMemoryDB()
+	store := newTestPostgresStore(t, mem, testKey(1))
+
+	mem.rows["k"] = []byte{1, 2, 3}
+	if _, err := store.Take(context.Background(), "k"); err == nil {
+		t.Fatal("Take of a truncated ciphertext succeeded")
+	}
+}
+
+func TestNewPostgresStoreKeyValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+	}{
+		{name: "empty", key: ""},
+		{name: "not base64", key: "not-base64!"},
+		{name: "too short", key: base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewPostgresStore(db.New(newMemoryDB()), tt.key); err == nil {
+				t.Fatalf("NewPostgresStore(%q) succeeded, want an error", tt.key)
+			}
+		})
+	}
+}
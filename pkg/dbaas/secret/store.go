@@ -0,0 +1,48 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Gskill75/api2/pkg/config"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"k8s.io/klog/v2"
+)
+
+// ErrSecretNotFound est renvoyée quand le secret n'existe pas (ou a déjà été récupéré)
+var ErrSecretNotFound = errors.New("secret not found")
+
+// Store conserve les secrets DBaaS (mots de passe générés) hors d'AWX et de awx_history
+type Store interface {
+	// Put enregistre (ou remplace) le secret associé à la clé
+	Put(ctx context.Context, key string, value []byte) error
+	// Take renvoie le secret puis le supprime : lecture unique
+	Take(ctx context.Context, key string) ([]byte, error)
+	// Delete supprime le secret s'il existe
+	Delete(ctx context.Context, key string) error
+	// Exists indique si le secret est présent, sans le lire
+	Exists(ctx context.Context, key string) (bool, error)
+}
+
+// New construit le Store configuré : "postgres" (défaut) ou "file"
+func New(cfg *config.Config, queries *db.Queries) (Store, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("config is required")
+	}
+
+	storeCfg := cfg.Dbaas.SecretStore
+	switch storeCfg.Type {
+	case "", "postgres":
+		if queries == nil {
+			return nil, fmt.Errorf("db.Queries is required for the postgres secret store")
+		}
+		klog.Info("Using Postgres secret store for DBaaS credentials")
+		return NewPostgresStore(queries, storeCfg.EncryptionKey)
+	case "file":
+		klog.Warningf("Using file secret store in %s: not intended for production", storeCfg.Dir)
+		return NewFileStore(storeCfg.Dir)
+	default:
+		return nil, fmt.Errorf("unknown secret store type %q", storeCfg.Type)
+	}
+}
@@ -0,0 +1,183 @@
+package service_engine
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/jackc/pgx/v5"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
+	"k8s.io/klog/v2"
+)
+
+// RedactedValue valeur utilisée par AWX pour masquer les réponses de type password, reprise pour awx_history
+const RedactedValue = "$encrypted$"
+
+const (
+	generatedPasswordLength = 24
+	passwordLower           = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpper           = "ABCDEFGHJKLMNPQRSTUVWXYZ"
+	passwordDigits          = "23456789"
+	passwordSpecial         = "-_.~+=%"
+)
+
+var (
+	ErrCredentialsNotReady         = errors.New("credentials not ready: job still running")
+	ErrCredentialsUnavailable      = errors.New("credentials unavailable: job did not succeed")
+	ErrCredentialsAlreadyRetrieved = errors.New("credentials already retrieved")
+	ErrSecretVariablesNotProtected = errors.New("secret_not_protected")
+)
+
+// Credentials identifiants générés pour une instance, récupérables une seule fois
+type Credentials struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// CredentialKey clé des identifiants d'une instance dans le secret store
+func CredentialKey(customerID, instanceName string) string {
+	return fmt.Sprintf("dbaas/%s/%s/credentials", customerID, instanceName)
+}
+
+// GeneratePassword génère un mot de passe aléatoire contenant chaque classe de caractères
+func GeneratePassword() (string, error) {
+	classes := []string{passwordLower, passwordUpper, passwordDigits, passwordSpecial}
+	all := passwordLower + passwordUpper + passwordDigits + passwordSpecial
+
+	buf := make([]byte, 0, generatedPasswordLength)
+	for _, class := range classes {
+		c, err := randomChar(class)
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, c)
+	}
+	for len(buf) < generatedPasswordLength {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		buf = append(buf, c)
+	}
+
+	// Mélange Fisher-Yates pour ne pas garder les classes en tête
+	for i := len(buf) - 1; i > 0; i-- {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		j := int(n.Int64())
+		buf[i], buf[j] = buf[j], buf[i]
+	}
+	return string(buf), nil
+}
+
+func randomChar(charset string) (byte, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(charset))))
+	if err != nil {
+		return 0, fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return charset[n.Int64()], nil
+}
+
+// secretVariables renvoie les variables déclarées "password" dans le survey :
+// AWX les chiffre et les affiche "$encrypted$" dans le détail du job
+func secretVariables(spec *awxclient.SurveySpec) map[string]bool {
+	vars := map[string]bool{}
+	if spec == nil {
+		return vars
+	}
+	for _, q := range spec.Spec {
+		if q.Type == "password" {
+			vars[q.Variable] = true
+		}
+	}
+	return vars
+}
+
+// EnsureSecretsProtected refuse le lancement si une variable secrète n'est pas une question password du survey
+func EnsureSecretsProtected(spec *awxclient.SurveySpec, secretKeys ...string) error {
+	protected := secretVariables(spec)
+	for _, key := range secretKeys {
+		if !protected[key] {
+			return fmt.Errorf("%w: variable '%s' must be a password question in the template survey", ErrSecretVariablesNotProtected, key)
+		}
+	}
+	return nil
+}
+
+// RedactSecrets copie les extra vars en masquant les secrets avant persistance
+func RedactSecrets(extraVars map[string]interface{}, secretKeys ...string) map[string]interface{} {
+	redacted := make(map[string]interface{}, len(extraVars))
+	for k, v := range extraVars {
+		redacted[k] = v
+	}
+	for _, key := range secretKeys {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = RedactedValue
+		}
+	}
+	return redacted
+}
+
+// StoreCredentials enregistre les identifiants générés jusqu'à leur récupération par le client
+func (s *Service) StoreCredentials(ctx context.Context, customerID, instanceName string, creds Credentials) error {
+	value, err := json.Marshal(creds)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+	return s.secrets.Put(ctx, CredentialKey(customerID, instanceName), value)
+}
+
+// GetCredentials renvoie une seule fois les identifiants d'une instance, une fois le job AWX réussi
+func (s *Service) GetCredentials(ctx context.Context, customerID, instanceName string) (*Credentials, error) {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	history, err := s.queries.GetLatestCredentialHistory(ctx, db.GetLatestCredentialHistoryParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInstanceNotFound
+		}
+		return nil, fmt.Errorf("failed_to_get_history: %w", err)
+	}
+
+	key := CredentialKey(customerID, instanceName)
+	switch history.Status {
+	case db.StatusEnumPending, db.StatusEnumRunning:
+		return nil, ErrCredentialsNotReady
+	case db.StatusEnumCompleted:
+		// ok
+	default:
+		// Le job a échoué : le mot de passe généré ne sert plus à rien
+		if err := s.secrets.Delete(ctx, key); err != nil {
+			klog.Errorf("Failed to delete credentials of failed instance '%s': %v", instanceName, err)
+		}
+		return nil, ErrCredentialsUnavailable
+	}
+
+	value, err := s.secrets.Take(ctx, key)
+	if err != nil {
+		if errors.Is(err, secret.ErrSecretNotFound) {
+			return nil, ErrCredentialsAlreadyRetrieved
+		}
+		return nil, fmt.Errorf("failed_to_read_secret: %w", err)
+	}
+
+	var creds Credentials
+	if err := json.Unmarshal(value, &creds); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal credentials: %w", err)
+	}
+
+	klog.Infof("Credentials of instance '%s' retrieved by customer %s", instanceName, customerID)
+	return &creds, nil
+}
@@ -0,0 +1,68 @@
+package service_engine
+
+import (
+	"errors"
+	"testing"
+
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+)
+
+func TestEnsureSecretsProtected(t *testing.T) {
+	spec := &awxclient.SurveySpec{
+		Spec: []awxclient.SurveyQuestion{
+			{Variable: "db_name", Type: "text"},
+			{Variable: "db_password", Type: "password"},
+			{Variable: "admin_password", Type: "text"},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		spec    *awxclient.SurveySpec
+		keys    []string
+		wantErr bool
+	}{
+		{name: "password question", spec: spec, keys: []string{"db_password"}},
+		{name: "no secret", spec: spec},
+		{name: "text question", spec: spec, keys: []string{"db_password", "admin_password"}, wantErr: true},
+		{name: "missing question", spec: spec, keys: []string{"replica_password"}, wantErr: true},
+		{name: "empty survey", spec: &awxclient.SurveySpec{}, keys: []string{"db_password"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := EnsureSecretsProtected(tt.spec, tt.keys...)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSecretVariablesNotProtected) {
+					t.Fatalf("error = %v, want ErrSecretVariablesNotProtected", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestRedactSecrets(t *testing.T) {
+	extraVars := map[string]interface{}{
+		"db_name":     "app",
+		"db_password": "s3cret",
+		"storage_gb":  10,
+	}
+
+	redacted := RedactSecrets(extraVars, "db_password", "absent_password")
+
+	if redacted["db_password"] != RedactedValue {
+		t.Fatalf("db_password = %v, want %q", redacted["db_password"], RedactedValue)
+	}
+	if redacted["db_name"] != "app" || redacted["storage_gb"] != 10 {
+		t.Fatalf("non secret vars changed: %v", redacted)
+	}
+	if _, ok := redacted["absent_password"]; ok {
+		t.Fatal("a secret key absent from the extra vars was added")
+	}
+	if extraVars["db_password"] != "s3cret" {
+		t.Fatal("RedactSecrets modified the extra vars sent to AWX")
+	}
+}
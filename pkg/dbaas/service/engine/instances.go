@@ -0,0 +1,347 @@
+package service_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"k8s.io/klog/v2"
+)
+
+type ProvisionRequest struct {
+	InstanceName string `json:"instance_name"`
+	Username     string `json:"username"`
+	// Version du moteur, la version par défaut de l'offre si vide
+	Version string `json:"version,omitempty"`
+	// Stockage demandé en Go, la valeur par défaut des quotas si 0
+	StorageGB int32 `json:"storage_gb,omitempty"`
+	// Template de provisioning choisi par le moteur appelant, celui de la configuration si vide
+	TemplateName string `json:"-"`
+}
+
+type OperationResponse struct {
+	InstanceName string `json:"instance_name"`
+	Username     string `json:"username,omitempty"`
+	JobID        int    `json:"job_id"`
+	Status       string `json:"status"`
+	CustomerID   string `json:"customer_id"`
+	// Lancement prévu d'une opération disruptive mise en attente de la fenêtre de maintenance
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
+}
+
+type InstanceResponse struct {
+	InstanceName string    `json:"instance_name"`
+	Engine       string    `json:"engine"`
+	Version      string    `json:"version,omitempty"`
+	Host         string    `json:"host,omitempty"`
+	Port         int32     `json:"port,omitempty"`
+	Username     string    `json:"username,omitempty"`
+	Status       string    `json:"status"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type HistoryEntry struct {
+	JobID        int64      `json:"job_id"`
+	Action       string     `json:"action"`
+	Status       string     `json:"status"`
+	TemplateName string     `json:"template_name,omitempty"`
+	ErrorMessage string     `json:"error_message,omitempty"`
+	CreatedBy    string     `json:"created_by"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+// Provision crée une instance du moteur avec le template de provisioning configuré.
+// Le mot de passe est généré côté serveur, récupérable une fois via GetCredentials.
+func (s *Service) Provision(ctx context.Context, customerID string, req ProvisionRequest, createdBy string) (*OperationResponse, error) {
+	version, err := s.validateProvisionRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EnsureInstanceNameAvailable(ctx, customerID, req.InstanceName); err != nil {
+		return nil, err
+	}
+	if req.StorageGB == 0 {
+		req.StorageGB = s.DefaultStorageGB()
+	}
+	if err := s.EnsureProvisionQuota(ctx, customerID, req.StorageGB); err != nil {
+		return nil, err
+	}
+
+	password, err := GeneratePassword()
+	if err != nil {
+		return nil, fmt.Errorf("password_generation_failed: %w", err)
+	}
+	if err := s.StoreCredentials(ctx, customerID, req.InstanceName, Credentials{Username: req.Username, Password: password}); err != nil {
+		return nil, fmt.Errorf("secret_store_failed: %w", err)
+	}
+
+	instance, err := s.queries.CreateDBInstance(ctx, db.CreateDBInstanceParams{
+		CustomerID:   customerID,
+		DbType:       s.engine.Name,
+		Version:      pgtype.Text{String: version, Valid: version != ""},
+		Username:     pgtype.Text{String: req.Username, Valid: true},
+		Status:       db.StatusEnumRunning,
+		InstanceName: req.InstanceName,
+		CreatedBy:    createdBy,
+		StorageGb:    req.StorageGB,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	extraVars := map[string]interface{}{
+		"instance_name": req.InstanceName,
+		"username":      req.Username,
+		"password":      password,
+		"customer_id":   customerID,
+		"storage_gb":    req.StorageGB,
+	}
+	if version != "" {
+		extraVars["version"] = version
+	}
+
+	templateName := req.TemplateName
+	if templateName == "" {
+		templateName = s.engine.Templates.Provision
+	}
+	history, err := s.LaunchOperation(ctx, OperationRequest{
+		Action:       db.ActionTypeEnumCreate,
+		TemplateName: templateName,
+		Instance:     instance,
+		ExtraVars:    extraVars,
+		SecretKeys:   []string{"password"},
+		CreatedBy:    createdBy,
+		OnDone:       s.UpdateInstanceStatusHook(instance.ID),
+	})
+	if err != nil {
+		// Libère le nom de l'instance et les identifiants générés
+		if updErr := s.queries.UpdateDBInstanceStatus(ctx, db.UpdateDBInstanceStatusParams{ID: instance.ID, Status: db.StatusEnumFailed}); updErr != nil {
+			klog.Errorf("Failed to mark %s instance '%s' as failed: %v", s.engine.Name, req.InstanceName, updErr)
+		}
+		if delErr := s.secrets.Delete(ctx, CredentialKey(customerID, req.InstanceName)); delErr != nil {
+			klog.Errorf("Failed to delete credentials after launch failure: %v", delErr)
+		}
+		return nil, err
+	}
+
+	return &OperationResponse{
+		InstanceName: req.InstanceName,
+		Username:     req.Username,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   customerID,
+	}, nil
+}
+
+// validateProvisionRequest applique les règles fixes et renvoie la version à déployer
+func (s *Service) validateProvisionRequest(req *ProvisionRequest) (string, error) {
+	verr := &ValidationError{}
+	ValidateInstanceName(verr, "instance_name", req.InstanceName)
+	ValidateUsername(verr, "username", req.Username, s.engine.ReservedUsernames...)
+	if req.StorageGB < 0 {
+		verr.Add("storage_gb", "must be positive")
+	}
+
+	version := req.Version
+	if len(s.engine.Versions) > 0 {
+		if version == "" {
+			version = s.engine.Versions[0]
+		} else if !slices.Contains(s.engine.Versions, version) {
+			verr.Add("version", fmt.Sprintf("%s: must be one of %v", ErrVersionNotSupported, s.engine.Versions))
+		}
+	}
+	return version, verr.OrNil()
+}
+
+// Delete supprime l'instance avec le template de suppression configuré ; l'instance est
+// retirée de db_instances une fois le job réussi. Une instance dont la création a échoué
+// est retirée directement.
+func (s *Service) Delete(ctx context.Context, customerID, instanceName, createdBy string) (*OperationResponse, error) {
+	instance, err := s.GetInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.EnsureNoOperationInProgress(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	if instance.Status == db.StatusEnumFailed {
+		if err := s.removeInstance(ctx, instance); err != nil {
+			return nil, err
+		}
+		return &OperationResponse{InstanceName: instanceName, Status: string(db.StatusEnumCompleted), CustomerID: customerID}, nil
+	}
+	if instance.Status != db.StatusEnumCompleted {
+		return nil, ErrInstanceNotReady
+	}
+	if s.engine.Templates.Delete == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, db.ActionTypeEnumDelete)
+	}
+
+	history, err := s.LaunchOperation(ctx, OperationRequest{
+		Action:       db.ActionTypeEnumDelete,
+		TemplateName: s.engine.Templates.Delete,
+		Instance:     instance,
+		ExtraVars: map[string]interface{}{
+			"instance_name": instanceName,
+			"customer_id":   customerID,
+		},
+		CreatedBy: createdBy,
+		OnDone:    s.removeInstanceHook(instance),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &OperationResponse{
+		InstanceName: instanceName,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   customerID,
+		ScheduledAt:  ScheduledAt(history),
+	}, nil
+}
+
+// removeInstanceHook retire l'instance une fois le job de suppression réussi
+func (s *Service) removeInstanceHook(instance db.DbInstance) JobCompletionHook {
+	return func(ctx context.Context, _ int32, status db.StatusEnum, _ *awxclient.Job) {
+		if status != db.StatusEnumCompleted {
+			klog.Warningf("Deletion of %s instance '%s' did not succeed (%s), instance kept", s.engine.Name, instance.InstanceName, status)
+			return
+		}
+		if err := s.removeInstance(ctx, instance); err != nil {
+			klog.Errorf("Failed to remove %s instance '%s': %v", s.engine.Name, instance.InstanceName, err)
+		}
+	}
+}
+
+func (s *Service) removeInstance(ctx context.Context, instance db.DbInstance) error {
+	if err := s.queries.SoftDeleteDBInstance(ctx, instance.ID); err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	if err := s.secrets.Delete(ctx, CredentialKey(instance.CustomerID, instance.InstanceName)); err != nil {
+		klog.Errorf("Failed to delete credentials of removed instance '%s': %v", instance.InstanceName, err)
+	}
+	if err := s.queries.CancelScheduledHistoryByInstance(ctx, db.CancelScheduledHistoryByInstanceParams{
+		CustomerID:   instance.CustomerID,
+		InstanceName: instance.InstanceName,
+	}); err != nil {
+		klog.Errorf("Failed to cancel scheduled operations of removed instance '%s': %v", instance.InstanceName, err)
+	}
+	klog.Infof("%s instance '%s' of customer %s removed", s.engine.Name, instance.InstanceName, instance.CustomerID)
+	return nil
+}
+
+// ListInstances renvoie les instances du client pour ce moteur
+func (s *Service) ListInstances(ctx context.Context, customerID string) ([]InstanceResponse, error) {
+	instances, err := s.queries.GetDBInstancesByCustomerAndType(ctx, db.GetDBInstancesByCustomerAndTypeParams{
+		CustomerID: customerID,
+		DbType:     s.engine.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	resp := make([]InstanceResponse, 0, len(instances))
+	for _, instance := range instances {
+		resp = append(resp, toInstanceResponse(instance))
+	}
+	return resp, nil
+}
+
+// DescribeInstance renvoie le détail d'une instance du client
+func (s *Service) DescribeInstance(ctx context.Context, customerID, instanceName string) (*InstanceResponse, error) {
+	instance, err := s.GetInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	resp := toInstanceResponse(instance)
+	return &resp, nil
+}
+
+// ListHistory renvoie les opérations AWX de l'instance, les plus récentes en premier
+func (s *Service) ListHistory(ctx context.Context, customerID, instanceName string) ([]HistoryEntry, error) {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.queries.GetHistoryByInstance(ctx, db.GetHistoryByInstanceParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	entries := make([]HistoryEntry, 0, len(rows))
+	for _, h := range rows {
+		entry := HistoryEntry{
+			JobID:        h.AwxJobID.Int64,
+			Action:       string(h.ActionType),
+			Status:       string(h.Status),
+			TemplateName: h.AwxTemplateName.String,
+			ErrorMessage: h.ErrorMessage.String,
+			CreatedBy:    h.CreatedBy,
+			CreatedAt:    h.CreatedAt.Time,
+		}
+		if h.CompletedAt.Valid {
+			entry.CompletedAt = &h.CompletedAt.Time
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// GetJobStatus renvoie le statut AWX d'un job lancé pour le client par ce moteur
+func (s *Service) GetJobStatus(ctx context.Context, customerID string, jobID int) (*OperationResponse, error) {
+	history, err := s.queries.GetHistoryByJobID(ctx, pgtype.Int8{Int64: int64(jobID), Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	// Même réponse qu'un job inexistant pour les jobs des autres clients et des autres moteurs
+	if history.CustomerID != customerID || !s.ownsTemplate(history.AwxTemplateName.String) {
+		return nil, ErrJobNotFound
+	}
+
+	job, err := s.awxClient.GetUnifiedJob(ctx, awxclient.JobKind(history.AwxJobType), jobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+
+	return &OperationResponse{
+		InstanceName: history.InstanceName,
+		Username:     history.Username.String,
+		JobID:        job.ID,
+		Status:       string(jobStatus(job.Status)),
+		CustomerID:   customerID,
+	}, nil
+}
+
+func (s *Service) ownsTemplate(templateName string) bool {
+	return templateName != "" && (templateName == s.engine.Templates.Provision || templateName == s.engine.Templates.Delete)
+}
+
+func toInstanceResponse(instance db.DbInstance) InstanceResponse {
+	return InstanceResponse{
+		InstanceName: instance.InstanceName,
+		Engine:       instance.DbType,
+		Version:      instance.Version.String,
+		Host:         instance.Host.String,
+		Port:         instance.Port.Int32,
+		Username:     instance.Username.String,
+		Status:       string(instance.Status),
+		CreatedBy:    instance.CreatedBy,
+		CreatedAt:    instance.CreatedAt.Time,
+	}
+}
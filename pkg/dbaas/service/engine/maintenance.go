@@ -0,0 +1,584 @@
+package service_engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"k8s.io/klog/v2"
+)
+
+const (
+	minMaintenanceMinutes = 30
+	maxMaintenanceMinutes = 12 * 60
+)
+
+var (
+	ErrMaintenanceWindowNotFound  = errors.New("maintenance window not found")
+	ErrScheduledOperationNotFound = errors.New("scheduled operation not found")
+	ErrOperationAlreadyScheduled  = errors.New("an operation is already scheduled on this instance")
+	// Les extra vars d'une opération planifiée sont conservées dans awx_history, sans secret
+	ErrScheduledSecrets = errors.New("disruptive template cannot receive secret variables")
+)
+
+// HookResolver reconstruit le suivi propre à une opération lancée plus tard (planifiée ou relancée)
+type HookResolver func(ctx context.Context, history *db.AwxHistory) JobCompletionHook
+
+type MaintenanceWindowRequest struct {
+	// Jours de la fenêtre, 0 pour dimanche
+	DaysOfWeek      []int  `json:"days_of_week"`
+	StartTime       string `json:"start_time"` // HH:MM
+	DurationMinutes int    `json:"duration_minutes"`
+	// Fuseau IANA (Europe/Paris...), UTC par défaut
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type MaintenanceWindowResponse struct {
+	InstanceName    string    `json:"instance_name"`
+	DaysOfWeek      []int     `json:"days_of_week"`
+	StartTime       string    `json:"start_time"`
+	DurationMinutes int32     `json:"duration_minutes"`
+	Timezone        string    `json:"timezone"`
+	NextWindowAt    time.Time `json:"next_window_at"`
+}
+
+type ScheduledOperation struct {
+	ID           int32     `json:"id"`
+	InstanceName string    `json:"instance_name"`
+	Action       string    `json:"action"`
+	TemplateName string    `json:"template_name"`
+	Status       string    `json:"status"`
+	ScheduledAt  time.Time `json:"scheduled_at"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// maintenanceWindow fenêtre hebdomadaire, dans le fuseau du client
+type maintenanceWindow struct {
+	days     []time.Weekday
+	hour     int
+	minute   int
+	duration time.Duration
+	loc      *time.Location
+}
+
+// startOn renvoie le début de la fenêtre le jour de day
+func (w maintenanceWindow) startOn(day time.Time) time.Time {
+	y, m, d := day.Date()
+	return time.Date(y, m, d, w.hour, w.minute, 0, 0, w.loc)
+}
+
+// contains indique si t est dans la fenêtre, y compris une fenêtre commencée la veille
+func (w maintenanceWindow) contains(t time.Time) bool {
+	t = t.In(w.loc)
+	for d := -1; d <= 0; d++ {
+		start := w.startOn(t.AddDate(0, 0, d))
+		if slices.Contains(w.days, start.Weekday()) && !t.Before(start) && t.Before(start.Add(w.duration)) {
+			return true
+		}
+	}
+	return false
+}
+
+// next renvoie le début de la prochaine fenêtre à partir de t
+func (w maintenanceWindow) next(t time.Time) time.Time {
+	t = t.In(w.loc)
+	for d := 0; d <= 7; d++ {
+		start := w.startOn(t.AddDate(0, 0, d))
+		if slices.Contains(w.days, start.Weekday()) && !start.Before(t) {
+			return start
+		}
+	}
+	return t
+}
+
+func toMaintenanceWindow(row db.DbMaintenanceWindow) (maintenanceWindow, error) {
+	loc, err := time.LoadLocation(row.Timezone)
+	if err != nil {
+		return maintenanceWindow{}, fmt.Errorf("invalid maintenance timezone '%s': %w", row.Timezone, err)
+	}
+	minutes := int(row.StartTime.Microseconds / int64(time.Minute/time.Microsecond))
+	w := maintenanceWindow{
+		hour:     minutes / 60,
+		minute:   minutes % 60,
+		duration: time.Duration(row.DurationMinutes) * time.Minute,
+		loc:      loc,
+	}
+	for _, d := range row.DaysOfWeek {
+		w.days = append(w.days, time.Weekday(d))
+	}
+	return w, nil
+}
+
+// SetHookResolver remplace la reconstruction des hooks des opérations planifiées
+func (s *Service) SetHookResolver(resolver HookResolver) {
+	s.hookResolver = resolver
+}
+
+// IsDisruptive indique si le template ne doit être lancé que dans la fenêtre de maintenance
+func (s *Service) IsDisruptive(templateName string) bool {
+	return slices.Contains(s.engine.DisruptiveTemplates, templateName)
+}
+
+// GetMaintenanceWindow renvoie la fenêtre de maintenance de l'instance
+func (s *Service) GetMaintenanceWindow(ctx context.Context, customerID, instanceName string) (*MaintenanceWindowResponse, error) {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+	row, err := s.queries.GetMaintenanceWindow(ctx, db.GetMaintenanceWindowParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMaintenanceWindowNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return toMaintenanceWindowResponse(row)
+}
+
+// SetMaintenanceWindow crée ou remplace la fenêtre de maintenance de l'instance ;
+// les opérations déjà planifiées sont replanifiées sur la nouvelle fenêtre
+func (s *Service) SetMaintenanceWindow(ctx context.Context, customerID, instanceName string, req MaintenanceWindowRequest, createdBy string) (*MaintenanceWindowResponse, error) {
+	verr := &ValidationError{}
+	if len(req.DaysOfWeek) == 0 {
+		verr.Add("days_of_week", "is required")
+	}
+	days := make([]int16, 0, len(req.DaysOfWeek))
+	for _, d := range req.DaysOfWeek {
+		if d < 0 || d > 6 {
+			verr.Add("days_of_week", "must contain days between 0 (sunday) and 6 (saturday)")
+			break
+		}
+		if !slices.Contains(days, int16(d)) {
+			days = append(days, int16(d))
+		}
+	}
+	start, err := time.Parse("15:04", req.StartTime)
+	if err != nil {
+		verr.Add("start_time", "must be formatted as HH:MM")
+	}
+	if req.DurationMinutes < minMaintenanceMinutes || req.DurationMinutes > maxMaintenanceMinutes {
+		verr.Add("duration_minutes", fmt.Sprintf("must be between %d and %d", minMaintenanceMinutes, maxMaintenanceMinutes))
+	}
+	if req.Timezone == "" {
+		req.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		verr.Add("timezone", "must be a valid IANA time zone")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	slices.Sort(days)
+
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	startMinutes := int64(start.Hour()*60 + start.Minute())
+	row, err := s.queries.UpsertMaintenanceWindow(ctx, db.UpsertMaintenanceWindowParams{
+		CustomerID:      customerID,
+		InstanceName:    instanceName,
+		DaysOfWeek:      days,
+		StartTime:       pgtype.Time{Microseconds: startMinutes * int64(time.Minute/time.Microsecond), Valid: true},
+		DurationMinutes: int32(req.DurationMinutes),
+		Timezone:        req.Timezone,
+		CreatedBy:       createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	resp, err := toMaintenanceWindowResponse(row)
+	if err != nil {
+		return nil, err
+	}
+	// Fenêtre déjà ouverte : lancement au prochain passage du scheduler
+	at := resp.NextWindowAt
+	if w, err := toMaintenanceWindow(row); err == nil && w.contains(time.Now()) {
+		at = time.Now()
+	}
+	s.rescheduleOperations(ctx, customerID, instanceName, at)
+
+	klog.Infof("Maintenance window of %s instance '%s' set by %s: days=%v start=%s duration=%dmin tz=%s", s.engine.Name, instanceName, createdBy, days, req.StartTime, req.DurationMinutes, req.Timezone)
+	return resp, nil
+}
+
+// DeleteMaintenanceWindow supprime la fenêtre ; les opérations planifiées sont lancées au prochain passage du scheduler
+func (s *Service) DeleteMaintenanceWindow(ctx context.Context, customerID, instanceName string) error {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return err
+	}
+	deleted, err := s.queries.DeleteMaintenanceWindow(ctx, db.DeleteMaintenanceWindowParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	if deleted == 0 {
+		return ErrMaintenanceWindowNotFound
+	}
+	s.rescheduleOperations(ctx, customerID, instanceName, time.Now())
+	return nil
+}
+
+// ListScheduledOperations renvoie les opérations de l'instance en attente de la fenêtre de maintenance
+func (s *Service) ListScheduledOperations(ctx context.Context, customerID, instanceName string) ([]ScheduledOperation, error) {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListScheduledHistoryByInstance(ctx, db.ListScheduledHistoryByInstanceParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	out := make([]ScheduledOperation, 0, len(rows))
+	for _, h := range rows {
+		out = append(out, toScheduledOperation(h))
+	}
+	return out, nil
+}
+
+// CancelScheduledOperation annule une opération qui n'a pas encore été lancée dans AWX
+func (s *Service) CancelScheduledOperation(ctx context.Context, customerID, instanceName string, id int32) (*ScheduledOperation, error) {
+	if _, err := s.GetInstance(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+	history, err := s.queries.CancelScheduledHistory(ctx, db.CancelScheduledHistoryParams{
+		ID:           id,
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrScheduledOperationNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	klog.Infof("Scheduled %s operation %d on instance '%s' canceled", history.ActionType, history.ID, instanceName)
+	op := toScheduledOperation(history)
+	return &op, nil
+}
+
+// maintenanceWindow renvoie la fenêtre de l'instance, nil si le client n'en a pas défini
+func (s *Service) maintenanceWindow(ctx context.Context, customerID, instanceName string) (*maintenanceWindow, error) {
+	row, err := s.queries.GetMaintenanceWindow(ctx, db.GetMaintenanceWindowParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	w, err := toMaintenanceWindow(row)
+	if err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// deferToMaintenanceWindow planifie l'opération si son template est disruptif et que la fenêtre de
+// maintenance de l'instance est fermée ; renvoie nil si l'opération peut être lancée tout de suite
+func (s *Service) deferToMaintenanceWindow(ctx context.Context, op OperationRequest, template *awxclient.TemplateRef) (*db.AwxHistory, error) {
+	if !s.IsDisruptive(op.TemplateName) {
+		return nil, nil
+	}
+	window, err := s.maintenanceWindow(ctx, op.Instance.CustomerID, op.Instance.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); window != nil && !window.contains(now) {
+		return s.scheduleOperation(ctx, op, template, window.next(now))
+	}
+	return nil, nil
+}
+
+// DeferRelaunch applique la fenêtre de maintenance à la relance d'un job : une opération disruptive
+// relancée hors de la fenêtre est planifiée avec ses extra vars d'origine. Renvoie nil si la relance
+// peut être faite immédiatement.
+func (s *Service) DeferRelaunch(ctx context.Context, original *db.AwxHistory, createdBy string) (*db.AwxHistory, error) {
+	if !s.IsDisruptive(original.AwxTemplateName.String) {
+		return nil, nil
+	}
+	instance, err := s.GetInstance(ctx, original.CustomerID, original.InstanceName)
+	if err != nil {
+		return nil, err
+	}
+	var extraVars map[string]interface{}
+	if err := json.Unmarshal(original.ExtraVars, &extraVars); err != nil {
+		return nil, fmt.Errorf("invalid extra_vars: %w", err)
+	}
+	template, err := s.awxClient.ResolveTemplate(ctx, original.AwxTemplateName.String)
+	if err != nil {
+		return nil, fmt.Errorf("template_name_not_found: %w", err)
+	}
+	return s.deferToMaintenanceWindow(ctx, OperationRequest{
+		Action:       original.ActionType,
+		TemplateName: original.AwxTemplateName.String,
+		Instance:     instance,
+		ExtraVars:    extraVars,
+		// Les secrets masqués dans awx_history ne peuvent être relancés depuis le scheduler
+		SecretKeys: redactedKeys(extraVars),
+		CreatedBy:  createdBy,
+	}, template)
+}
+
+// redactedKeys renvoie les extra vars masquées par RedactSecrets, triées
+func redactedKeys(extraVars map[string]interface{}) []string {
+	var keys []string
+	for k, v := range extraVars {
+		if v == RedactedValue {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// scheduleOperation enregistre l'opération dans awx_history (status scheduled) sans lancer de job
+func (s *Service) scheduleOperation(ctx context.Context, op OperationRequest, template *awxclient.TemplateRef, at time.Time) (*db.AwxHistory, error) {
+	if len(op.SecretKeys) > 0 {
+		return nil, ErrScheduledSecrets
+	}
+	scheduled, err := s.queries.ListScheduledHistoryByInstance(ctx, db.ListScheduledHistoryByInstanceParams{
+		CustomerID:   op.Instance.CustomerID,
+		InstanceName: op.Instance.InstanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	if len(scheduled) > 0 {
+		return nil, ErrOperationAlreadyScheduled
+	}
+
+	extraVarsJSON, err := json.Marshal(op.ExtraVars)
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_marshal_extra_vars: %w", err)
+	}
+	history, err := s.queries.CreateHistory(ctx, db.CreateHistoryParams{
+		InstanceName:    op.Instance.InstanceName,
+		CustomerID:      op.Instance.CustomerID,
+		AwxTemplateName: pgtype.Text{String: op.TemplateName, Valid: true},
+		AwxTemplateID:   pgtype.Int4{Int32: int32(template.ID), Valid: true},
+		ActionType:      op.Action,
+		Status:          db.StatusEnumScheduled,
+		Username:        op.Instance.Username,
+		ExtraVars:       extraVarsJSON,
+		CreatedBy:       op.CreatedBy,
+		AwxJobType:      string(template.Kind.JobKind()),
+		ScheduledAt:     pgtype.Timestamptz{Time: at, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	klog.Infof("%s on instance '%s' of customer %s scheduled at %s (maintenance window)", op.Action, op.Instance.InstanceName, op.Instance.CustomerID, at.Format(time.RFC3339))
+	return &history, nil
+}
+
+// rescheduleOperations déplace les opérations planifiées de l'instance au début de la fenêtre
+func (s *Service) rescheduleOperations(ctx context.Context, customerID, instanceName string, at time.Time) {
+	scheduled, err := s.queries.ListScheduledHistoryByInstance(ctx, db.ListScheduledHistoryByInstanceParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		klog.Errorf("Failed to list scheduled operations of instance '%s': %v", instanceName, err)
+		return
+	}
+	for _, h := range scheduled {
+		s.reschedule(ctx, h, at)
+	}
+}
+
+func (s *Service) reschedule(ctx context.Context, history db.AwxHistory, at time.Time) {
+	if err := s.queries.RescheduleHistory(ctx, db.RescheduleHistoryParams{
+		ID:          history.ID,
+		ScheduledAt: pgtype.Timestamptz{Time: at, Valid: true},
+	}); err != nil {
+		klog.Errorf("Failed to reschedule operation %d: %v", history.ID, err)
+	}
+}
+
+// RunDueOperations lance les opérations planifiées dont la fenêtre de maintenance est ouverte
+func (s *Service) RunDueOperations(ctx context.Context) {
+	due, err := s.queries.ClaimDueScheduledHistory(ctx, s.engine.Name)
+	if err != nil {
+		klog.Errorf("Failed to claim due %s operations: %v", s.engine.Name, err)
+		return
+	}
+	for _, h := range due {
+		s.launchScheduled(ctx, h)
+	}
+}
+
+// RunScheduler lance les opérations planifiées à chaque intervalle, jusqu'à l'annulation du contexte
+func (s *Service) RunScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RunDueOperations(ctx)
+		}
+	}
+}
+
+// launchScheduled lance le job AWX d'une opération planifiée, ou la replanifie si la fenêtre
+// est passée ou si l'instance ou les quotas ne le permettent pas encore
+func (s *Service) launchScheduled(ctx context.Context, history db.AwxHistory) {
+	now := time.Now()
+	window, err := s.maintenanceWindow(ctx, history.CustomerID, history.InstanceName)
+	if err != nil {
+		klog.Errorf("Failed to get maintenance window of instance '%s': %v", history.InstanceName, err)
+		s.reschedule(ctx, history, history.ScheduledAt.Time)
+		return
+	}
+	if window != nil && !window.contains(now) {
+		// Fenêtre manquée (scheduler arrêté), on attend la suivante
+		next := window.next(now)
+		klog.Warningf("Maintenance window of instance '%s' missed, operation %d rescheduled at %s", history.InstanceName, history.ID, next.Format(time.RFC3339))
+		s.reschedule(ctx, history, next)
+		return
+	}
+	// Nouvelle tentative au prochain passage, tant que la fenêtre est ouverte. Le verrou de l'instance
+	// écarte les lancements simultanés par l'API jusqu'à l'enregistrement du job
+	unlock, err := s.LockInstance(ctx, history.CustomerID, history.InstanceName)
+	if err != nil {
+		klog.Infof("Scheduled operation %d on instance '%s' delayed: %v", history.ID, history.InstanceName, err)
+		s.reschedule(ctx, history, history.ScheduledAt.Time)
+		return
+	}
+	defer unlock()
+	if err := s.EnsureNoOperationInProgress(ctx, history.CustomerID, history.InstanceName); err != nil {
+		klog.Infof("Scheduled operation %d on instance '%s' delayed: %v", history.ID, history.InstanceName, err)
+		s.reschedule(ctx, history, history.ScheduledAt.Time)
+		return
+	}
+	if err := s.EnsureJobQuota(ctx, history.CustomerID); err != nil {
+		klog.Infof("Scheduled operation %d on instance '%s' delayed: %v", history.ID, history.InstanceName, err)
+		s.reschedule(ctx, history, history.ScheduledAt.Time)
+		return
+	}
+
+	var extraVars map[string]interface{}
+	if err := json.Unmarshal(history.ExtraVars, &extraVars); err != nil {
+		s.failScheduled(ctx, history, fmt.Errorf("invalid extra_vars: %w", err))
+		return
+	}
+	template, err := s.awxClient.ResolveTemplate(ctx, history.AwxTemplateName.String)
+	if err != nil {
+		s.failScheduled(ctx, history, fmt.Errorf("template_name_not_found: %w", err))
+		return
+	}
+	jobID, err := s.awxClient.Launch(ctx, template, extraVars)
+	if err != nil {
+		s.failScheduled(ctx, history, fmt.Errorf("awx_launch_failed: %w", err))
+		return
+	}
+
+	if err := s.queries.MarkScheduledHistoryLaunched(ctx, db.MarkScheduledHistoryLaunchedParams{
+		ID:            history.ID,
+		AwxJobID:      pgtype.Int8{Int64: int64(jobID), Valid: true},
+		AwxTemplateID: pgtype.Int4{Int32: int32(template.ID), Valid: true},
+		AwxJobType:    string(template.Kind.JobKind()),
+	}); err != nil {
+		klog.Errorf("Scheduled operation %d launched as job %d but failed to update DB: %v", history.ID, jobID, err)
+	}
+	history.AwxJobID = pgtype.Int8{Int64: int64(jobID), Valid: true}
+
+	var onDone JobCompletionHook
+	if s.hookResolver != nil {
+		onDone = s.hookResolver(ctx, &history)
+	}
+	if err := s.MonitorJob(context.Background(), template.Kind.JobKind(), jobID, history.ID, onDone); err != nil {
+		klog.Errorf("Failed to start job monitoring for job %d: %v", jobID, err)
+	}
+	klog.Infof("Scheduled %s on instance '%s' launched in maintenance window, job_id=%d", history.ActionType, history.InstanceName, jobID)
+}
+
+func (s *Service) failScheduled(ctx context.Context, history db.AwxHistory, cause error) {
+	klog.Errorf("Scheduled operation %d on instance '%s' failed to launch: %v", history.ID, history.InstanceName, cause)
+	if err := s.queries.UpdateHistoryCompletion(ctx, db.UpdateHistoryCompletionParams{
+		ID:           history.ID,
+		Status:       db.StatusEnumFailed,
+		ErrorMessage: pgtype.Text{String: cause.Error(), Valid: true},
+	}); err != nil {
+		klog.Errorf("Failed to mark scheduled operation %d as failed: %v", history.ID, err)
+	}
+}
+
+// defaultHookResolver reconstruit les hooks des opérations de la couche commune
+func (s *Service) defaultHookResolver(ctx context.Context, history *db.AwxHistory) JobCompletionHook {
+	instance, err := s.GetInstance(ctx, history.CustomerID, history.InstanceName)
+	if err != nil {
+		klog.Errorf("Instance '%s' of operation %d not found: %v", history.InstanceName, history.ID, err)
+		return nil
+	}
+	switch history.ActionType {
+	case db.ActionTypeEnumCreate:
+		return s.UpdateInstanceStatusHook(instance.ID)
+	case db.ActionTypeEnumDelete:
+		return s.removeInstanceHook(instance)
+	}
+	return nil
+}
+
+// ScheduledAt renvoie la date de lancement prévue d'une opération planifiée, nil si elle a été lancée
+func ScheduledAt(history *db.AwxHistory) *time.Time {
+	if history.Status != db.StatusEnumScheduled || !history.ScheduledAt.Valid {
+		return nil
+	}
+	return &history.ScheduledAt.Time
+}
+
+func toMaintenanceWindowResponse(row db.DbMaintenanceWindow) (*MaintenanceWindowResponse, error) {
+	w, err := toMaintenanceWindow(row)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]int, 0, len(row.DaysOfWeek))
+	for _, d := range row.DaysOfWeek {
+		days = append(days, int(d))
+	}
+	return &MaintenanceWindowResponse{
+		InstanceName:    row.InstanceName,
+		DaysOfWeek:      days,
+		StartTime:       fmt.Sprintf("%02d:%02d", w.hour, w.minute),
+		DurationMinutes: row.DurationMinutes,
+		Timezone:        row.Timezone,
+		NextWindowAt:    w.next(time.Now()),
+	}, nil
+}
+
+func toScheduledOperation(h db.AwxHistory) ScheduledOperation {
+	return ScheduledOperation{
+		ID:           h.ID,
+		InstanceName: h.InstanceName,
+		Action:       string(h.ActionType),
+		TemplateName: h.AwxTemplateName.String,
+		Status:       string(h.Status),
+		ScheduledAt:  h.ScheduledAt.Time,
+		CreatedBy:    h.CreatedBy,
+		CreatedAt:    h.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,110 @@
+package service_engine
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func testWindow(t *testing.T, tz string, hour, minute, durationMinutes int, days ...time.Weekday) maintenanceWindow {
+	t.Helper()
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		t.Fatalf("LoadLocation(%q): %v", tz, err)
+	}
+	return maintenanceWindow{
+		days:     days,
+		hour:     hour,
+		minute:   minute,
+		duration: time.Duration(durationMinutes) * time.Minute,
+		loc:      loc,
+	}
+}
+
+func mustParse(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", value, err)
+	}
+	return parsed
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	tests := []struct {
+		name   string
+		window maintenanceWindow
+		at     string
+		want   bool
+	}{
+		// 2026-10-17 est un samedi
+		{name: "before start", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), at: "2026-10-17T01:59:59Z", want: false},
+		{name: "at start", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), at: "2026-10-17T02:00:00Z", want: true},
+		{name: "end excluded", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), at: "2026-10-17T03:00:00Z", want: false},
+		{name: "other day", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), at: "2026-10-18T02:30:00Z", want: false},
+		{name: "across midnight before", window: testWindow(t, "UTC", 23, 0, 120, time.Saturday), at: "2026-10-17T23:30:00Z", want: true},
+		{name: "across midnight after", window: testWindow(t, "UTC", 23, 0, 120, time.Saturday), at: "2026-10-18T00:30:00Z", want: true},
+		{name: "across midnight ended", window: testWindow(t, "UTC", 23, 0, 120, time.Saturday), at: "2026-10-18T01:00:00Z", want: false},
+		{name: "across midnight start day only", window: testWindow(t, "UTC", 23, 0, 120, time.Sunday), at: "2026-10-18T00:30:00Z", want: false},
+		{name: "timezone inside", window: testWindow(t, "America/New_York", 9, 0, 60, time.Monday), at: "2026-10-19T13:30:00Z", want: true},
+		{name: "timezone in UTC hours", window: testWindow(t, "America/New_York", 9, 0, 60, time.Monday), at: "2026-10-19T09:30:00Z", want: false},
+		{name: "timezone crosses UTC midnight", window: testWindow(t, "Asia/Tokyo", 1, 0, 60, time.Monday), at: "2026-10-18T16:30:00Z", want: true},
+		// Passage à l'heure d'été à Paris le 29/03/2026 à 02:00 : la fenêtre dure 3 heures réelles
+		{name: "dst spring inside", window: testWindow(t, "Europe/Paris", 1, 0, 180, time.Sunday), at: "2026-03-29T02:30:00Z", want: true},
+		{name: "dst spring ended", window: testWindow(t, "Europe/Paris", 1, 0, 180, time.Sunday), at: "2026-03-29T03:00:00Z", want: false},
+		// Retour à l'heure d'hiver le 25/10/2026 à 03:00 : 04:00 CET = 03:00 UTC
+		{name: "dst autumn inside", window: testWindow(t, "Europe/Paris", 4, 0, 60, time.Sunday), at: "2026-10-25T03:30:00Z", want: true},
+		{name: "dst autumn summer offset", window: testWindow(t, "Europe/Paris", 4, 0, 60, time.Sunday), at: "2026-10-25T02:30:00Z", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.window.contains(mustParse(t, tt.at)); got != tt.want {
+				t.Fatalf("contains(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindowNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		window maintenanceWindow
+		from   string
+		want   string
+	}{
+		{name: "later today", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), from: "2026-10-17T01:00:00Z", want: "2026-10-17T02:00:00Z"},
+		{name: "at start", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), from: "2026-10-17T02:00:00Z", want: "2026-10-17T02:00:00Z"},
+		{name: "started today", window: testWindow(t, "UTC", 2, 0, 60, time.Saturday), from: "2026-10-17T02:30:00Z", want: "2026-10-24T02:00:00Z"},
+		{name: "closest day", window: testWindow(t, "UTC", 2, 0, 60, time.Monday, time.Thursday), from: "2026-10-20T12:00:00Z", want: "2026-10-22T02:00:00Z"},
+		{name: "across midnight", window: testWindow(t, "UTC", 23, 30, 120, time.Sunday), from: "2026-10-18T23:45:00Z", want: "2026-10-25T23:30:00Z"},
+		{name: "timezone", window: testWindow(t, "America/New_York", 9, 0, 60, time.Monday), from: "2026-10-18T12:00:00Z", want: "2026-10-19T13:00:00Z"},
+		// Fenêtre à 03:00 le jour du passage à l'heure d'été : 03:00 CEST = 01:00 UTC
+		{name: "dst spring", window: testWindow(t, "Europe/Paris", 3, 0, 60, time.Sunday), from: "2026-03-28T12:00:00Z", want: "2026-03-29T01:00:00Z"},
+		// Le dimanche suivant le retour à l'heure d'hiver, 03:00 CET = 02:00 UTC
+		{name: "dst autumn", window: testWindow(t, "Europe/Paris", 3, 0, 60, time.Sunday), from: "2026-10-24T12:00:00Z", want: "2026-10-25T02:00:00Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.window.next(mustParse(t, tt.from))
+			if want := mustParse(t, tt.want); !got.Equal(want) {
+				t.Fatalf("next(%s) = %s, want %s", tt.from, got.UTC().Format(time.RFC3339), tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactedKeys(t *testing.T) {
+	// Extra vars d'une rotation relue depuis awx_history
+	extraVars := RedactSecrets(map[string]interface{}{
+		"instance_name": "pg1",
+		"username":      "app",
+		"password":      "secret",
+		"replicas":      float64(2),
+	}, "password")
+	if got := redactedKeys(extraVars); !slices.Equal(got, []string{"password"}) {
+		t.Fatalf("redactedKeys() = %v, want [password]", got)
+	}
+	if got := redactedKeys(map[string]interface{}{"replicas": float64(3)}); len(got) != 0 {
+		t.Fatalf("redactedKeys() = %v, want none", got)
+	}
+}
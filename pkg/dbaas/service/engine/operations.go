@@ -0,0 +1,211 @@
+package service_engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"k8s.io/klog/v2"
+)
+
+// OperationRequest décrit une opération AWX sur une instance existante (rotation, backup...)
+type OperationRequest struct {
+	Action       db.ActionTypeEnum
+	TemplateName string
+	Instance     db.DbInstance
+	ExtraVars    map[string]interface{}
+	// SecretKeys sont masquées dans awx_history et doivent être des questions password du survey
+	SecretKeys []string
+	CreatedBy  string
+	OnDone     JobCompletionHook
+}
+
+// LaunchOperation valide les extra vars contre le survey, lance le template, trace l'action dans awx_history
+// et démarre le suivi du job. Un template disruptif hors de la fenêtre de maintenance de l'instance est
+// seulement planifié (status scheduled) et lancé par le scheduler à l'ouverture de la fenêtre.
+func (s *Service) LaunchOperation(ctx context.Context, op OperationRequest) (*db.AwxHistory, error) {
+	if op.TemplateName == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, op.Action)
+	}
+
+	template, err := s.awxClient.ResolveTemplate(ctx, op.TemplateName)
+	if err != nil {
+		return nil, fmt.Errorf("template_name_not_found: %w", err)
+	}
+
+	spec, err := s.awxClient.GetSurveySpec(ctx, template)
+	if err != nil {
+		return nil, fmt.Errorf("survey_spec_failed: %w", err)
+	}
+	if err := ValidateExtraVars(spec, op.ExtraVars); err != nil {
+		return nil, err
+	}
+	if err := EnsureSecretsProtected(spec, op.SecretKeys...); err != nil {
+		return nil, err
+	}
+
+	if scheduled, err := s.deferToMaintenanceWindow(ctx, op, template); err != nil || scheduled != nil {
+		return scheduled, err
+	}
+	if err := s.EnsureJobQuota(ctx, op.Instance.CustomerID); err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Launching %s %s '%s' (%d) for instance '%s'", op.Action, template.Kind, op.TemplateName, template.ID, op.Instance.InstanceName)
+	jobID, err := s.awxClient.Launch(ctx, template, op.ExtraVars)
+	if err != nil {
+		klog.Errorf("Failed to launch %s job: %v", op.Action, err)
+		return nil, fmt.Errorf("awx_launch_failed: %w", err)
+	}
+
+	extraVarsJSON, err := json.Marshal(RedactSecrets(op.ExtraVars, op.SecretKeys...))
+	if err != nil {
+		return nil, fmt.Errorf("failed_to_marshal_extra_vars: %w", err)
+	}
+
+	historyRecord, err := s.queries.CreateHistory(ctx, db.CreateHistoryParams{
+		InstanceName:    op.Instance.InstanceName,
+		CustomerID:      op.Instance.CustomerID,
+		AwxJobID:        pgtype.Int8{Int64: int64(jobID), Valid: true},
+		AwxTemplateName: pgtype.Text{String: op.TemplateName, Valid: true},
+		AwxTemplateID:   pgtype.Int4{Int32: int32(template.ID), Valid: true},
+		ActionType:      op.Action,
+		Status:          db.StatusEnumRunning,
+		Username:        op.Instance.Username,
+		ExtraVars:       extraVarsJSON,
+		CreatedBy:       op.CreatedBy,
+		AwxJobType:      string(template.Kind.JobKind()),
+	})
+	if err != nil {
+		klog.Errorf("%s job launched but failed to insert into DB: %v", op.Action, err)
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	if err := s.MonitorJob(context.Background(), template.Kind.JobKind(), jobID, historyRecord.ID, op.OnDone); err != nil {
+		klog.Errorf("Failed to start job monitoring for job %d: %v", jobID, err)
+	}
+
+	klog.Infof("%s started for instance '%s' of customer %s, job_id=%d", op.Action, op.Instance.InstanceName, op.Instance.CustomerID, jobID)
+	return &historyRecord, nil
+}
+
+// JobKind renvoie le type (job ou workflow) d'un job lancé par l'API, job par défaut
+func (s *Service) JobKind(ctx context.Context, jobID int) awxclient.JobKind {
+	history, err := s.queries.GetHistoryByJobID(ctx, pgtype.Int8{Int64: int64(jobID), Valid: true})
+	if err != nil {
+		return awxclient.JobKindJob
+	}
+	return awxclient.JobKind(history.AwxJobType)
+}
+
+// JobCompletionHook est appelé une fois le job AWX terminé et awx_history mis à jour
+type JobCompletionHook func(ctx context.Context, historyID int32, status db.StatusEnum, job *awxclient.Job)
+
+// MonitorJob suit le job (ou workflow) AWX en arrière-plan, met à jour awx_history puis appelle onDone (optionnel)
+func (s *Service) MonitorJob(ctx context.Context, kind awxclient.JobKind, jobID int, historyID int32, onDone JobCompletionHook) error {
+	klog.Infof("Starting job monitoring for job ID: %d", jobID)
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				klog.Errorf("Job monitoring panic for job %d: %v", jobID, r)
+			}
+		}()
+
+		// MonitorJob blocks until job completes, so we call it in goroutine
+		finalJob, err := s.awxClient.MonitorUnifiedJob(ctx, kind, jobID)
+		if err != nil {
+			klog.Errorf("Failed to monitor job %d: %v", jobID, err)
+
+			// finalJob est nil si le suivi lui-même a échoué (AWX injoignable, contexte annulé)
+			status := db.StatusEnumError
+			awxStatus := db.AwxStatusEnumError
+			if finalJob != nil {
+				awxStatus = db.AwxStatusEnum(finalJob.Status)
+				// Un job encore en cours n'est plus suivi : il ne doit pas bloquer l'instance
+				if status = jobStatus(finalJob.Status); status == db.StatusEnumRunning {
+					status = db.StatusEnumError
+				}
+			}
+
+			// Update database with failed status even if monitoring failed
+			updateErr := s.queries.UpdateHistoryCompletion(ctx, db.UpdateHistoryCompletionParams{
+				ID:           historyID,
+				Status:       status,
+				AwxStatus:    db.NullAwxStatusEnum{AwxStatusEnum: awxStatus, Valid: true},
+				ErrorMessage: pgtype.Text{String: err.Error(), Valid: true},
+			})
+
+			if updateErr != nil {
+				klog.Errorf("Failed to update failed status for job %d: %v", jobID, updateErr)
+			}
+			if onDone != nil {
+				onDone(ctx, historyID, status, finalJob)
+			}
+			return
+		}
+
+		// Map AWX status to our enum
+		var status string
+		switch finalJob.Status {
+		case "successful":
+			status = "completed"
+		case "failed", "error", "canceled":
+			status = "failed"
+		default:
+			status = "failed" // Default to failed if unknown
+		}
+
+		// Update database with final status
+		err = s.queries.UpdateHistoryCompletion(ctx, db.UpdateHistoryCompletionParams{
+			ID:           historyID,
+			Status:       db.StatusEnum(status),
+			AwxStatus:    db.NullAwxStatusEnum{AwxStatusEnum: db.AwxStatusEnum(finalJob.Status), Valid: true},
+			ErrorMessage: pgtype.Text{String: "", Valid: finalJob.Status != "successful"},
+		})
+
+		if err != nil {
+			klog.Errorf("Failed to update completion for job %d: %v", jobID, err)
+		} else {
+			klog.Infof("Job %d completed with status: %s", jobID, status)
+		}
+		if onDone != nil {
+			onDone(ctx, historyID, db.StatusEnum(status), finalJob)
+		}
+	}()
+
+	return nil
+}
+
+// UpdateInstanceStatusHook reporte le statut final du job de création sur db_instances
+func (s *Service) UpdateInstanceStatusHook(instanceID int32) JobCompletionHook {
+	return func(ctx context.Context, _ int32, status db.StatusEnum, _ *awxclient.Job) {
+		if status != db.StatusEnumCompleted {
+			status = db.StatusEnumFailed
+		}
+		err := s.queries.UpdateDBInstanceStatus(ctx, db.UpdateDBInstanceStatusParams{
+			ID:     instanceID,
+			Status: status,
+		})
+		if err != nil {
+			klog.Errorf("Failed to update status of instance %d: %v", instanceID, err)
+		}
+	}
+}
+
+// jobStatus traduit le statut AWX d'un job dans le statut exposé aux clients
+func jobStatus(awxStatus string) db.StatusEnum {
+	switch awxStatus {
+	case "successful":
+		return db.StatusEnumCompleted
+	case "failed", "error", "canceled":
+		return db.StatusEnumFailed
+	case "pending", "waiting", "running":
+		return db.StatusEnumRunning
+	default:
+		return db.StatusEnum(awxStatus)
+	}
+}
@@ -0,0 +1,215 @@
+package service_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Ressources soumises à quota
+const (
+	QuotaInstances      = "instances"
+	QuotaStorageGB      = "storage_gb"
+	QuotaConcurrentJobs = "concurrent_jobs"
+)
+
+// QuotaError précise la ressource dont la limite serait dépassée
+type QuotaError struct {
+	Resource  string
+	Limit     int64
+	Used      int64
+	Requested int64
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("%s: %s limit %d, used %d, requested %d", ErrQuotaExceeded, e.Resource, e.Limit, e.Used, e.Requested)
+}
+
+func (e *QuotaError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// QuotaLimits limites d'un client, 0 pour illimité
+type QuotaLimits struct {
+	MaxInstances      int32 `json:"max_instances"`
+	MaxStorageGB      int32 `json:"max_storage_gb"`
+	MaxConcurrentJobs int32 `json:"max_concurrent_jobs"`
+}
+
+type QuotaUsage struct {
+	Instances      int64 `json:"instances"`
+	StorageGB      int64 `json:"storage_gb"`
+	ConcurrentJobs int64 `json:"concurrent_jobs"`
+}
+
+type QuotaResponse struct {
+	CustomerID string      `json:"customer_id"`
+	Limits     QuotaLimits `json:"limits"`
+	Usage      QuotaUsage  `json:"usage"`
+	// Limites propres au client, sinon valeurs par défaut
+	Custom bool `json:"custom"`
+}
+
+// QuotaRequest limites d'un client ; un champ absent reprend la valeur par défaut
+type QuotaRequest struct {
+	MaxInstances      *int32 `json:"max_instances"`
+	MaxStorageGB      *int32 `json:"max_storage_gb"`
+	MaxConcurrentJobs *int32 `json:"max_concurrent_jobs"`
+}
+
+// GetQuota renvoie les limites du client et sa consommation, tous moteurs confondus
+func (s *Service) GetQuota(ctx context.Context, customerID string) (*QuotaResponse, error) {
+	limits, custom, err := s.quotaLimits(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := s.quotaUsage(ctx, customerID)
+	if err != nil {
+		return nil, err
+	}
+	return &QuotaResponse{CustomerID: customerID, Limits: limits, Usage: usage, Custom: custom}, nil
+}
+
+// SetQuota définit les limites propres à un client
+func (s *Service) SetQuota(ctx context.Context, customerID string, req QuotaRequest, updatedBy string) (*QuotaResponse, error) {
+	verr := &ValidationError{}
+	for field, value := range map[string]*int32{
+		"max_instances":       req.MaxInstances,
+		"max_storage_gb":      req.MaxStorageGB,
+		"max_concurrent_jobs": req.MaxConcurrentJobs,
+	} {
+		if value != nil && *value < 0 {
+			verr.Add(field, "must be positive or 0 for unlimited")
+		}
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	_, err := s.queries.UpsertCustomerQuota(ctx, db.UpsertCustomerQuotaParams{
+		CustomerID:        customerID,
+		MaxInstances:      optionalInt4(req.MaxInstances),
+		MaxStorageGb:      optionalInt4(req.MaxStorageGB),
+		MaxConcurrentJobs: optionalInt4(req.MaxConcurrentJobs),
+		UpdatedBy:         updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return s.GetQuota(ctx, customerID)
+}
+
+// EnsureProvisionQuota vérifie qu'une nouvelle instance de storageGB Go et son job tiennent dans les quotas
+func (s *Service) EnsureProvisionQuota(ctx context.Context, customerID string, storageGB int32) error {
+	limits, _, err := s.quotaLimits(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	usage, err := s.quotaUsage(ctx, customerID)
+	if err != nil {
+		return err
+	}
+
+	if err := checkQuota(QuotaInstances, limits.MaxInstances, usage.Instances, 1); err != nil {
+		return err
+	}
+	if err := checkQuota(QuotaStorageGB, limits.MaxStorageGB, usage.StorageGB, int64(storageGB)); err != nil {
+		return err
+	}
+	return checkQuota(QuotaConcurrentJobs, limits.MaxConcurrentJobs, usage.ConcurrentJobs, 1)
+}
+
+// EnsureJobQuota vérifie que le client peut lancer un job de plus
+func (s *Service) EnsureJobQuota(ctx context.Context, customerID string) error {
+	limits, _, err := s.quotaLimits(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if limits.MaxConcurrentJobs == 0 {
+		return nil
+	}
+	active, err := s.queries.CountActiveJobsByCustomer(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	return checkQuota(QuotaConcurrentJobs, limits.MaxConcurrentJobs, active, 1)
+}
+
+// EnsureStorageQuota vérifie qu'un agrandissement de additionalGB Go tient dans le quota de stockage
+func (s *Service) EnsureStorageQuota(ctx context.Context, customerID string, additionalGB int32) error {
+	limits, _, err := s.quotaLimits(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if limits.MaxStorageGB == 0 || additionalGB <= 0 {
+		return nil
+	}
+	usage, err := s.queries.GetCustomerInstanceUsage(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	return checkQuota(QuotaStorageGB, limits.MaxStorageGB, usage.StorageGb, int64(additionalGB))
+}
+
+// DefaultStorageGB stockage attribué aux instances créées sans storage_gb
+func (s *Service) DefaultStorageGB() int32 {
+	return s.quotas.DefaultStorageGB
+}
+
+func (s *Service) quotaLimits(ctx context.Context, customerID string) (QuotaLimits, bool, error) {
+	limits := QuotaLimits{
+		MaxInstances:      s.quotas.MaxInstances,
+		MaxStorageGB:      s.quotas.MaxStorageGB,
+		MaxConcurrentJobs: s.quotas.MaxConcurrentJobs,
+	}
+
+	custom, err := s.queries.GetCustomerQuota(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return limits, false, nil
+		}
+		return limits, false, fmt.Errorf("db_error: %w", err)
+	}
+	if custom.MaxInstances.Valid {
+		limits.MaxInstances = custom.MaxInstances.Int32
+	}
+	if custom.MaxStorageGb.Valid {
+		limits.MaxStorageGB = custom.MaxStorageGb.Int32
+	}
+	if custom.MaxConcurrentJobs.Valid {
+		limits.MaxConcurrentJobs = custom.MaxConcurrentJobs.Int32
+	}
+	return limits, true, nil
+}
+
+func (s *Service) quotaUsage(ctx context.Context, customerID string) (QuotaUsage, error) {
+	instances, err := s.queries.GetCustomerInstanceUsage(ctx, customerID)
+	if err != nil {
+		return QuotaUsage{}, fmt.Errorf("db_error: %w", err)
+	}
+	active, err := s.queries.CountActiveJobsByCustomer(ctx, customerID)
+	if err != nil {
+		return QuotaUsage{}, fmt.Errorf("db_error: %w", err)
+	}
+	return QuotaUsage{Instances: instances.InstanceCount, StorageGB: instances.StorageGb, ConcurrentJobs: active}, nil
+}
+
+func checkQuota(resource string, limit int32, used, requested int64) error {
+	if limit > 0 && used+requested > int64(limit) {
+		return &QuotaError{Resource: resource, Limit: int64(limit), Used: used, Requested: requested}
+	}
+	return nil
+}
+
+func optionalInt4(v *int32) pgtype.Int4 {
+	if v == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *v, Valid: true}
+}
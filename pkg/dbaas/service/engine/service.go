@@ -0,0 +1,176 @@
+package service_engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	"github.com/Gskill75/api2/pkg/config"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
+	"k8s.io/klog/v2"
+)
+
+// Service couche commune aux moteurs DBaaS pilotés par AWX : instances (db_instances, filtrées
+// par db_type), lancement et suivi des jobs (awx_history), identifiants générés
+type Service struct {
+	engine    config.DbaasEngine
+	quotas    config.DbaasQuotas
+	awxClient *awxclient.Client
+	queries   *db.Queries
+	secrets   secret.Store
+	// Hooks des opérations lancées par le scheduler de maintenance
+	hookResolver HookResolver
+}
+
+// Erreurs métier sur les instances
+var (
+	ErrInstanceNotFound      = errors.New("instance not found")
+	ErrInstanceAlreadyExists = errors.New("instance already exists")
+	ErrInstanceNotReady      = errors.New("instance is not ready")
+	ErrOperationInProgress   = errors.New("an operation is already running on this instance")
+	ErrTemplateNotConfigured = errors.New("template not configured")
+	ErrJobNotFound           = errors.New("job not found")
+	ErrVersionNotSupported   = errors.New("version not supported")
+)
+
+func NewService(engine config.DbaasEngine, quotas config.DbaasQuotas, awxClient *awxclient.Client, queries *db.Queries, secrets secret.Store) *Service {
+	s := &Service{
+		engine:    engine,
+		quotas:    quotas,
+		awxClient: awxClient,
+		queries:   queries,
+		secrets:   secrets,
+	}
+	s.hookResolver = s.defaultHookResolver
+	return s
+}
+
+// Engine renvoie le nom du moteur, utilisé comme db_type des instances
+func (s *Service) Engine() string {
+	return s.engine.Name
+}
+
+// GetInstance renvoie l'instance du client pour ce moteur
+func (s *Service) GetInstance(ctx context.Context, customerID, instanceName string) (db.DbInstance, error) {
+	instance, err := s.queries.GetDBInstanceByName(ctx, db.GetDBInstanceByNameParams{
+		InstanceName: instanceName,
+		CustomerID:   customerID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return instance, ErrInstanceNotFound
+		}
+		return instance, fmt.Errorf("db_error: %w", err)
+	}
+	// Les noms sont uniques par client tous moteurs confondus : une instance d'un autre moteur n'existe pas ici
+	if instance.DbType != s.engine.Name {
+		return instance, ErrInstanceNotFound
+	}
+	return instance, nil
+}
+
+// GetReadyInstance renvoie l'instance du client si elle est prête et sans opération en cours
+func (s *Service) GetReadyInstance(ctx context.Context, customerID, instanceName string) (db.DbInstance, error) {
+	instance, err := s.GetInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return instance, err
+	}
+	if instance.Status != db.StatusEnumCompleted {
+		return instance, ErrInstanceNotReady
+	}
+
+	// Une seule opération à la fois sur une instance
+	if err := s.EnsureNoOperationInProgress(ctx, customerID, instanceName); err != nil {
+		return instance, err
+	}
+	return instance, nil
+}
+
+// EnsureNoOperationInProgress vérifie qu'aucun job de l'instance n'est en cours
+func (s *Service) EnsureNoOperationInProgress(ctx context.Context, customerID, instanceName string) error {
+	active, err := s.queries.HasActiveOperationOnInstance(ctx, db.HasActiveOperationOnInstanceParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	if active {
+		return ErrOperationInProgress
+	}
+	return nil
+}
+
+// instanceLockTTL durée maximale du verrou d'une instance, au-delà un appel interrompu ne bloque plus l'instance
+const instanceLockTTL = 5 * time.Minute
+
+// LockInstance réserve l'instance le temps de vérifier puis lancer une opération, y compris entre réplicas.
+// Renvoie ErrOperationInProgress si un autre appel la détient ; la fonction renvoyée libère le verrou.
+func (s *Service) LockInstance(ctx context.Context, customerID, instanceName string) (func(), error) {
+	// Précision de la colonne TIMESTAMPTZ : la même valeur sert de jeton à la libération
+	lockedUntil := pgtype.Timestamptz{Time: time.Now().Add(instanceLockTTL).Truncate(time.Microsecond), Valid: true}
+	acquired, err := s.queries.AcquireInstanceLock(ctx, db.AcquireInstanceLockParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+		LockedUntil:  lockedUntil,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	if acquired == 0 {
+		return nil, ErrOperationInProgress
+	}
+
+	return func() {
+		// Libéré même si la requête du client a été annulée
+		err := s.queries.ReleaseInstanceLock(context.WithoutCancel(ctx), db.ReleaseInstanceLockParams{
+			CustomerID:   customerID,
+			InstanceName: instanceName,
+			LockedUntil:  lockedUntil,
+		})
+		if err != nil {
+			klog.Errorf("Failed to release lock of instance '%s': %v", instanceName, err)
+		}
+	}, nil
+}
+
+// LockReadyInstance réserve l'instance puis la renvoie si elle est prête et sans opération en cours.
+// Le verrou, à libérer par la fonction renvoyée, couvre le lancement jusqu'à l'enregistrement du job :
+// deux appels simultanés ne peuvent passer tous deux la vérification des opérations en cours
+func (s *Service) LockReadyInstance(ctx context.Context, customerID, instanceName string) (db.DbInstance, func(), error) {
+	unlock, err := s.LockInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return db.DbInstance{}, nil, err
+	}
+	instance, err := s.GetReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		unlock()
+		return instance, nil, err
+	}
+	return instance, unlock, nil
+}
+
+// EnsureInstanceNameAvailable vérifie que le nom d'instance est libre pour le client.
+// Le nom identifie l'instance (et ses identifiants) ; une instance dont la création a échoué libère son nom.
+func (s *Service) EnsureInstanceNameAvailable(ctx context.Context, customerID, instanceName string) error {
+	existing, err := s.queries.GetDBInstanceByName(ctx, db.GetDBInstanceByNameParams{
+		InstanceName: instanceName,
+		CustomerID:   customerID,
+	})
+	switch {
+	case err == nil && existing.Status == db.StatusEnumFailed:
+		if err := s.queries.SoftDeleteDBInstance(ctx, existing.ID); err != nil {
+			return fmt.Errorf("db_error: %w", err)
+		}
+	case err == nil:
+		return ErrInstanceAlreadyExists
+	case !errors.Is(err, pgx.ErrNoRows):
+		return fmt.Errorf("db_error: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package service_engine
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+)
+
+const (
+	// instance_name est un VARCHAR(20) dans awx_history et db_instances
+	maxInstanceNameLength = 20
+)
+
+var (
+	instanceNameRegex = regexp.MustCompile(`^[a-z][a-z0-9-]*[a-z0-9]$`)
+	usernameRegex     = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+)
+
+// ValidationError regroupe les erreurs de validation par champ (renvoyées en 400)
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s: %s", k, e.Fields[k]))
+	}
+	return "validation_failed: " + strings.Join(parts, "; ")
+}
+
+// Add enregistre une erreur pour le champ
+func (e *ValidationError) Add(field, msg string) {
+	if e.Fields == nil {
+		e.Fields = map[string]string{}
+	}
+	// On garde la première erreur rencontrée pour un champ
+	if _, exists := e.Fields[field]; !exists {
+		e.Fields[field] = msg
+	}
+}
+
+// OrNil renvoie nil si aucune erreur n'a été enregistrée
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ValidateUsername vérifie le nom de l'utilisateur créé sur l'instance
+func ValidateUsername(verr *ValidationError, field, username string, reserved ...string) {
+	switch {
+	case username == "":
+		verr.Add(field, "is required")
+	case !usernameRegex.MatchString(username):
+		verr.Add(field, "must start with a lowercase letter or '_' and contain only lowercase letters, digits and '_' (max 63)")
+	case slices.Contains(reserved, username):
+		verr.Add(field, "is reserved")
+	}
+}
+
+func ValidateInstanceName(verr *ValidationError, field, name string) {
+	switch {
+	case name == "":
+		verr.Add(field, "is required")
+	case len(name) > maxInstanceNameLength:
+		verr.Add(field, fmt.Sprintf("must be at most %d characters", maxInstanceNameLength))
+	case !instanceNameRegex.MatchString(name):
+		verr.Add(field, "must start with a lowercase letter and contain only lowercase letters, digits and '-'")
+	}
+}
+
+// ValidateExtraVars vérifie chaque extra var déclarée dans le survey du template AWX
+func ValidateExtraVars(spec *awxclient.SurveySpec, extraVars map[string]interface{}) error {
+	verr := &ValidationError{}
+	if spec == nil {
+		return nil
+	}
+
+	for _, q := range spec.Spec {
+		value, present := extraVars[q.Variable]
+		if !present || isEmptyValue(value) {
+			if q.Required && q.Default == nil {
+				verr.Add(q.Variable, "is required by the template")
+			}
+			continue
+		}
+
+		if msg := validateSurveyValue(q, value); msg != "" {
+			verr.Add(q.Variable, msg)
+		}
+	}
+
+	return verr.OrNil()
+}
+
+func validateSurveyValue(q awxclient.SurveyQuestion, value interface{}) string {
+	switch q.Type {
+	case "text", "textarea", "password":
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		length := float64(len(s))
+		if q.Min != nil && length < *q.Min {
+			return fmt.Sprintf("must be at least %d characters", int(*q.Min))
+		}
+		if q.Max != nil && length > *q.Max {
+			return fmt.Sprintf("must be at most %d characters", int(*q.Max))
+		}
+
+	case "integer", "float":
+		n, ok := ToFloat(value)
+		if !ok {
+			return "must be a number"
+		}
+		if q.Type == "integer" && n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		if q.Min != nil && n < *q.Min {
+			return fmt.Sprintf("must be greater than or equal to %v", *q.Min)
+		}
+		if q.Max != nil && n > *q.Max {
+			return fmt.Sprintf("must be less than or equal to %v", *q.Max)
+		}
+
+	case "multiplechoice":
+		s, ok := value.(string)
+		if !ok {
+			return "must be a string"
+		}
+		if choices := q.ChoiceList(); len(choices) > 0 && !slices.Contains(choices, s) {
+			return fmt.Sprintf("must be one of: %s", strings.Join(choices, ", "))
+		}
+
+	case "multiselect":
+		values, ok := toStringSlice(value)
+		if !ok {
+			return "must be a list of strings"
+		}
+		choices := q.ChoiceList()
+		for _, v := range values {
+			if len(choices) > 0 && !slices.Contains(choices, v) {
+				return fmt.Sprintf("values must be among: %s", strings.Join(choices, ", "))
+			}
+		}
+	}
+
+	return ""
+}
+
+func isEmptyValue(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	if s, ok := value.(string); ok {
+		return s == ""
+	}
+	return false
+}
+
+// ToFloat convertit une valeur numérique (extra var, artifact AWX) en float64
+func ToFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toStringSlice(value interface{}) ([]string, bool) {
+	switch v := value.(type) {
+	case []string:
+		return v, true
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			out = append(out, s)
+		}
+		return out, true
+	}
+	return nil, false
+}
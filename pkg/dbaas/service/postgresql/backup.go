@@ -0,0 +1,285 @@
+package service_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"k8s.io/klog/v2"
+)
+
+const (
+	BackupTriggerManual    = "manual"
+	BackupTriggerScheduled = "scheduled"
+
+	// Utilisateur enregistré dans awx_history pour les backups planifiés
+	schedulerUser = "scheduler"
+
+	minBackupIntervalHours = 1
+	maxBackupIntervalHours = 24 * 30
+)
+
+// Artifacts publiés par le playbook de backup via set_stats
+const (
+	artifactBackupID        = "backup_id"
+	artifactBackupSize      = "backup_size"
+	artifactBackupTimestamp = "backup_timestamp"
+)
+
+var ErrBackupScheduleNotFound = errors.New("backup schedule not found")
+
+type BackupResponse struct {
+	ID           int32      `json:"id"`
+	InstanceName string     `json:"instance_name"`
+	JobID        int64      `json:"job_id"`
+	Trigger      string     `json:"trigger"`
+	Status       string     `json:"status"`
+	BackupID     string     `json:"backup_id,omitempty"`
+	SizeBytes    *int64     `json:"size_bytes,omitempty"`
+	BackupAt     *time.Time `json:"backup_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	CompletedAt  *time.Time `json:"completed_at,omitempty"`
+}
+
+type BackupScheduleRequest struct {
+	IntervalHours int        `json:"interval_hours"`
+	Enabled       bool       `json:"enabled"`
+	StartAt       *time.Time `json:"start_at,omitempty"`
+}
+
+type BackupScheduleResponse struct {
+	InstanceName  string     `json:"instance_name"`
+	IntervalHours int32      `json:"interval_hours"`
+	Enabled       bool       `json:"enabled"`
+	NextRunAt     time.Time  `json:"next_run_at"`
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`
+}
+
+// TriggerBackup lance immédiatement un backup de l'instance
+func (p *PostgresService) TriggerBackup(ctx context.Context, customerID, instanceName, createdBy string) (*BackupResponse, error) {
+	return p.launchBackup(ctx, customerID, instanceName, createdBy, BackupTriggerManual)
+}
+
+// launchBackup un backup manuel et un backup planifié (ou toute autre opération) ne peuvent être lancés
+// simultanément sur l'instance : le verrou est tenu jusqu'à l'enregistrement du job
+func (p *PostgresService) launchBackup(ctx context.Context, customerID, instanceName, createdBy, trigger string) (*BackupResponse, error) {
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	history, err := p.engine.LaunchOperation(ctx, engine.OperationRequest{
+		Action:       db.ActionTypeEnumBackup,
+		TemplateName: p.cfg.Dbaas.Templates.Backup,
+		Instance:     instance,
+		ExtraVars: map[string]interface{}{
+			"instance_name": instanceName,
+			"customer_id":   customerID,
+		},
+		CreatedBy: createdBy,
+		OnDone:    p.recordBackupHook(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	backup, err := p.queries.CreateBackup(ctx, db.CreateBackupParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+		AwxHistoryID: pgtype.Int4{Int32: history.ID, Valid: true},
+		AwxJobID:     history.AwxJobID,
+		TriggerType:  trigger,
+		Status:       db.StatusEnumRunning,
+	})
+	if err != nil {
+		klog.Errorf("Backup job launched but failed to insert backup into DB: %v", err)
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	return toBackupResponse(backup), nil
+}
+
+// recordBackupHook reporte le résultat du job et les artifacts AWX (id, taille, horodatage) sur db_backups
+func (p *PostgresService) recordBackupHook() engine.JobCompletionHook {
+	return func(ctx context.Context, historyID int32, status db.StatusEnum, job *awxclient.Job) {
+		params := db.UpdateBackupCompletionByHistoryIDParams{
+			AwxHistoryID: pgtype.Int4{Int32: historyID, Valid: true},
+			Status:       status,
+		}
+		if status != db.StatusEnumCompleted {
+			params.Status = db.StatusEnumFailed
+		}
+		if job != nil && status == db.StatusEnumCompleted {
+			if id, ok := job.Artifacts[artifactBackupID].(string); ok && id != "" {
+				params.BackupID = pgtype.Text{String: id, Valid: true}
+			}
+			if size, ok := engine.ToFloat(job.Artifacts[artifactBackupSize]); ok {
+				params.SizeBytes = pgtype.Int8{Int64: int64(size), Valid: true}
+			}
+			if ts, ok := job.Artifacts[artifactBackupTimestamp].(string); ok {
+				if t, err := time.Parse(time.RFC3339, ts); err == nil {
+					params.BackupAt = pgtype.Timestamptz{Time: t, Valid: true}
+				} else {
+					klog.Warningf("Invalid backup_timestamp artifact '%s' for job %d: %v", ts, job.ID, err)
+				}
+			}
+			if !params.BackupID.Valid {
+				klog.Warningf("Backup job %d completed without backup_id artifact", job.ID)
+			}
+		}
+
+		if err := p.queries.UpdateBackupCompletionByHistoryID(ctx, params); err != nil {
+			klog.Errorf("Failed to update backup for history %d: %v", historyID, err)
+		}
+	}
+}
+
+// ListBackups renvoie les backups d'une instance du client, du plus récent au plus ancien
+func (p *PostgresService) ListBackups(ctx context.Context, customerID, instanceName string) ([]BackupResponse, error) {
+	if err := p.ensureInstanceOwned(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	backups, err := p.queries.ListBackupsByInstance(ctx, db.ListBackupsByInstanceParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	out := make([]BackupResponse, 0, len(backups))
+	for _, b := range backups {
+		out = append(out, *toBackupResponse(b))
+	}
+	return out, nil
+}
+
+// GetBackupSchedule renvoie la planification des backups d'une instance
+func (p *PostgresService) GetBackupSchedule(ctx context.Context, customerID, instanceName string) (*BackupScheduleResponse, error) {
+	if err := p.ensureInstanceOwned(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+
+	schedule, err := p.queries.GetBackupSchedule(ctx, db.GetBackupScheduleParams{
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBackupScheduleNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return toBackupScheduleResponse(schedule), nil
+}
+
+// SetBackupSchedule crée ou remplace la planification des backups d'une instance.
+// Le premier backup a lieu à start_at, ou après un intervalle si absent.
+func (p *PostgresService) SetBackupSchedule(ctx context.Context, customerID, instanceName string, req BackupScheduleRequest, createdBy string) (*BackupScheduleResponse, error) {
+	verr := &ValidationError{}
+	if req.IntervalHours < minBackupIntervalHours || req.IntervalHours > maxBackupIntervalHours {
+		verr.Add("interval_hours", fmt.Sprintf("must be between %d and %d", minBackupIntervalHours, maxBackupIntervalHours))
+	}
+	if req.StartAt != nil && req.StartAt.Before(time.Now()) {
+		verr.Add("start_at", "must be in the future")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := p.ensureInstanceOwned(ctx, customerID, instanceName); err != nil {
+		return nil, err
+	}
+	if p.cfg.Dbaas.Templates.Backup == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, db.ActionTypeEnumBackup)
+	}
+
+	nextRun := time.Now().Add(time.Duration(req.IntervalHours) * time.Hour)
+	if req.StartAt != nil {
+		nextRun = *req.StartAt
+	}
+
+	schedule, err := p.queries.UpsertBackupSchedule(ctx, db.UpsertBackupScheduleParams{
+		CustomerID:    customerID,
+		InstanceName:  instanceName,
+		IntervalHours: int32(req.IntervalHours),
+		Enabled:       req.Enabled,
+		NextRunAt:     pgtype.Timestamptz{Time: nextRun, Valid: true},
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	klog.Infof("Backup schedule of instance '%s' set by %s: every %dh, enabled=%t", instanceName, createdBy, req.IntervalHours, req.Enabled)
+	return toBackupScheduleResponse(schedule), nil
+}
+
+// runDueBackups lance les backups planifiés arrivés à échéance
+func (p *PostgresService) runDueBackups(ctx context.Context) {
+	// La requête réserve les planifications (SKIP LOCKED) et avance next_run_at : sûr avec plusieurs réplicas
+	schedules, err := p.queries.ClaimDueBackupSchedules(ctx)
+	if err != nil {
+		klog.Errorf("Failed to claim due backup schedules: %v", err)
+		return
+	}
+
+	for _, s := range schedules {
+		backup, err := p.launchBackup(ctx, s.CustomerID, s.InstanceName, schedulerUser, BackupTriggerScheduled)
+		if err != nil {
+			// Backup manqué, on retentera à la prochaine échéance
+			klog.Errorf("Scheduled backup of instance '%s' (customer %s) not launched: %v", s.InstanceName, s.CustomerID, err)
+			continue
+		}
+		klog.Infof("Scheduled backup of instance '%s' launched, job_id=%d", s.InstanceName, backup.JobID)
+	}
+}
+
+// ensureInstanceOwned vérifie que l'instance existe, appartient au client et est une instance PostgreSQL
+func (p *PostgresService) ensureInstanceOwned(ctx context.Context, customerID, instanceName string) error {
+	_, err := p.engine.GetInstance(ctx, customerID, instanceName)
+	return err
+}
+
+func toBackupResponse(b db.DbBackup) *BackupResponse {
+	resp := &BackupResponse{
+		ID:           b.ID,
+		InstanceName: b.InstanceName,
+		JobID:        b.AwxJobID.Int64,
+		Trigger:      b.TriggerType,
+		Status:       string(b.Status),
+		BackupID:     b.BackupID.String,
+		CreatedAt:    b.CreatedAt.Time,
+	}
+	if b.SizeBytes.Valid {
+		resp.SizeBytes = &b.SizeBytes.Int64
+	}
+	if b.BackupAt.Valid {
+		resp.BackupAt = &b.BackupAt.Time
+	}
+	if b.CompletedAt.Valid {
+		resp.CompletedAt = &b.CompletedAt.Time
+	}
+	return resp
+}
+
+func toBackupScheduleResponse(s db.DbBackupSchedule) *BackupScheduleResponse {
+	resp := &BackupScheduleResponse{
+		InstanceName:  s.InstanceName,
+		IntervalHours: s.IntervalHours,
+		Enabled:       s.Enabled,
+		NextRunAt:     s.NextRunAt.Time,
+	}
+	if s.LastRunAt.Valid {
+		resp.LastRunAt = &s.LastRunAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,96 @@
+package service_postgresql
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"k8s.io/klog/v2"
+)
+
+// Durée de validité d'un jeton de confirmation
+const confirmationTokenTTL = 5 * time.Minute
+
+var ErrInvalidConfirmationToken = errors.New("invalid or expired confirmation token")
+
+// loadConfirmationKey décode la clé HMAC configurée, partagée par tous les réplicas.
+// La clé est validée au chargement de la configuration.
+func loadConfirmationKey(encoded string) []byte {
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) < 32 {
+		klog.Fatalf("Invalid dbaas.confirmation_key: base64, at least 32 bytes expected")
+	}
+	return key
+}
+
+// newConfirmationToken émet un jeton à usage unique pour l'opération sur l'instance du client.
+// Format : <expiration unix>.<nonce>.<hmac base64url> ; le nonce est conservé jusqu'à son utilisation.
+func (p *PostgresService) newConfirmationToken(ctx context.Context, customerID, instanceName, operation string, expiresAt time.Time) (string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate confirmation nonce: %w", err)
+	}
+	nonce := base64.RawURLEncoding.EncodeToString(raw)
+
+	// Purge des jetons jamais utilisés
+	if err := p.queries.DeleteExpiredConfirmationTokens(ctx); err != nil {
+		klog.Warningf("Failed to purge expired confirmation tokens: %v", err)
+	}
+	err := p.queries.CreateConfirmationToken(ctx, db.CreateConfirmationTokenParams{
+		Nonce:        nonce,
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+		ExpiresAt:    pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	})
+	if err != nil {
+		return "", fmt.Errorf("db_error: %w", err)
+	}
+
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	return exp + "." + nonce + "." + p.signConfirmation(customerID, instanceName, operation, exp, nonce), nil
+}
+
+// consumeConfirmationToken vérifie que le jeton a été émis pour cette opération, ce client et cette
+// instance, qu'il n'a pas expiré, puis l'invalide : un jeton ne confirme qu'une seule opération
+func (p *PostgresService) consumeConfirmationToken(ctx context.Context, customerID, instanceName, operation, token string) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return ErrInvalidConfirmationToken
+	}
+	exp, nonce, mac := parts[0], parts[1], parts[2]
+	expUnix, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expUnix {
+		return ErrInvalidConfirmationToken
+	}
+	if !hmac.Equal([]byte(mac), []byte(p.signConfirmation(customerID, instanceName, operation, exp, nonce))) {
+		return ErrInvalidConfirmationToken
+	}
+
+	consumed, err := p.queries.ConsumeConfirmationToken(ctx, db.ConsumeConfirmationTokenParams{
+		Nonce:        nonce,
+		CustomerID:   customerID,
+		InstanceName: instanceName,
+	})
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+	if consumed == 0 {
+		return ErrInvalidConfirmationToken
+	}
+	return nil
+}
+
+func (p *PostgresService) signConfirmation(customerID, instanceName, operation, exp, nonce string) string {
+	h := hmac.New(sha256.New, p.confirmationKey)
+	fmt.Fprintf(h, "%s|%s|%s|%s|%s", customerID, instanceName, operation, exp, nonce)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,23 @@
+package service_postgresql
+
+import (
+	"context"
+
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+// Erreurs des identifiants, partagées avec les autres moteurs
+var (
+	ErrCredentialsNotReady         = engine.ErrCredentialsNotReady
+	ErrCredentialsUnavailable      = engine.ErrCredentialsUnavailable
+	ErrCredentialsAlreadyRetrieved = engine.ErrCredentialsAlreadyRetrieved
+	ErrSecretVariablesNotProtected = engine.ErrSecretVariablesNotProtected
+)
+
+// Credentials identifiants générés pour une instance, récupérables une seule fois
+type Credentials = engine.Credentials
+
+// GetCredentials renvoie une seule fois les identifiants d'une instance, une fois le job AWX réussi
+func (p *PostgresService) GetCredentials(ctx context.Context, customerID, instanceName string) (*Credentials, error) {
+	return p.engine.GetCredentials(ctx, customerID, instanceName)
+}
@@ -0,0 +1,160 @@
+package service_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrJobNotCancelable               = errors.New("job is not running")
+	ErrJobNotRelaunchable             = errors.New("only failed or canceled jobs can be relaunched")
+	ErrRelaunchCredentialsUnavailable = errors.New("generated credentials are no longer available, start a new operation")
+)
+
+// CancelJob annule un job AWX en cours et passe son historique à canceled
+func (p *PostgresService) CancelJob(ctx context.Context, caller Caller, jobID int) (*db.AwxHistory, error) {
+	history, err := p.getJobForCaller(ctx, caller, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if history.Status != db.StatusEnumPending && history.Status != db.StatusEnumRunning {
+		return nil, ErrJobNotCancelable
+	}
+
+	if err := p.awxClient.CancelUnifiedJob(ctx, awxclient.JobKind(history.AwxJobType), jobID); err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+
+	// Le suivi du job complètera l'historique (completed_at, hooks) quand AWX aura arrêté le job
+	err = p.queries.UpdateHistoryStatus(ctx, db.UpdateHistoryStatusParams{
+		ID:        history.ID,
+		Status:    db.StatusEnumCanceled,
+		AwxStatus: db.NullAwxStatusEnum{AwxStatusEnum: db.AwxStatusEnumCanceled, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	klog.Infof("Job %d (%s on instance '%s') canceled by %s", jobID, history.ActionType, history.InstanceName, caller.Subject)
+	history.Status = db.StatusEnumCanceled
+	return history, nil
+}
+
+// RelaunchJob relance un job terminé en échec avec les mêmes paramètres.
+// Le nouvel historique référence l'original via parent_history_id.
+func (p *PostgresService) RelaunchJob(ctx context.Context, caller Caller, jobID int) (*db.AwxHistory, error) {
+	original, err := p.getJobForCaller(ctx, caller, jobID)
+	if err != nil {
+		return nil, err
+	}
+	switch original.Status {
+	case db.StatusEnumFailed, db.StatusEnumError, db.StatusEnumCanceled:
+	default:
+		return nil, ErrJobNotRelaunchable
+	}
+
+	if err := p.engine.EnsureNoOperationInProgress(ctx, original.CustomerID, original.InstanceName); err != nil {
+		return nil, err
+	}
+	// Comme au premier lancement, une opération disruptive n'est relancée que dans la fenêtre de maintenance
+	scheduled, err := p.engine.DeferRelaunch(ctx, original, caller.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if scheduled != nil {
+		klog.Infof("Relaunch of job %d (%s on instance '%s') scheduled at %s by %s", jobID, original.ActionType, original.InstanceName, scheduled.ScheduledAt.Time.Format(time.RFC3339), caller.Subject)
+		return scheduled, nil
+	}
+	if err := p.engine.EnsureJobQuota(ctx, original.CustomerID); err != nil {
+		return nil, err
+	}
+
+	// AWX relance avec le mot de passe d'origine : il doit encore être récupérable par le client
+	switch original.ActionType {
+	case db.ActionTypeEnumCreate, db.ActionTypeEnumRotate, db.ActionTypeEnumClone:
+		exists, err := p.secrets.Exists(ctx, engine.CredentialKey(original.CustomerID, original.InstanceName))
+		if err != nil {
+			return nil, fmt.Errorf("secret_store_failed: %w", err)
+		}
+		if !exists {
+			return nil, ErrRelaunchCredentialsUnavailable
+		}
+	}
+
+	kind := awxclient.JobKind(original.AwxJobType)
+	job, err := p.awxClient.RelaunchUnifiedJob(ctx, kind, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_launch_failed: %w", err)
+	}
+
+	relaunched, err := p.queries.CreateHistory(ctx, db.CreateHistoryParams{
+		CustomerID:      original.CustomerID,
+		AwxJobID:        pgtype.Int8{Int64: int64(job.ID), Valid: true},
+		AwxTemplateName: original.AwxTemplateName,
+		AwxTemplateID:   original.AwxTemplateID,
+		ActionType:      original.ActionType,
+		Status:          db.StatusEnumRunning,
+		InstanceName:    original.InstanceName,
+		Username:        original.Username,
+		ExtraVars:       original.ExtraVars,
+		CreatedBy:       caller.Subject,
+		ParentHistoryID: pgtype.Int4{Int32: original.ID, Valid: true},
+		AwxJobType:      original.AwxJobType,
+	})
+	if err != nil {
+		klog.Errorf("Job %d relaunched as %d but failed to insert into DB: %v", jobID, job.ID, err)
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	if err := p.engine.MonitorJob(context.Background(), kind, job.ID, relaunched.ID, p.relaunchHook(ctx, &relaunched)); err != nil {
+		klog.Errorf("Failed to start job monitoring for job %d: %v", job.ID, err)
+	}
+
+	klog.Infof("Job %d (%s on instance '%s') relaunched as job %d by %s", jobID, original.ActionType, original.InstanceName, job.ID, caller.Subject)
+	return &relaunched, nil
+}
+
+// relaunchHook prépare le suivi propre à l'action relancée (statut de l'instance, ligne de backup, mise à jour)
+func (p *PostgresService) relaunchHook(ctx context.Context, history *db.AwxHistory) engine.JobCompletionHook {
+	switch history.ActionType {
+	case db.ActionTypeEnumCreate, db.ActionTypeEnumClone:
+		instance, err := p.queries.GetDBInstanceByName(ctx, db.GetDBInstanceByNameParams{
+			InstanceName: history.InstanceName,
+			CustomerID:   history.CustomerID,
+		})
+		if err != nil {
+			klog.Errorf("Instance '%s' of relaunched job not found: %v", history.InstanceName, err)
+			return nil
+		}
+		if err := p.queries.UpdateDBInstanceStatus(ctx, db.UpdateDBInstanceStatusParams{ID: instance.ID, Status: db.StatusEnumRunning}); err != nil {
+			klog.Errorf("Failed to reset status of instance %d: %v", instance.ID, err)
+		}
+		return p.engine.UpdateInstanceStatusHook(instance.ID)
+
+	case db.ActionTypeEnumBackup:
+		_, err := p.queries.CreateBackup(ctx, db.CreateBackupParams{
+			CustomerID:   history.CustomerID,
+			InstanceName: history.InstanceName,
+			AwxHistoryID: pgtype.Int4{Int32: history.ID, Valid: true},
+			AwxJobID:     history.AwxJobID,
+			TriggerType:  BackupTriggerManual,
+			Status:       db.StatusEnumRunning,
+		})
+		if err != nil {
+			klog.Errorf("Failed to insert backup of relaunched job %d: %v", history.AwxJobID.Int64, err)
+		}
+		return p.recordBackupHook()
+
+	case db.ActionTypeEnumUpdate:
+		return p.updateRelaunchHook(ctx, history)
+	}
+	return nil
+}
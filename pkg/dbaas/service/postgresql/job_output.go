@@ -0,0 +1,259 @@
+package service_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+var ErrJobNotFound = engine.ErrJobNotFound
+
+var (
+	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*[a-zA-Z]`)
+	// Secrets pouvant apparaître dans la sortie des playbooks (variables, DSN, en-têtes) ;
+	// chaque motif capture le préfixe conservé puis le suffixe (éventuellement vide)
+	secretPatterns = []*regexp.Regexp{
+		regexp.MustCompile(`(?i)("?[a-z_]*(?:password|passwd|secret|token|api_key)[a-z_]*"?\s*[:=]\s*)(?:"[^"]*"|'[^']*'|[^\s,}]+)()`),
+		regexp.MustCompile(`(?i)([a-z][a-z0-9+.-]*://[^:/@\s]+:)[^@\s]+(@)`),
+		regexp.MustCompile(`(?i)(authorization:\s*(?:bearer|token|basic)\s+)\S+()`),
+	}
+)
+
+// Ordre de gravité des statuts de tâche, le plus grave l'emporte ("started" : aucun résultat d'hôte)
+var taskStatusRank = map[string]int{
+	"started":     0,
+	"skipped":     1,
+	"ok":          2,
+	"changed":     3,
+	"failed":      4,
+	"unreachable": 5,
+}
+
+type JobTask struct {
+	Play   string `json:"play"`
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+type FailedHost struct {
+	Host    string `json:"host"`
+	Task    string `json:"task"`
+	Message string `json:"message,omitempty"`
+}
+
+type JobEventsResponse struct {
+	JobID       int          `json:"job_id"`
+	Status      string       `json:"status"`
+	Tasks       []JobTask    `json:"tasks"`
+	FailedHosts []FailedHost `json:"failed_hosts"`
+}
+
+// Caller identité de l'appelant : un admin accède aux jobs de tous les clients
+type Caller struct {
+	CustomerID string
+	Subject    string
+	Admin      bool
+}
+
+// getJobForCaller renvoie la ligne awx_history du job si l'appelant y a accès
+func (p *PostgresService) getJobForCaller(ctx context.Context, caller Caller, jobID int) (*db.AwxHistory, error) {
+	history, err := p.queries.GetHistoryByJobID(ctx, pgtype.Int8{Int64: int64(jobID), Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	// Même réponse qu'un job inexistant pour ne pas révéler les jobs des autres clients
+	if !caller.Admin && history.CustomerID != caller.CustomerID {
+		return nil, ErrJobNotFound
+	}
+	return &history, nil
+}
+
+// GetJobStdout renvoie la sortie texte du job, nettoyée des séquences ANSI et des secrets.
+// Pour un workflow, les sorties des jobs de ses noeuds sont concaténées.
+func (p *PostgresService) GetJobStdout(ctx context.Context, customerID string, jobID int) (string, error) {
+	history, err := p.getJobForCaller(ctx, Caller{CustomerID: customerID}, jobID)
+	if err != nil {
+		return "", err
+	}
+
+	var stdout string
+	if awxclient.JobKind(history.AwxJobType) == awxclient.JobKindWorkflow {
+		stdout, err = p.workflowStdout(ctx, jobID)
+	} else {
+		stdout, err = p.awxClient.JobService().GetJobStdout(ctx, jobID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("awx_request_failed: %w", err)
+	}
+	return sanitizeOutput(stdout), nil
+}
+
+func (p *PostgresService) workflowStdout(ctx context.Context, workflowJobID int) (string, error) {
+	nodes, err := p.awxClient.WorkflowJobService().ListWorkflowNodes(ctx, workflowJobID)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		job := node.SummaryFields.Job
+		if job == nil || job.Type != string(awxclient.JobKindJob) {
+			continue
+		}
+		stdout, err := p.awxClient.JobService().GetJobStdout(ctx, job.ID)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "=== %s (job %d, %s) ===\n%s\n", node.Name(), job.ID, job.Status, stdout)
+	}
+	return b.String(), nil
+}
+
+// GetJobEvents résume les événements du job : statut de chaque tâche et hôtes en échec.
+// Pour un workflow, chaque noeud est une tâche et les hôtes en échec viennent des jobs des noeuds.
+func (p *PostgresService) GetJobEvents(ctx context.Context, customerID string, jobID int) (*JobEventsResponse, error) {
+	history, err := p.getJobForCaller(ctx, Caller{CustomerID: customerID}, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if awxclient.JobKind(history.AwxJobType) == awxclient.JobKindWorkflow {
+		return p.workflowEvents(ctx, jobID)
+	}
+
+	job, err := p.awxClient.JobService().GetJob(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+	events, err := p.awxClient.JobService().GetJobEvents(ctx, jobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+
+	resp := summarizeJobEvents(events)
+	resp.JobID = job.ID
+	resp.Status = job.Status
+	return resp, nil
+}
+
+func (p *PostgresService) workflowEvents(ctx context.Context, workflowJobID int) (*JobEventsResponse, error) {
+	workflowJob, err := p.awxClient.WorkflowJobService().GetWorkflowJob(ctx, workflowJobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+	nodes, err := p.awxClient.WorkflowJobService().ListWorkflowNodes(ctx, workflowJobID)
+	if err != nil {
+		return nil, fmt.Errorf("awx_request_failed: %w", err)
+	}
+
+	resp := &JobEventsResponse{
+		JobID:       workflowJob.ID,
+		Status:      workflowJob.Status,
+		Tasks:       []JobTask{},
+		FailedHosts: []FailedHost{},
+	}
+	for _, node := range nodes {
+		status := workflowNodeTaskStatus(node.Status())
+		resp.Tasks = append(resp.Tasks, JobTask{Play: workflowJob.Name, Name: node.Name(), Status: status})
+
+		job := node.SummaryFields.Job
+		if status != "failed" || job == nil || job.Type != string(awxclient.JobKindJob) {
+			continue
+		}
+		events, err := p.awxClient.JobService().GetJobEvents(ctx, job.ID)
+		if err != nil {
+			return nil, fmt.Errorf("awx_request_failed: %w", err)
+		}
+		resp.FailedHosts = append(resp.FailedHosts, summarizeJobEvents(events).FailedHosts...)
+	}
+	return resp, nil
+}
+
+// workflowNodeTaskStatus traduit le statut du job d'un noeud dans le vocabulaire des tâches
+func workflowNodeTaskStatus(status string) string {
+	switch status {
+	case "successful":
+		return "ok"
+	case "failed", "error", "canceled":
+		return "failed"
+	case "never_run":
+		return "skipped"
+	default:
+		return "started"
+	}
+}
+
+func summarizeJobEvents(events []awxclient.JobEvent) *JobEventsResponse {
+	resp := &JobEventsResponse{Tasks: []JobTask{}, FailedHosts: []FailedHost{}}
+	current := -1
+
+	for _, e := range events {
+		var status string
+		switch e.Event {
+		case "playbook_on_task_start":
+			resp.Tasks = append(resp.Tasks, JobTask{Play: e.Play, Name: e.Task, Status: "started"})
+			current = len(resp.Tasks) - 1
+			continue
+		case "runner_on_ok":
+			status = "ok"
+			if e.Changed {
+				status = "changed"
+			}
+		case "runner_on_skipped":
+			status = "skipped"
+		case "runner_on_failed":
+			status = "failed"
+			// Les erreurs ignorées (ignore_errors) n'empêchent pas le job de réussir
+			if ignored, _ := e.EventData["ignore_errors"].(bool); ignored {
+				status = "ok"
+			}
+		case "runner_on_unreachable":
+			status = "unreachable"
+		default:
+			continue
+		}
+
+		if current >= 0 && taskStatusRank[status] > taskStatusRank[resp.Tasks[current].Status] {
+			resp.Tasks[current].Status = status
+		}
+		if status == "failed" || status == "unreachable" {
+			resp.FailedHosts = append(resp.FailedHosts, FailedHost{
+				Host:    e.Host,
+				Task:    e.Task,
+				Message: sanitizeOutput(eventMessage(e)),
+			})
+		}
+	}
+
+	return resp
+}
+
+// eventMessage extrait le message d'erreur du résultat du module (event_data.res.msg)
+func eventMessage(e awxclient.JobEvent) string {
+	res, ok := e.EventData["res"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if msg, ok := res["msg"].(string); ok {
+		return msg
+	}
+	return ""
+}
+
+func sanitizeOutput(s string) string {
+	s = ansiEscapeRegex.ReplaceAllString(s, "")
+	for _, re := range secretPatterns {
+		s = re.ReplaceAllString(s, "${1}"+strings.ReplaceAll(engine.RedactedValue, "$", "$$")+"${2}")
+	}
+	return s
+}
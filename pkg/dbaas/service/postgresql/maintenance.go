@@ -0,0 +1,46 @@
+package service_postgresql
+
+import (
+	"context"
+
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+// Fenêtres de maintenance et opérations planifiées, communes à tous les moteurs DBaaS
+type (
+	MaintenanceWindowRequest  = engine.MaintenanceWindowRequest
+	MaintenanceWindowResponse = engine.MaintenanceWindowResponse
+	ScheduledOperation        = engine.ScheduledOperation
+)
+
+var (
+	ErrMaintenanceWindowNotFound  = engine.ErrMaintenanceWindowNotFound
+	ErrScheduledOperationNotFound = engine.ErrScheduledOperationNotFound
+	ErrOperationAlreadyScheduled  = engine.ErrOperationAlreadyScheduled
+	ErrScheduledSecrets           = engine.ErrScheduledSecrets
+)
+
+// GetMaintenanceWindow renvoie la fenêtre de maintenance de l'instance
+func (p *PostgresService) GetMaintenanceWindow(ctx context.Context, customerID, instanceName string) (*MaintenanceWindowResponse, error) {
+	return p.engine.GetMaintenanceWindow(ctx, customerID, instanceName)
+}
+
+// SetMaintenanceWindow crée ou remplace la fenêtre de maintenance de l'instance
+func (p *PostgresService) SetMaintenanceWindow(ctx context.Context, customerID, instanceName string, req MaintenanceWindowRequest, createdBy string) (*MaintenanceWindowResponse, error) {
+	return p.engine.SetMaintenanceWindow(ctx, customerID, instanceName, req, createdBy)
+}
+
+// DeleteMaintenanceWindow supprime la fenêtre ; les opérations planifiées sont lancées sans attendre
+func (p *PostgresService) DeleteMaintenanceWindow(ctx context.Context, customerID, instanceName string) error {
+	return p.engine.DeleteMaintenanceWindow(ctx, customerID, instanceName)
+}
+
+// ListScheduledOperations renvoie les opérations en attente de la fenêtre de maintenance
+func (p *PostgresService) ListScheduledOperations(ctx context.Context, customerID, instanceName string) ([]ScheduledOperation, error) {
+	return p.engine.ListScheduledOperations(ctx, customerID, instanceName)
+}
+
+// CancelScheduledOperation annule une opération pas encore lancée
+func (p *PostgresService) CancelScheduledOperation(ctx context.Context, customerID, instanceName string, id int32) (*ScheduledOperation, error) {
+	return p.engine.CancelScheduledOperation(ctx, customerID, instanceName, id)
+}
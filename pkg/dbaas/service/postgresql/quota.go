@@ -0,0 +1,24 @@
+package service_postgresql
+
+import (
+	"context"
+
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+// Quotas communs à tous les moteurs DBaaS
+type (
+	QuotaError    = engine.QuotaError
+	QuotaRequest  = engine.QuotaRequest
+	QuotaResponse = engine.QuotaResponse
+)
+
+// GetQuota renvoie les limites du client et sa consommation
+func (p *PostgresService) GetQuota(ctx context.Context, customerID string) (*QuotaResponse, error) {
+	return p.engine.GetQuota(ctx, customerID)
+}
+
+// SetQuota définit les limites propres à un client (administration)
+func (p *PostgresService) SetQuota(ctx context.Context, customerID string, req QuotaRequest, updatedBy string) (*QuotaResponse, error) {
+	return p.engine.SetQuota(ctx, customerID, req, updatedBy)
+}
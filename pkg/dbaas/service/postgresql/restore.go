@@ -0,0 +1,256 @@
+package service_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"k8s.io/klog/v2"
+)
+
+var (
+	ErrBackupNotFound     = errors.New("backup not found")
+	ErrBackupNotAvailable = errors.New("backup did not complete")
+)
+
+// RestoreRequest point de restauration : un backup ou un horodatage (PITR), exclusifs
+type RestoreRequest struct {
+	BackupID    string     `json:"backup_id,omitempty"`
+	PointInTime *time.Time `json:"point_in_time,omitempty"`
+}
+
+type RestoreConfirmation struct {
+	InstanceName      string    `json:"instance_name"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+type CloneRequest struct {
+	TargetInstanceName string
+	// Point de restauration de la source ; état courant si vide
+	Source RestoreRequest
+}
+
+// RequestRestore vérifie qu'une restauration en place est possible et renvoie le jeton
+// de confirmation à présenter à RestoreInstance
+func (p *PostgresService) RequestRestore(ctx context.Context, customerID, instanceName string, req RestoreRequest) (*RestoreConfirmation, error) {
+	if p.cfg.Dbaas.Templates.Restore == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, db.ActionTypeEnumRestore)
+	}
+
+	instance, err := p.engine.GetReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.restoreSourceVars(ctx, instance, req, true); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(confirmationTokenTTL)
+	token, err := p.newConfirmationToken(ctx, customerID, instanceName, restoreOperation(req), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &RestoreConfirmation{
+		InstanceName:      instanceName,
+		ConfirmationToken: token,
+		ExpiresAt:         expiresAt,
+	}, nil
+}
+
+// RestoreInstance écrase les données de l'instance avec le point de restauration demandé.
+// Le jeton doit avoir été émis par RequestRestore pour la même instance et la même cible ; il n'est valable qu'une fois.
+// Il n'est consommé qu'une fois l'instance réservée et la demande validée : une instance occupée ne l'invalide pas.
+func (p *PostgresService) RestoreInstance(ctx context.Context, customerID, instanceName string, req RestoreRequest, confirmationToken, createdBy string) (*PostgresProvisionResponse, error) {
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	extraVars, err := p.restoreSourceVars(ctx, instance, req, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.consumeConfirmationToken(ctx, customerID, instanceName, restoreOperation(req), confirmationToken); err != nil {
+		return nil, err
+	}
+	extraVars["instance_name"] = instanceName
+	extraVars["customer_id"] = customerID
+
+	history, err := p.engine.LaunchOperation(ctx, engine.OperationRequest{
+		Action:       db.ActionTypeEnumRestore,
+		TemplateName: p.cfg.Dbaas.Templates.Restore,
+		Instance:     instance,
+		ExtraVars:    extraVars,
+		CreatedBy:    createdBy,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	klog.Infof("In-place restore of instance '%s' confirmed by %s", instanceName, createdBy)
+	return &PostgresProvisionResponse{
+		InstanceName: instanceName,
+		Username:     instance.Username.String,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   customerID,
+		ScheduledAt:  engine.ScheduledAt(history),
+	}, nil
+}
+
+// CloneInstance crée une nouvelle instance du client à partir d'une instance existante.
+// Le clone reçoit un nouveau mot de passe, récupérable une fois via GetCredentials.
+func (p *PostgresService) CloneInstance(ctx context.Context, customerID, sourceName string, req CloneRequest, createdBy string) (*PostgresProvisionResponse, error) {
+	verr := &ValidationError{}
+	engine.ValidateInstanceName(verr, "target_instance_name", req.TargetInstanceName)
+	if req.TargetInstanceName == sourceName {
+		verr.Add("target_instance_name", "must differ from the source instance")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	if p.cfg.Dbaas.Templates.Clone == "" {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, db.ActionTypeEnumClone)
+	}
+
+	source, unlock, err := p.engine.LockReadyInstance(ctx, customerID, sourceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+	// Deux clones simultanés vers le même nom passeraient tous deux la vérification du nom
+	unlockTarget, err := p.engine.LockInstance(ctx, customerID, req.TargetInstanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlockTarget()
+
+	extraVars, err := p.restoreSourceVars(ctx, source, req.Source, false)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.engine.EnsureInstanceNameAvailable(ctx, customerID, req.TargetInstanceName); err != nil {
+		return nil, err
+	}
+	// Le clone reprend le stockage de la source
+	if err := p.engine.EnsureProvisionQuota(ctx, customerID, source.StorageGb); err != nil {
+		return nil, err
+	}
+
+	password, err := engine.GeneratePassword()
+	if err != nil {
+		return nil, fmt.Errorf("password_generation_failed: %w", err)
+	}
+	username := source.Username.String
+	if err := p.engine.StoreCredentials(ctx, customerID, req.TargetInstanceName, Credentials{Username: username, Password: password}); err != nil {
+		return nil, fmt.Errorf("secret_store_failed: %w", err)
+	}
+
+	target, err := p.queries.CreateDBInstance(ctx, db.CreateDBInstanceParams{
+		CustomerID:   customerID,
+		DbType:       source.DbType,
+		Version:      source.Version,
+		Username:     source.Username,
+		Status:       db.StatusEnumRunning,
+		InstanceName: req.TargetInstanceName,
+		CreatedBy:    createdBy,
+		StorageGb:    source.StorageGb,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_insert_failed: %w", err)
+	}
+
+	extraVars["instance_name"] = req.TargetInstanceName
+	extraVars["source_instance_name"] = sourceName
+	extraVars["username"] = username
+	extraVars["password"] = password
+	extraVars["customer_id"] = customerID
+	extraVars["storage_gb"] = source.StorageGb
+
+	history, err := p.engine.LaunchOperation(ctx, engine.OperationRequest{
+		Action:       db.ActionTypeEnumClone,
+		TemplateName: p.cfg.Dbaas.Templates.Clone,
+		Instance:     target,
+		ExtraVars:    extraVars,
+		SecretKeys:   []string{"password"},
+		CreatedBy:    createdBy,
+		OnDone:       p.engine.UpdateInstanceStatusHook(target.ID),
+	})
+	if err != nil {
+		// Libère le nom du clone et les identifiants générés
+		if updErr := p.queries.UpdateDBInstanceStatus(ctx, db.UpdateDBInstanceStatusParams{ID: target.ID, Status: db.StatusEnumFailed}); updErr != nil {
+			klog.Errorf("Failed to mark clone '%s' as failed: %v", req.TargetInstanceName, updErr)
+		}
+		if delErr := p.secrets.Delete(ctx, engine.CredentialKey(customerID, req.TargetInstanceName)); delErr != nil {
+			klog.Errorf("Failed to delete credentials after clone failure: %v", delErr)
+		}
+		return nil, err
+	}
+
+	return &PostgresProvisionResponse{
+		InstanceName: req.TargetInstanceName,
+		Username:     username,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   customerID,
+	}, nil
+}
+
+// restoreSourceVars valide le point de restauration et renvoie les extra vars correspondantes
+// (backup_id ou point_in_time). required impose l'un des deux.
+func (p *PostgresService) restoreSourceVars(ctx context.Context, instance db.DbInstance, req RestoreRequest, required bool) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	verr := &ValidationError{}
+
+	switch {
+	case req.BackupID != "" && req.PointInTime != nil:
+		verr.Add("point_in_time", "cannot be combined with backup_id")
+	case req.BackupID != "":
+		backup, err := p.queries.GetBackupByBackupID(ctx, db.GetBackupByBackupIDParams{
+			CustomerID:   instance.CustomerID,
+			InstanceName: instance.InstanceName,
+			BackupID:     pgtype.Text{String: req.BackupID, Valid: true},
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrBackupNotFound
+			}
+			return nil, fmt.Errorf("db_error: %w", err)
+		}
+		if backup.Status != db.StatusEnumCompleted {
+			return nil, ErrBackupNotAvailable
+		}
+		vars["backup_id"] = req.BackupID
+	case req.PointInTime != nil:
+		switch {
+		case req.PointInTime.After(time.Now()):
+			verr.Add("point_in_time", "must be in the past")
+		case req.PointInTime.Before(instance.CreatedAt.Time):
+			verr.Add("point_in_time", "must be after the instance creation")
+		}
+		vars["point_in_time"] = req.PointInTime.UTC().Format(time.RFC3339)
+	case required:
+		verr.Add("backup_id", "backup_id or point_in_time is required")
+	}
+
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	return vars, nil
+}
+
+// restoreOperation identifie la cible d'une restauration en place pour le jeton de confirmation
+func restoreOperation(req RestoreRequest) string {
+	pointInTime := ""
+	if req.PointInTime != nil {
+		pointInTime = req.PointInTime.UTC().Format(time.RFC3339)
+	}
+	return fmt.Sprintf("restore|%s|%s", req.BackupID, pointInTime)
+}
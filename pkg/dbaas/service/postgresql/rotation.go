@@ -0,0 +1,81 @@
+package service_postgresql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"k8s.io/klog/v2"
+)
+
+// RotateCredentials lance le template AWX de rotation pour une instance du client.
+// Le nouveau mot de passe est récupérable une fois via GetCredentials quand le job a réussi.
+func (p *PostgresService) RotateCredentials(ctx context.Context, customerID, instanceName, createdBy string) (*PostgresProvisionResponse, error) {
+	// Deux rotations simultanées passeraient toutes deux la vérification des opérations en cours
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	password, err := engine.GeneratePassword()
+	if err != nil {
+		return nil, fmt.Errorf("password_generation_failed: %w", err)
+	}
+
+	// Identifiants non encore récupérés (création ou rotation précédente) : toujours valides tant que
+	// la rotation n'est pas lancée, ils sont remis en place si le lancement échoue
+	key := engine.CredentialKey(customerID, instanceName)
+	previous, err := p.secrets.Take(ctx, key)
+	if err != nil && !errors.Is(err, secret.ErrSecretNotFound) {
+		return nil, fmt.Errorf("secret_store_failed: %w", err)
+	}
+
+	creds := Credentials{Username: instance.Username.String, Password: password}
+	if err := p.engine.StoreCredentials(ctx, customerID, instanceName, creds); err != nil {
+		p.restoreCredentials(ctx, key, previous)
+		return nil, fmt.Errorf("secret_store_failed: %w", err)
+	}
+
+	history, err := p.engine.LaunchOperation(ctx, engine.OperationRequest{
+		Action:       db.ActionTypeEnumRotate,
+		TemplateName: p.cfg.Dbaas.Templates.RotateCredentials,
+		Instance:     instance,
+		ExtraVars: map[string]interface{}{
+			"instance_name": instanceName,
+			"username":      instance.Username.String,
+			"password":      password,
+			"customer_id":   customerID,
+		},
+		SecretKeys: []string{"password"},
+		CreatedBy:  createdBy,
+	})
+	if err != nil {
+		p.restoreCredentials(ctx, key, previous)
+		return nil, err
+	}
+
+	return &PostgresProvisionResponse{
+		InstanceName: instanceName,
+		Username:     instance.Username.String,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   customerID,
+	}, nil
+}
+
+// restoreCredentials remet les identifiants précédents, ou supprime le mot de passe jamais appliqué
+func (p *PostgresService) restoreCredentials(ctx context.Context, key string, previous []byte) {
+	var err error
+	if previous != nil {
+		err = p.secrets.Put(ctx, key, previous)
+	} else {
+		err = p.secrets.Delete(ctx, key)
+	}
+	if err != nil {
+		klog.Errorf("Failed to restore credentials after rotation failure: %v", err)
+	}
+}
@@ -0,0 +1,227 @@
+package service_postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	awxclient "github.com/Gskill75/api2/pkg/awx/client"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+	"k8s.io/klog/v2"
+)
+
+const (
+	minCPU      = 1
+	maxCPU      = 32
+	minMemoryGB = 1
+	maxMemoryGB = 256
+	minReplicas = 1
+	maxReplicas = 5
+)
+
+// Extra vars des opérations de mise à jour, relues au succès du job (et après une relance)
+const (
+	updateVarStorageGB = "storage_gb"
+	updateVarVersion   = "version"
+)
+
+// ResizeRequest nouvelle taille de l'instance ; le disque ne peut qu'être agrandi
+type ResizeRequest struct {
+	CPU      int `json:"cpu"`
+	MemoryGB int `json:"memory_gb"`
+	// Stockage en Go, inchangé si 0
+	StorageGB int32 `json:"storage_gb,omitempty"`
+}
+
+type ScaleReplicasRequest struct {
+	Replicas int `json:"replicas"`
+}
+
+type UpgradeRequest struct {
+	Version string `json:"version"`
+}
+
+// ResizeInstance change le CPU, la mémoire et éventuellement le disque de l'instance.
+// Le stockage de db_instances est mis à jour une fois le job réussi.
+func (p *PostgresService) ResizeInstance(ctx context.Context, customerID, instanceName string, req ResizeRequest, createdBy string) (*PostgresProvisionResponse, error) {
+	if p.cfg.Dbaas.Templates.Resize == "" {
+		return nil, fmt.Errorf("%w: resize", ErrTemplateNotConfigured)
+	}
+
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	verr := &ValidationError{}
+	if req.CPU < minCPU || req.CPU > maxCPU {
+		verr.Add("cpu", fmt.Sprintf("must be between %d and %d", minCPU, maxCPU))
+	}
+	if req.MemoryGB < minMemoryGB || req.MemoryGB > maxMemoryGB {
+		verr.Add("memory_gb", fmt.Sprintf("must be between %d and %d", minMemoryGB, maxMemoryGB))
+	}
+	storageGB := instance.StorageGb
+	if req.StorageGB != 0 {
+		if req.StorageGB < instance.StorageGb {
+			verr.Add("storage_gb", fmt.Sprintf("cannot be reduced below the current %d GB", instance.StorageGb))
+		}
+		storageGB = req.StorageGB
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	if err := p.engine.EnsureStorageQuota(ctx, customerID, storageGB-instance.StorageGb); err != nil {
+		return nil, err
+	}
+
+	return p.launchUpdate(ctx, instance, p.cfg.Dbaas.Templates.Resize, map[string]interface{}{
+		"cpu":              req.CPU,
+		"memory_gb":        req.MemoryGB,
+		updateVarStorageGB: storageGB,
+	}, createdBy)
+}
+
+// ScaleReplicas change le nombre de réplicas Patroni de l'instance
+func (p *PostgresService) ScaleReplicas(ctx context.Context, customerID, instanceName string, req ScaleReplicasRequest, createdBy string) (*PostgresProvisionResponse, error) {
+	verr := &ValidationError{}
+	if req.Replicas < minReplicas || req.Replicas > maxReplicas {
+		verr.Add("replicas", fmt.Sprintf("must be between %d and %d", minReplicas, maxReplicas))
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	if p.cfg.Dbaas.Templates.Replicas == "" {
+		return nil, fmt.Errorf("%w: replicas", ErrTemplateNotConfigured)
+	}
+
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return p.launchUpdate(ctx, instance, p.cfg.Dbaas.Templates.Replicas, map[string]interface{}{
+		"replicas": req.Replicas,
+	}, createdBy)
+}
+
+// UpgradeInstance monte l'instance vers une version majeure supérieure.
+// La version de db_instances est mise à jour une fois le job réussi.
+func (p *PostgresService) UpgradeInstance(ctx context.Context, customerID, instanceName string, req UpgradeRequest, createdBy string) (*PostgresProvisionResponse, error) {
+	if p.cfg.Dbaas.Templates.Upgrade == "" {
+		return nil, fmt.Errorf("%w: upgrade", ErrTemplateNotConfigured)
+	}
+
+	instance, unlock, err := p.engine.LockReadyInstance(ctx, customerID, instanceName)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	verr := &ValidationError{}
+	current := instance.Version.String
+	switch {
+	case !slices.Contains(p.cfg.Dbaas.PostgresVersions, req.Version):
+		verr.Add("version", fmt.Sprintf("%s: must be one of %v", engine.ErrVersionNotSupported, p.cfg.Dbaas.PostgresVersions))
+	case current == "":
+		// Instance créée sans version enregistrée : impossible de garantir une montée de version
+		verr.Add("version", "current version of the instance is unknown, upgrade not possible")
+	case majorVersion(req.Version) <= majorVersion(current):
+		verr.Add("version", fmt.Sprintf("must be a major version above the current %s", current))
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	return p.launchUpdate(ctx, instance, p.cfg.Dbaas.Templates.Upgrade, map[string]interface{}{
+		updateVarVersion:  req.Version,
+		"current_version": current,
+	}, createdBy)
+}
+
+// launchUpdate lance une opération de mise à jour, tracée en action update dans awx_history.
+// L'appelant détient le verrou de l'instance (LockReadyInstance)
+func (p *PostgresService) launchUpdate(ctx context.Context, instance db.DbInstance, templateName string, extraVars map[string]interface{}, createdBy string) (*PostgresProvisionResponse, error) {
+	extraVars["instance_name"] = instance.InstanceName
+	extraVars["customer_id"] = instance.CustomerID
+
+	history, err := p.engine.LaunchOperation(ctx, engine.OperationRequest{
+		Action:       db.ActionTypeEnumUpdate,
+		TemplateName: templateName,
+		Instance:     instance,
+		ExtraVars:    extraVars,
+		CreatedBy:    createdBy,
+		OnDone:       p.applyUpdateHook(instance.ID, extraVars),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &PostgresProvisionResponse{
+		InstanceName: instance.InstanceName,
+		Username:     instance.Username.String,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(history.Status),
+		CustomerID:   instance.CustomerID,
+		ScheduledAt:  engine.ScheduledAt(history),
+	}, nil
+}
+
+// applyUpdateHook reporte la nouvelle version ou le nouveau stockage sur db_instances quand le job a réussi
+func (p *PostgresService) applyUpdateHook(instanceID int32, extraVars map[string]interface{}) engine.JobCompletionHook {
+	return func(ctx context.Context, historyID int32, status db.StatusEnum, job *awxclient.Job) {
+		if status != db.StatusEnumCompleted {
+			return
+		}
+		if version, ok := extraVars[updateVarVersion].(string); ok && version != "" {
+			if err := p.queries.UpdateDBInstanceVersion(ctx, db.UpdateDBInstanceVersionParams{
+				ID:      instanceID,
+				Version: pgtype.Text{String: version, Valid: true},
+			}); err != nil {
+				klog.Errorf("Failed to update version of instance %d: %v", instanceID, err)
+			}
+		}
+		if storage, ok := engine.ToFloat(extraVars[updateVarStorageGB]); ok {
+			if err := p.queries.UpdateDBInstanceStorage(ctx, db.UpdateDBInstanceStorageParams{
+				ID:        instanceID,
+				StorageGb: int32(storage),
+			}); err != nil {
+				klog.Errorf("Failed to update storage of instance %d: %v", instanceID, err)
+			}
+		}
+	}
+}
+
+// updateRelaunchHook reconstruit le hook d'une mise à jour relancée depuis ses extra vars
+func (p *PostgresService) updateRelaunchHook(ctx context.Context, history *db.AwxHistory) engine.JobCompletionHook {
+	instance, err := p.queries.GetDBInstanceByName(ctx, db.GetDBInstanceByNameParams{
+		InstanceName: history.InstanceName,
+		CustomerID:   history.CustomerID,
+	})
+	if err != nil {
+		klog.Errorf("Instance '%s' of relaunched job not found: %v", history.InstanceName, err)
+		return nil
+	}
+	var extraVars map[string]interface{}
+	if err := json.Unmarshal(history.ExtraVars, &extraVars); err != nil {
+		klog.Errorf("Invalid extra_vars of relaunched job %d: %v", history.AwxJobID.Int64, err)
+		return nil
+	}
+	return p.applyUpdateHook(instance.ID, extraVars)
+}
+
+// majorVersion renvoie la version majeure ("16.2" -> 16), 0 si illisible
+func majorVersion(version string) int {
+	major, _, _ := strings.Cut(version, ".")
+	n, err := strconv.Atoi(major)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,26 @@
+package service_postgresql
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RunScheduler exécute les opérations planifiées à chaque intervalle, jusqu'à l'annulation du contexte
+func (p *PostgresService) RunScheduler(ctx context.Context, interval time.Duration) {
+	klog.Infof("DBaaS scheduler started, interval=%s", interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			klog.Info("DBaaS scheduler stopped")
+			return
+		case <-ticker.C:
+			p.runDueBackups(ctx)
+			p.engine.RunDueOperations(ctx)
+		}
+	}
+}
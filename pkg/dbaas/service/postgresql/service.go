@@ -2,28 +2,52 @@ package service_postgresql
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	awxclient "github.com/Gskill75/api2/pkg/awx/client"
 	"github.com/Gskill75/api2/pkg/config"
 	db "github.com/Gskill75/api2/pkg/db/sqlc/postgresql"
+	"github.com/Gskill75/api2/pkg/dbaas/secret"
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
 	"k8s.io/klog/v2"
 )
 
+// DbType db_type des instances PostgreSQL
+const DbType = "postgres"
+
 type PostgresService struct {
-	awxClient *awxclient.Client
-	queries   *db.Queries
-	cfg       *config.Config
+	awxClient       *awxclient.Client
+	queries         *db.Queries
+	cfg             *config.Config
+	secrets         secret.Store
+	confirmationKey []byte
+	engine          *engine.Service
 }
 
+// Erreurs métier sur les instances, partagées avec les autres moteurs
+var (
+	ErrInstanceNotFound      = engine.ErrInstanceNotFound
+	ErrInstanceAlreadyExists = engine.ErrInstanceAlreadyExists
+	ErrInstanceNotReady      = engine.ErrInstanceNotReady
+	ErrOperationInProgress   = engine.ErrOperationInProgress
+	ErrTemplateNotConfigured = engine.ErrTemplateNotConfigured
+	ErrQuotaExceeded         = engine.ErrQuotaExceeded
+)
+
 type PostgresProvisionRequest struct {
+	// Template de création, parmi dbaas.templates.provision ; le premier si vide
 	TemplateName string `json:"template_name"`
 	InstanceName string `json:"instance_name"`
 	Username     string `json:"username"`
-	Password     string `json:"password"`
 	CustomerID   string `json:"customer_id"`
+	// Stockage demandé en Go, la valeur par défaut des quotas si 0
+	StorageGB int32 `json:"storage_gb"`
+	// Version majeure, enregistrée sur l'instance pour les montées de version
+	Version string `json:"version,omitempty"`
 }
 
 type PostgresProvisionResponse struct {
@@ -32,88 +56,74 @@ type PostgresProvisionResponse struct {
 	JobID        int    `json:"job_id"`
 	Status       string `json:"status"`
 	CustomerID   string `json:"customer_id"`
+	// Lancement prévu d'une opération disruptive mise en attente de la fenêtre de maintenance
+	ScheduledAt *time.Time `json:"scheduled_at,omitempty"`
 }
 
-func NewPostgresService(awxClient *awxclient.Client, queries *db.Queries, cfg *config.Config) *PostgresService {
-	return &PostgresService{
-		awxClient: awxClient,
-		queries:   queries,
-		cfg:       cfg,
+func NewPostgresService(awxClient *awxclient.Client, queries *db.Queries, cfg *config.Config, secrets secret.Store) *PostgresService {
+	engineCfg := config.DbaasEngine{
+		Name:                DbType,
+		Versions:            cfg.Dbaas.PostgresVersions,
+		ReservedUsernames:   []string{"postgres"},
+		DisruptiveTemplates: cfg.Dbaas.DisruptiveTemplates,
+	}
+	if len(cfg.Dbaas.Templates.Provision) > 0 {
+		engineCfg.Templates.Provision = cfg.Dbaas.Templates.Provision[0]
+	}
+	p := &PostgresService{
+		awxClient:       awxClient,
+		queries:         queries,
+		cfg:             cfg,
+		secrets:         secrets,
+		confirmationKey: loadConfirmationKey(cfg.Dbaas.ConfirmationKey),
+		engine:          engine.NewService(engineCfg, cfg.Dbaas.Quotas, awxClient, queries, secrets),
 	}
+	// Les opérations planifiées reprennent le suivi des opérations relancées
+	p.engine.SetHookResolver(p.relaunchHook)
+	return p
 }
 
+// ProvisionDatabase crée une instance PostgreSQL avec le template demandé, via le provisioning commun des moteurs
 func (p *PostgresService) ProvisionDatabase(ctx context.Context, req PostgresProvisionRequest, createdBy string) (*PostgresProvisionResponse, error) {
 	klog.Infof("Provisioning PostgreSQL instance - starting")
 
-	templateID, err := p.awxClient.JobTemplateService().GetTemplateIDByName(ctx, req.TemplateName)
-	if err != nil {
-		return nil, fmt.Errorf("template_name_not_found: %w", err)
+	if len(p.cfg.Dbaas.Templates.Provision) == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotConfigured, db.ActionTypeEnumCreate)
 	}
-
-	klog.Infof("Launching AWX job template '%d' with name '%v'", templateID, req.TemplateName)
-
-	// Prepare extra variables for the job
-	extraVars := map[string]interface{}{
-		"instance_name": req.InstanceName,
-		"custom":        "TESTIII",
-		"username":      req.Username,
-		"password":      req.Password,
-		"customer_id":   req.CustomerID,
+	// Validation des paramètres avant tout appel à AWX
+	if err := validateProvisionRequest(req, p.cfg.Dbaas.Templates.Provision); err != nil {
+		return nil, err
 	}
 
-	klog.Infof("Calling AWX API to launch job...")
-	response, err := p.awxClient.JobTemplateService().LaunchJob(ctx, templateID, extraVars)
-	if err != nil {
-		klog.Errorf("Failed to launch PostgreSQL provisioning job: %v", err)
-		return nil, fmt.Errorf("awx_launch_failed: %w", err)
-	}
-	klog.Infof("AWX API call successful - Job ID: %d", response.Job)
-
-	// Convert extraVars to JSON for database storage
-	extraVarsJSON, err := json.Marshal(extraVars)
-	if err != nil {
-		klog.Errorf("Failed to marshal extra_vars to JSON: %v", err)
-		return nil, fmt.Errorf("failed_to_marshal_extra_vars: %w", err)
-	}
-
-	// Insert l'action en db
-	historyRecord, err := p.queries.CreateHistory(ctx, db.CreateHistoryParams{
-		InstanceName:    req.InstanceName,
-		CustomerID:      req.CustomerID,
-		AwxJobID:        pgtype.Int8{Int64: int64(response.Job), Valid: true},
-		AwxTemplateName: pgtype.Text{String: req.TemplateName, Valid: true},
-		AwxTemplateID:   pgtype.Int4{Int32: int32(templateID), Valid: true},
-		ActionType:      "create",
-		Status:          "running",
-		ExtraVars:       extraVarsJSON,
-		CreatedBy:       createdBy,
-	})
-	if err != nil {
-		klog.Errorf("Job launched but failed to insert into DB: %v", err)
-		return nil, fmt.Errorf("db_insert_failed: %w", err)
-	}
-
-	// Start monitoring job for status updates (use background context)
-	err = p.DoMonitorJob(context.Background(), response.Job, historyRecord.ID)
+	resp, err := p.engine.Provision(ctx, req.CustomerID, engine.ProvisionRequest{
+		InstanceName: req.InstanceName,
+		Username:     req.Username,
+		StorageGB:    req.StorageGB,
+		Version:      req.Version,
+		TemplateName: req.TemplateName,
+	}, createdBy)
 	if err != nil {
-		klog.Errorf("Failed to start job monitoring for job %d: %v", response.Job, err)
-		// Don't return error as job is already launched and recorded
+		return nil, err
 	}
 
 	klog.Infof("PostgreSQL instance '%s' provisioning started for customer %s", req.InstanceName, req.CustomerID)
-
 	return &PostgresProvisionResponse{
-		InstanceName: req.InstanceName,
-		Username:     req.Username,
-		JobID:        response.Job,
-		Status:       "running",
-		CustomerID:   req.CustomerID,
+		InstanceName: resp.InstanceName,
+		Username:     resp.Username,
+		JobID:        resp.JobID,
+		Status:       resp.Status,
+		CustomerID:   resp.CustomerID,
 	}, nil
 }
 
-// GetJobStatus retrieves the status of a provisioning job
-func (p *PostgresService) GetJobStatus(ctx context.Context, jobID int) (*PostgresProvisionResponse, error) {
-	job, err := p.awxClient.JobService().GetJob(ctx, jobID)
+// GetJobStatus retrieves the status of a job launched for the customer
+func (p *PostgresService) GetJobStatus(ctx context.Context, customerID string, jobID int) (*PostgresProvisionResponse, error) {
+	history, err := p.getJobForCaller(ctx, Caller{CustomerID: customerID}, jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := p.awxClient.GetUnifiedJob(ctx, awxclient.JobKind(history.AwxJobType), jobID)
 	if err != nil {
 		return nil, fmt.Errorf("failed_to_get_job_status: %w", err)
 	}
@@ -131,100 +141,37 @@ func (p *PostgresService) GetJobStatus(ctx context.Context, jobID int) (*Postgre
 	}
 
 	return &PostgresProvisionResponse{
-		JobID:  job.ID,
-		Status: status,
+		InstanceName: history.InstanceName,
+		JobID:        job.ID,
+		Status:       status,
+		CustomerID:   customerID,
 	}, nil
 }
 
-// CheckActiveJob pour verifier avant lancement si duplicat
+// CheckActiveJob pour verifier avant lancement si duplicat : seuls les jobs du client sont pris en compte
 func (p *PostgresService) CheckActiveJob(ctx context.Context, customerID, templateName string) (*PostgresProvisionResponse, error) {
-	activeJobs, err := p.awxClient.JobService().GetRunningJobsForTemplate(ctx, templateName)
+	history, err := p.queries.GetActiveHistoryByTemplate(ctx, db.GetActiveHistoryByTemplateParams{
+		CustomerID:      customerID,
+		AwxTemplateName: pgtype.Text{String: templateName, Valid: true},
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed_to_get_running_job_for_template: %w", err)
-	}
-
-	klog.Infof("Found %d jobs for template %s", len(activeJobs), templateName)
-
-	// Check if any jobs are in active states
-	for _, job := range activeJobs {
-		if job.Status == "running" || job.Status == "waiting" || job.Status == "pending" {
-			klog.Infof("Found active job %d with status %s", job.ID, job.Status)
-
-			var status string
-			switch job.Status {
-			case "running", "waiting", "pending":
-				status = "running"
-			default:
-				status = job.Status
-			}
-
-			return &PostgresProvisionResponse{
-				JobID:      job.ID,
-				Status:     status,
-				CustomerID: customerID,
-			}, nil
+		if errors.Is(err, pgx.ErrNoRows) {
+			klog.Infof("No active jobs found for template %s and customer %s", templateName, customerID)
+			return nil, nil
 		}
+		return nil, fmt.Errorf("db_error: %w", err)
 	}
 
-	// No active jobs found
-	klog.Infof("No active jobs found for template %s", templateName)
-	return nil, nil
+	klog.Infof("Found active job %d with status %s for customer %s", history.AwxJobID.Int64, history.Status, customerID)
+	return &PostgresProvisionResponse{
+		InstanceName: history.InstanceName,
+		Username:     history.Username.String,
+		JobID:        int(history.AwxJobID.Int64),
+		Status:       string(db.StatusEnumRunning),
+		CustomerID:   customerID,
+	}, nil
 }
 
 func (p *PostgresService) DoMonitorJob(ctx context.Context, jobID int, historyID int32) error {
-	klog.Infof("Starting job monitoring for job ID: %d", jobID)
-
-	go func() {
-		defer func() {
-			if r := recover(); r != nil {
-				klog.Errorf("Job monitoring panic for job %d: %v", jobID, r)
-			}
-		}()
-
-		// MonitorJob blocks until job completes, so we call it in goroutine
-		finalJob, err := p.awxClient.JobService().MonitorJob(ctx, jobID)
-		if err != nil {
-			klog.Errorf("Failed to monitor job %d: %v", jobID, err)
-
-			// Update database with failed status even if monitoring failed
-			err = p.queries.UpdateHistoryCompletion(ctx, db.UpdateHistoryCompletionParams{
-				ID:           historyID,
-				Status:       db.StatusEnum(finalJob.Status),
-				AwxStatus:    db.NullAwxStatusEnum{AwxStatusEnum: db.AwxStatusEnum("canceled"), Valid: true},
-				ErrorMessage: pgtype.Text{String: err.Error(), Valid: true},
-			})
-
-			if err != nil {
-				klog.Errorf("Failed to update failed status for job %d: %v", jobID, err)
-			}
-			return
-		}
-
-		// Map AWX status to our enum
-		var status string
-		switch finalJob.Status {
-		case "successful":
-			status = "completed"
-		case "failed", "error", "canceled":
-			status = "failed"
-		default:
-			status = "failed" // Default to failed if unknown
-		}
-
-		// Update database with final status
-		err = p.queries.UpdateHistoryCompletion(ctx, db.UpdateHistoryCompletionParams{
-			ID:           historyID,
-			Status:       db.StatusEnum(status),
-			AwxStatus:    db.NullAwxStatusEnum{AwxStatusEnum: db.AwxStatusEnum(finalJob.Status), Valid: true},
-			ErrorMessage: pgtype.Text{String: "", Valid: finalJob.Status != "successful"},
-		})
-
-		if err != nil {
-			klog.Errorf("Failed to update completion for job %d: %v", jobID, err)
-		} else {
-			klog.Infof("Job %d completed with status: %s", jobID, status)
-		}
-	}()
-
-	return nil
+	return p.engine.MonitorJob(ctx, p.engine.JobKind(ctx, jobID), jobID, historyID, nil)
 }
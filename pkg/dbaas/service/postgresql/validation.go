@@ -0,0 +1,34 @@
+package service_postgresql
+
+import (
+	"fmt"
+	"slices"
+
+	engine "github.com/Gskill75/api2/pkg/dbaas/service/engine"
+)
+
+// ValidationError regroupe les erreurs de validation par champ (renvoyées en 400)
+type ValidationError = engine.ValidationError
+
+// validateProvisionRequest applique les règles fixes, indépendantes du template AWX
+func validateProvisionRequest(req PostgresProvisionRequest, provisionTemplates []string) error {
+	verr := &ValidationError{}
+
+	// Seuls les templates de création configurés peuvent être lancés, le client ne choisit pas un template quelconque
+	if req.TemplateName != "" && !slices.Contains(provisionTemplates, req.TemplateName) {
+		verr.Add("template_name", fmt.Sprintf("must be one of %v", provisionTemplates))
+	}
+
+	engine.ValidateInstanceName(verr, "instance_name", req.InstanceName)
+	engine.ValidateUsername(verr, "username", req.Username, "postgres")
+
+	if req.CustomerID == "" {
+		verr.Add("customer_id", "is required")
+	}
+	if req.StorageGB < 0 {
+		verr.Add("storage_gb", "must be positive")
+	}
+
+	return verr.OrNil()
+}
+
@@ -14,6 +14,7 @@ var (
 	ErrConflict        = errors.New("conflict")
 	ErrTooManyRequests = errors.New("too_many_requests")
 	ErrInternalError   = errors.New("internal_error")
+	ErrUnavailable     = errors.New("unavailable")
 )
 
 // Wrappers pratiques pour créer des erreurs typées
@@ -40,3 +41,7 @@ func NewConflict(msg string) error {
 func NewInternalError(msg string) error {
 	return fmt.Errorf("%w: %s", ErrInternalError, msg)
 }
+
+func NewUnavailable(msg string) error {
+	return fmt.Errorf("%w: %s", ErrUnavailable, msg)
+}
@@ -2,8 +2,11 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 
+	"github.com/go-openapi/runtime"
 	harbor "github.com/goharbor/go-client/pkg/harbor"
 	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
 	"github.com/Gskill75/api2/pkg/config"
@@ -75,3 +78,39 @@ func (c *Client) Ping() error {
 	klog.Info("Harbor ping successful")
 	return nil
 }
+
+// IsNotFound reports whether err is a 404 answer from Harbor
+func IsNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
+
+// IsConflict reports whether err is a 409 answer from Harbor
+func IsConflict(err error) bool {
+	return hasStatus(err, http.StatusConflict)
+}
+
+// IsBadRequest reports whether err is a 400 answer from Harbor, e.g. an invalid
+// retention schedule
+func IsBadRequest(err error) bool {
+	return hasStatus(err, http.StatusBadRequest)
+}
+
+// IsPreconditionFailed reports whether err is a 412 answer from Harbor, e.g. when
+// deleting an immutable artifact
+func IsPreconditionFailed(err error) bool {
+	return hasStatus(err, http.StatusPreconditionFailed)
+}
+
+// hasStatus matches both the typed responses of the go-client and the generic
+// runtime.APIError returned for status codes missing from the swagger spec
+func hasStatus(err error, code int) bool {
+	var apiErr *runtime.APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == code
+	}
+	var coded interface{ IsCode(int) bool }
+	if errors.As(err, &coded) {
+		return coded.IsCode(code)
+	}
+	return false
+}
@@ -1,27 +1,44 @@
 package harbor
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/Gskill75/api2/pkg/config"
 	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	kubernetesdb "github.com/Gskill75/api2/pkg/db/sqlc/kubernetes"
 	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
 	"github.com/Gskill75/api2/pkg/harbor/project"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	k8sclient "github.com/Gskill75/api2/pkg/kubernetes/client"
+	"github.com/Gskill75/api2/pkg/utils"
 )
 
 type HarborSolution struct {
-	client  *harborclient.Client
-	queries *db.Queries
-	cfg     *config.Config
+	client      *harborclient.Client
+	queries     *db.Queries
+	cfg         *config.Config
+	service     *service.ProjectService
+	pullSecrets *service.PullSecretService
 }
 
-func NewHarborSolution(cfg *config.Config, client *harborclient.Client, queries *db.Queries) *HarborSolution {
+func NewHarborSolution(cfg *config.Config, client *harborclient.Client, queries *db.Queries, kubeClient *k8sclient.Client, k8sQueries *kubernetesdb.Queries) *HarborSolution {
+	projectService := service.NewProjectService(cfg, queries, client)
 	return &HarborSolution{
-		client:  client,
-		queries: queries,
-		cfg:     cfg,
+		client:      client,
+		queries:     queries,
+		cfg:         cfg,
+		service:     projectService,
+		pullSecrets: service.NewPullSecretService(cfg, projectService, kubeClient, k8sQueries),
 	}
 }
 
+// RunRefresher renouvelle les imagePullSecrets dont le robot Harbor arrive à expiration
+func (s *HarborSolution) RunRefresher(ctx context.Context) {
+	s.pullSecrets.RunRefresher(ctx, time.Duration(s.cfg.Harbor.PullSecretRefreshInterval)*time.Second)
+}
+
 func (*HarborSolution) Name() string {
 	return "harbor"
 }
@@ -34,17 +51,70 @@ func (s *HarborSolution) Endpoint(rg *gin.RouterGroup) {
 	{
 		prj := rg.Group("/project")
 		{
-			prj.POST("/create/:name", project.CreateProjectHandler(s.client, s.queries))
-			prj.POST("/create/robot/:name")
-			prj.GET("/robot/:name")
-			prj.GET("/:name")
+			prj.GET("", project.ListProjectsHandler(s.service))
+			prj.POST("/create/:name", project.CreateProjectHandler(s.service))
+			prj.POST("/create/robot/:name", project.CreateRobotHandler(s.service))
+			prj.GET("/robot/:name", project.ListRobotsHandler(s.service))
+			prj.GET("/:name", project.GetProjectHandler(s.service))
+			prj.DELETE("/:name", project.DeleteProjectHandler(s.service))
+			prj.PUT("/:name/quota", project.SetProjectQuotaHandler(s.service))
+			prj.GET("/:name/repositories", project.ListRepositoriesHandler(s.service))
+			prj.GET("/:name/artifacts", project.ListArtifactsHandler(s.service))
+			prj.DELETE("/:name/artifacts/:reference", project.DeleteArtifactHandler(s.service))
+			prj.DELETE("/:name/tags/:tag", project.DeleteTagHandler(s.service))
+			prj.POST("/:name/artifacts/:reference/scan", project.ScanArtifactHandler(s.service))
+			prj.GET("/:name/artifacts/:reference/vulnerabilities", project.GetVulnerabilitySummaryHandler(s.service))
+			prj.GET("/:name/artifacts/:reference/vulnerabilities/report", project.GetVulnerabilityReportHandler(s.service))
+			prj.GET("/:name/artifacts/:reference/sbom", project.GetSBOMHandler(s.service))
+			prj.GET("/:name/vulnerabilities", project.GetProjectVulnerabilitiesHandler(s.service))
+			prj.GET("/:name/retention", project.GetRetentionPolicyHandler(s.service))
+			prj.PUT("/:name/retention", project.SetRetentionPolicyHandler(s.service))
+			prj.GET("/:name/immutability", project.ListImmutableRulesHandler(s.service))
+			prj.POST("/:name/immutability", project.CreateImmutableRuleHandler(s.service))
+			prj.DELETE("/:name/immutability/:id", project.DeleteImmutableRuleHandler(s.service))
+			prj.GET("/:name/members", project.ListMembersHandler(s.service))
+			prj.POST("/:name/members", project.AddMemberHandler(s.service))
+			prj.DELETE("/:name/members/:id", project.RemoveMemberHandler(s.service))
+			prj.GET("/:name/replications", project.ListReplicationsHandler(s.service))
+			prj.POST("/:name/replications", project.CreateReplicationHandler(s.service))
+			prj.DELETE("/:name/replications/:id", project.DeleteReplicationHandler(s.service))
+			prj.POST("/:name/replications/:id/run", project.RunReplicationHandler(s.service))
+			prj.GET("/:name/replications/:id/executions", project.ListReplicationExecutionsHandler(s.service))
+			prj.GET("/:name/pull-secrets", project.ListPullSecretsHandler(s.pullSecrets))
+			prj.POST("/:name/pull-secrets", project.LinkNamespaceHandler(s.pullSecrets))
+			prj.DELETE("/:name/pull-secrets/:namespace", project.UnlinkNamespaceHandler(s.pullSecrets))
+			prj.POST("/:name/pull-secrets/:namespace/refresh", project.RefreshPullSecretHandler(s.pullSecrets))
 
 		}
+		rg.GET("/history", project.ListHistoryHandler(s.service))
+		rg.GET("/upstreams", project.ListUpstreamsHandler(s.service))
 		robot := rg.Group("/robot")
 		{
-			robot.GET("/:name")
-			robot.POST("/create/:name")
-			robot.POST("/:name/members")
+			robot.GET("/:id", project.GetRobotHandler(s.service))
+			robot.DELETE("/:id", project.DeleteRobotHandler(s.service))
+		}
+	}
+
+	s.setupAdminRoutes(rg)
+}
+
+// setupAdminRoutes configure les routes d'administration Harbor
+func (s *HarborSolution) setupAdminRoutes(rg *gin.RouterGroup) {
+	adminGroup := rg.Group("/admin")
+
+	adminGroup.Use(func(c *gin.Context) {
+		if _, ok := utils.GetCustomerIDOrAbort(c); !ok {
+			return
 		}
+		if !utils.IsAdminOrAbort(c) {
+			return
+		}
+		c.Next()
+	})
+
+	{
+		adminGroup.GET("/plans/:customer_id", project.GetCustomerPlanAdminHandler(s.service))
+		adminGroup.PUT("/plans/:customer_id", project.SetCustomerPlanAdminHandler(s.service))
+		adminGroup.GET("/history", project.ListHistoryAdminHandler(s.service))
 	}
 }
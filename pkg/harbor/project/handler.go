@@ -1,43 +1,46 @@
 package project
 
 import (
-	"context"
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/go-openapi/runtime"
-	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
-	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
 	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
-	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
 
 	"k8s.io/klog/v2"
 )
 
 // CreateProjectRequest : structure attendue via la requête API interne
 type createProjectRequest struct {
-	Name         string `json:"project_name" binding:"required"`
-	Public       bool   `json:"public"`
-	CustomerID   string `json:"customer_id"` // optionnel sauf pour admin
-	CreatedBy    string `json:"created_by" binding:"required"`
-	StorageLimit int64  `json:"storage_limit"`
-}
-
-// harborAPIProject représente le JSON attendu par Harbor
-type harborAPIProject struct {
-	ProjectName string `json:"project_name"`
-	Public      int    `json:"public,string"` // Harbor attend un int entre 0/1 sous forme string éventuellement
-	Metadata    struct {
-		Public bool `json:"public,string"`
-	} `json:"metadata,omitempty"`
-	CountLimit     int  `json:"count_limit"`
-	StorageLimit   int  `json:"storage_limit"`
-	ClearTextArtif bool `json:"enable_content_trust"`
+	Name       string `json:"project_name" binding:"required"`
+	Public     bool   `json:"public"`
+	CustomerID string `json:"customer_id"` // pris en compte uniquement pour un admin
+	// Limite de stockage en Go, la valeur par défaut du plan du client si 0
+	StorageLimit int64 `json:"storage_limit"`
+	// Registre amont autorisé (voir GET /upstreams) pour créer un projet proxy-cache
+	Upstream string `json:"upstream"`
 }
 
 // CreateProjectHandler godoc
 // @Summary     Create a new Harbor Project
-func CreateProjectHandler(h *harborclient.Client, queries *db.Queries) gin.HandlerFunc {
+// @Description Creates a Harbor project owned by the customer of the token (admins may set customer_id). The creator is taken from the token. storage_limit is in GB: the default of the customer plan applies when omitted, and it cannot exceed the plan maximum. Set upstream to one of the approved upstream registries to create a proxy-cache project. The default retention and immutability policies are applied to the new project (retention only for a proxy-cache).
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       request body createProjectRequest true "Project"
+// @Success     201 {object} service.CreateProjectResult "Project created"
+// @Failure     400 {object} map[string]string "Invalid request body or upstream not allowed"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]interface{} "Storage limit exceeds the customer plan"
+// @Failure     409 {object} map[string]string "Project already exists"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/create/{name} [post]
+// @Security Bearer
+func CreateProjectHandler(projectService *service.ProjectService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		rid := c.GetString("request_id")
 
@@ -48,83 +51,204 @@ func CreateProjectHandler(h *harborclient.Client, queries *db.Queries) gin.Handl
 			return
 		}
 
-		role := c.GetString("role")
-		customerID := c.GetString("customer_id")
-		if role == "admin" && req.CustomerID != "" {
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		if req.CustomerID != "" && utils.IsAdmin(c) {
 			customerID = req.CustomerID
 		}
-		if customerID == "" {
-			klog.Warningf("[request_id=%s] Missing customer_id", rid)
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Missing customer_id", "request_id": rid})
+
+		result, err := projectService.CreateProject(c.Request.Context(), service.CreateProjectParams{
+			Name:           req.Name,
+			Public:         req.Public,
+			CustomerID:     customerID,
+			CreatedBy:      c.GetString("sub"),
+			StorageLimitGB: req.StorageLimit,
+			Upstream:       req.Upstream,
+		})
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumCreateProject, req.Name, "", err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to create Harbor project '%s': %v", rid, req.Name, err)
+			respondServiceError(c, err, "Harbor create failed")
 			return
 		}
 
-		// Vérifie si le projet existe déjà
-		headParams := project.NewHeadProjectParams().WithProjectName(req.Name)
-		_, err := h.ClientSet().V2().Project.HeadProject(context.Background(), headParams)
-		if err == nil {
-			klog.Warningf("[request_id=%s] Harbor project '%s' already exists", rid, req.Name)
-			c.JSON(http.StatusConflict, gin.H{"error": "Project already exists", "request_id": rid})
+		klog.Infof("[request_id=%s] Harbor project '%s' created for customer '%s' (plan=%s, storage=%dGB)", rid, req.Name, customerID, result.Plan, result.StorageLimitGB)
+		c.JSON(http.StatusCreated, gin.H{
+			"message":          "Harbor project created successfully",
+			"name":             result.Name,
+			"customer_id":      result.CustomerID,
+			"created_by":       result.CreatedBy,
+			"plan":             result.Plan,
+			"storage_limit_gb": result.StorageLimitGB,
+			"upstream":         result.Upstream,
+			"request_id":       rid,
+		})
+	}
+}
+
+// GetProjectHandler godoc
+// @Summary     Get Harbor project details
+// @Description Returns a Harbor project owned by the customer: metadata, repository count and storage quota usage (bytes, -1 when unlimited)
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {object} service.ProjectDetails "Project details"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name} [get]
+// @Security Bearer
+func GetProjectHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
 			return
-		} else {
-			if apiErr, ok := err.(*runtime.APIError); !ok || apiErr.Code != http.StatusNotFound {
-				klog.Errorf("[request_id=%s] Failed to check Harbor project: %v", rid, err)
-				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check project existence", "request_id": rid})
-				return
-			}
 		}
+		name := c.Param("name")
 
-		// Prépare le projet à créer
-		p := &models.ProjectReq{
-			ProjectName: req.Name,
-			Metadata: &models.ProjectMetadata{
-				Public:             boolToString(req.Public),
-				AutoScan:           strPtr("true"),
-				AutoSbomGeneration: strPtr("true"),
-				PreventVul:         strPtr("true"),
-				Severity:           strPtr("critical"),
-			},
+		details, err := projectService.GetProject(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to get project")
+			return
 		}
 
-		createParams := project.NewCreateProjectParams().WithProject(p)
+		c.JSON(http.StatusOK, details)
+	}
+}
 
-		_, err = h.ClientSet().V2().Project.CreateProject(context.Background(), createParams)
-		if err != nil {
-			klog.Errorf("[request_id=%s] Failed to create Harbor project: %v", rid, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Harbor create failed", "request_id": rid})
+// ListProjectsHandler godoc
+// @Summary     List Harbor projects
+// @Description Lists the Harbor projects owned by the customer, completed with live Harbor data (in_harbor is false when the project no longer exists in Harbor)
+// @Tags        harbor
+// @Produce     json
+// @Success     200 {array} service.ProjectListItem "Projects"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project [get]
+// @Security Bearer
+func ListProjectsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
 			return
 		}
 
-		// Enregistrement DB
-		err = queries.InsertHarborProject(c.Request.Context(), db.InsertHarborProjectParams{
-			Name:       req.Name,
-			CustomerID: customerID,
-			CreatedBy:  req.CreatedBy,
-		})
+		projects, err := projectService.ListProjects(c.Request.Context(), customerID)
 		if err != nil {
-			klog.Errorf("[request_id=%s] Harbor project created but failed to insert into DB: %v", rid, err)
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "DB insert failed", "request_id": rid})
+			klog.Errorf("[request_id=%s] Failed to list Harbor projects of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to list projects")
 			return
 		}
 
-		klog.Infof("[request_id=%s] Harbor project '%s' created for customer '%s'", rid, req.Name, customerID)
-		c.JSON(http.StatusCreated, gin.H{
-			"message":     "Harbor project created successfully",
-			"name":        req.Name,
-			"customer_id": customerID,
-			"created_by":  req.CreatedBy,
-			"request_id":  rid,
-		})
+		c.JSON(http.StatusOK, projects)
 	}
 }
 
-func boolToString(b bool) string {
-	if b {
-		return "true"
+// DeleteProjectHandler godoc
+// @Summary     Delete a Harbor project
+// @Description Deletes a Harbor project owned by the customer. A project containing repositories is refused unless force=true, which removes the immutability rules of the project and deletes its repositories first. A repository still holding immutable tags is reported with 412. The pull secrets of the project are removed from the linked namespaces.
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       force query bool false "Delete the repositories of the project first"
+// @Success     200 {object} service.DeleteProjectResponse "Project deleted"
+// @Failure     400 {object} map[string]string "Invalid force parameter"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     409 {object} map[string]string "Project is not empty"
+// @Failure     412 {object} map[string]string "Repository holds immutable tags"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor or Kubernetes error"
+// @Router      /harbor/v1/project/{name} [delete]
+// @Security Bearer
+func DeleteProjectHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		force, err := strconv.ParseBool(c.DefaultQuery("force", "false"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid force parameter", "request_id": rid})
+			return
+		}
+
+		deleted, err := projectService.DeleteProject(c.Request.Context(), customerID, name, force)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteProject, name, "", err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to delete project")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Harbor project '%s' deleted by %s (force=%t)", rid, name, c.GetString("sub"), force)
+		c.JSON(http.StatusOK, deleted)
 	}
-	return "false"
 }
 
-func strPtr(s string) *string {
-	return &s
+// respondServiceError traduit les erreurs du service Harbor en réponse HTTP
+func respondServiceError(c *gin.Context, err error, fallback string) {
+	rid := c.GetString("request_id")
+
+	var limitErr *service.StorageLimitError
+	switch {
+	case errors.As(err, &limitErr):
+		c.JSON(http.StatusForbidden, gin.H{"error": "Storage limit exceeds the customer plan", "plan": limitErr.Plan, "max_storage_gb": limitErr.Limit, "requested_storage_gb": limitErr.Requested, "request_id": rid})
+	case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrPlanNotFound):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "request_id": rid})
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found", "request_id": rid})
+	case errors.Is(err, service.ErrProjectNotEmpty):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "request_id": rid})
+	case errors.Is(err, service.ErrProjectAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project already exists", "request_id": rid})
+	case errors.Is(err, service.ErrRepositoryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Repository not found", "request_id": rid})
+	case errors.Is(err, service.ErrArtifactNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Artifact or tag not found", "request_id": rid})
+	case errors.Is(err, service.ErrArtifactImmutable):
+		c.JSON(http.StatusPreconditionFailed, gin.H{"error": "Artifact is protected by an immutability rule", "request_id": rid})
+	case errors.Is(err, service.ErrScanNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scan report not found", "request_id": rid})
+	case errors.Is(err, service.ErrScanRunning):
+		c.JSON(http.StatusConflict, gin.H{"error": "A scan is already running", "request_id": rid})
+	case errors.Is(err, service.ErrImmutableRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Immutability rule not found", "request_id": rid})
+	case errors.Is(err, service.ErrQuotaNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project quota not found", "request_id": rid})
+	case errors.Is(err, service.ErrRobotNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Robot account not found", "request_id": rid})
+	case errors.Is(err, service.ErrRobotAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Robot account already exists", "request_id": rid})
+	case errors.Is(err, service.ErrMemberNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project member not found", "request_id": rid})
+	case errors.Is(err, service.ErrMemberAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Already a member of the project", "request_id": rid})
+	case errors.Is(err, service.ErrReplicationNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Replication rule not found", "request_id": rid})
+	case errors.Is(err, service.ErrNamespaceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Namespace not found", "request_id": rid})
+	case errors.Is(err, service.ErrPullSecretNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project is not linked to this namespace", "request_id": rid})
+	case errors.Is(err, service.ErrPullSecretAlreadyExists):
+		c.JSON(http.StatusConflict, gin.H{"error": "Project already linked to this namespace", "request_id": rid})
+	case errors.Is(err, service.ErrSecretConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": "A secret with the same name, not managed by this project, already exists in the namespace", "request_id": rid})
+	case errors.Is(err, service.ErrHarborAPI):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Harbor error", "request_id": rid})
+	case errors.Is(err, service.ErrKubernetesAPI):
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Kubernetes error", "request_id": rid})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback, "request_id": rid})
+	}
 }
@@ -0,0 +1,93 @@
+package project
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+type historyRecorder interface {
+	RecordHistory(ctx context.Context, customerID string, action db.HarborActionTypeEnum, projectName, resource, createdBy string, opErr error)
+}
+
+// recordHistory trace l'opération dans harbor_history avec le sujet du token comme auteur
+func recordHistory(c *gin.Context, recorder historyRecorder, customerID string, action db.HarborActionTypeEnum, projectName, resource string, err error) {
+	recorder.RecordHistory(c.Request.Context(), customerID, action, projectName, resource, c.GetString("sub"), err)
+}
+
+// ListHistoryHandler godoc
+// @Summary     List the Harbor operations of the customer
+// @Description Returns the audit trail of the Harbor operations of the customer (project, quota, robot, member, policy, pull secret and artifact changes) with their status and author, most recent first
+// @Tags        harbor
+// @Produce     json
+// @Param       project query string false "Filter on a project name"
+// @Param       page query int false "Page number" default(1)
+// @Param       page_size query int false "Page size (max 100)" default(50)
+// @Success     200 {array} service.HistoryEntry "History"
+// @Failure     400 {object} map[string]string "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /harbor/v1/history [get]
+// @Security Bearer
+func ListHistoryHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		listHistory(c, projectService, customerID)
+	}
+}
+
+// ListHistoryAdminHandler godoc
+// @Summary     List the Harbor operations of all customers (admin)
+// @Description Returns the audit trail of the Harbor operations, optionally filtered on a customer and a project, most recent first
+// @Tags        harbor - admin
+// @Produce     json
+// @Param       customer_id query string false "Filter on a customer"
+// @Param       project query string false "Filter on a project name (requires customer_id)"
+// @Param       page query int false "Page number" default(1)
+// @Param       page_size query int false "Page size (max 100)" default(50)
+// @Success     200 {array} service.HistoryEntry "History"
+// @Failure     400 {object} map[string]string "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized"
+// @Failure     403 {object} map[string]string "Admin role required"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /harbor/v1/admin/history [get]
+// @Security Bearer
+func ListHistoryAdminHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		listHistory(c, projectService, c.Query("customer_id"))
+	}
+}
+
+func listHistory(c *gin.Context, projectService *service.ProjectService, customerID string) {
+	rid := c.GetString("request_id")
+
+	page, err1 := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+	pageSize, err2 := strconv.ParseInt(c.DefaultQuery("page_size", strconv.FormatInt(service.DefaultArtifactPageSize, 10)), 10, 64)
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page or page_size", "request_id": rid})
+		return
+	}
+
+	history, err := projectService.ListHistory(c.Request.Context(), service.HistoryQuery{
+		CustomerID:  customerID,
+		ProjectName: c.Query("project"),
+		Page:        page,
+		PageSize:    pageSize,
+	})
+	if err != nil {
+		klog.Errorf("[request_id=%s] Failed to list Harbor history of customer '%s': %v", rid, customerID, err)
+		respondServiceError(c, err, "Failed to list history")
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
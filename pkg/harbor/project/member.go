@@ -0,0 +1,134 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// AddMemberHandler godoc
+// @Summary     Add a member to a project
+// @Description Grants a Harbor user or an OIDC group a role on a Harbor project owned by the customer. Users must have logged in to Harbor once before being added.
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.MemberRequest true "Member (type: user or group; role: project_admin, maintainer, developer or guest)"
+// @Success     201 {object} service.MemberResponse "Member added"
+// @Failure     400 {object} map[string]string "Invalid role or type, or unknown user"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     409 {object} map[string]string "Already a member of the project"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/members [post]
+// @Security Bearer
+func AddMemberHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.MemberRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		m, err := projectService.AddMember(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumAddMember, name, req.Name, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to add member '%s' to Harbor project '%s': %v", rid, req.Name, name, err)
+			respondServiceError(c, err, "Failed to add member")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Member '%s' added to Harbor project '%s' as %s by %s", rid, req.Name, name, req.Role, c.GetString("sub"))
+		c.JSON(http.StatusCreated, m)
+	}
+}
+
+// ListMembersHandler godoc
+// @Summary     List the members of a project
+// @Description Lists the users and groups having a role on a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.MemberResponse "Members"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/members [get]
+// @Security Bearer
+func ListMembersHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		members, err := projectService.ListMembers(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list members of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list members")
+			return
+		}
+
+		c.JSON(http.StatusOK, members)
+	}
+}
+
+// RemoveMemberHandler godoc
+// @Summary     Remove a member from a project
+// @Description Revokes the role of a user or group on a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       id path int true "Member ID"
+// @Success     200 {object} map[string]string "Member removed"
+// @Failure     400 {object} map[string]string "Invalid member ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or member not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/members/{id} [delete]
+// @Security Bearer
+func RemoveMemberHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		memberID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || memberID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid member ID", "request_id": rid})
+			return
+		}
+
+		err = projectService.RemoveMember(c.Request.Context(), customerID, name, memberID)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumRemoveMember, name, c.Param("id"), err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to remove member %d from Harbor project '%s': %v", rid, memberID, name, err)
+			respondServiceError(c, err, "Failed to remove member")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Member %d removed from Harbor project '%s' by %s", rid, memberID, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Member removed", "request_id": rid})
+	}
+}
@@ -0,0 +1,211 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// GetRetentionPolicyHandler godoc
+// @Summary     Get the tag retention policy of a project
+// @Description Returns the tag retention rules of a Harbor project owned by the customer and their schedule. Tags matching no rule are deleted at each run.
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {object} service.RetentionPolicy "Retention policy"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/retention [get]
+// @Security Bearer
+func GetRetentionPolicyHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		policy, err := projectService.GetRetentionPolicy(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get retention policy of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to get retention policy")
+			return
+		}
+
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// SetRetentionPolicyHandler godoc
+// @Summary     Set the tag retention policy of a project
+// @Description Replaces the tag retention rules of a Harbor project owned by the customer. Each rule keeps either the last keep_last pushed tags or the tags pushed within keep_days days, for the tags and repositories matching its patterns ("**" when omitted).
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.RetentionPolicyRequest true "Retention rules"
+// @Success     200 {object} service.RetentionPolicy "Retention policy"
+// @Failure     400 {object} map[string]string "Invalid rules or schedule"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/retention [put]
+// @Security Bearer
+func SetRetentionPolicyHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.RetentionPolicyRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		policy, err := projectService.SetRetentionPolicy(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumUpdateRetention, name, "", err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to set retention policy of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to set retention policy")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Retention policy of Harbor project '%s' updated by %s", rid, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, policy)
+	}
+}
+
+// ListImmutableRulesHandler godoc
+// @Summary     List the tag immutability rules of a project
+// @Description Lists the rules protecting tags of a Harbor project owned by the customer from being overwritten or deleted
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.ImmutableRule "Immutability rules"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/immutability [get]
+// @Security Bearer
+func ListImmutableRulesHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		rules, err := projectService.ListImmutableRules(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list immutability rules of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list immutability rules")
+			return
+		}
+
+		c.JSON(http.StatusOK, rules)
+	}
+}
+
+// CreateImmutableRuleHandler godoc
+// @Summary     Add a tag immutability rule to a project
+// @Description Protects the tags matching tag_pattern, in the repositories matching repository_pattern ("**" when omitted), from being overwritten or deleted
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.ImmutableRuleRequest true "Immutability rule"
+// @Success     201 {object} map[string]string "Immutability rule created"
+// @Failure     400 {object} map[string]string "Invalid patterns or duplicate rule"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/immutability [post]
+// @Security Bearer
+func CreateImmutableRuleHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.ImmutableRuleRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		err := projectService.CreateImmutableRule(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumCreateImmutableRule, name, req.TagPattern, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to create immutability rule on Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to create immutability rule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Immutability rule '%s' added to Harbor project '%s' by %s", rid, req.TagPattern, name, c.GetString("sub"))
+		c.JSON(http.StatusCreated, gin.H{"message": "Immutability rule created", "request_id": rid})
+	}
+}
+
+// DeleteImmutableRuleHandler godoc
+// @Summary     Delete a tag immutability rule
+// @Description Removes an immutability rule from a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       id path int true "Rule ID"
+// @Success     200 {object} map[string]string "Immutability rule deleted"
+// @Failure     400 {object} map[string]string "Invalid rule ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or rule not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/immutability/{id} [delete]
+// @Security Bearer
+func DeleteImmutableRuleHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		ruleID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil || ruleID <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID", "request_id": rid})
+			return
+		}
+
+		err = projectService.DeleteImmutableRule(c.Request.Context(), customerID, name, ruleID)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteImmutableRule, name, c.Param("id"), err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete immutability rule %d of Harbor project '%s': %v", rid, ruleID, name, err)
+			respondServiceError(c, err, "Failed to delete immutability rule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Immutability rule %d of Harbor project '%s' deleted by %s", rid, ruleID, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Immutability rule deleted", "request_id": rid})
+	}
+}
@@ -0,0 +1,164 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// LinkNamespaceHandler godoc
+// @Summary     Link a project to a Kubernetes namespace
+// @Description Creates a pull-only robot on the Harbor project, writes its credentials into a kubernetes.io/dockerconfigjson secret of the namespace and adds it to the imagePullSecrets of the default ServiceAccount. The project and the namespace must both belong to the customer. The secret is refreshed before the robot expires.
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.PullSecretRequest true "Namespace to link"
+// @Success     201 {object} service.PullSecretResponse "Pull secret created"
+// @Failure     400 {object} map[string]string "Invalid request body"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or namespace not found"
+// @Failure     409 {object} map[string]string "Project already linked to this namespace, or a secret of the same name not managed by this project exists"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor or Kubernetes error"
+// @Router      /harbor/v1/project/{name}/pull-secrets [post]
+// @Security Bearer
+func LinkNamespaceHandler(pullSecretService *service.PullSecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.PullSecretRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		secret, err := pullSecretService.LinkNamespace(c.Request.Context(), customerID, name, req.Namespace, c.GetString("sub"))
+		recordHistory(c, pullSecretService, customerID, db.HarborActionTypeEnumLinkNamespace, name, req.Namespace, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to link Harbor project '%s' to namespace '%s': %v", rid, name, req.Namespace, err)
+			respondServiceError(c, err, "Failed to link namespace")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Harbor project '%s' linked to namespace '%s' by %s", rid, name, req.Namespace, c.GetString("sub"))
+		c.JSON(http.StatusCreated, secret)
+	}
+}
+
+// ListPullSecretsHandler godoc
+// @Summary     List the namespaces linked to a project
+// @Description Lists the Kubernetes namespaces receiving an image pull secret for a Harbor project owned by the customer, with the expiry of the current robot
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.PullSecretResponse "Pull secrets"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /harbor/v1/project/{name}/pull-secrets [get]
+// @Security Bearer
+func ListPullSecretsHandler(pullSecretService *service.PullSecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		secrets, err := pullSecretService.ListPullSecrets(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list pull secrets of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list pull secrets")
+			return
+		}
+
+		c.JSON(http.StatusOK, secrets)
+	}
+}
+
+// UnlinkNamespaceHandler godoc
+// @Summary     Unlink a project from a Kubernetes namespace
+// @Description Removes the pull secret from the default ServiceAccount, deletes it from the namespace and deletes its Harbor robot
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       namespace path string true "Namespace name"
+// @Success     200 {object} map[string]string "Namespace unlinked"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not linked to this namespace"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Kubernetes error"
+// @Router      /harbor/v1/project/{name}/pull-secrets/{namespace} [delete]
+// @Security Bearer
+func UnlinkNamespaceHandler(pullSecretService *service.PullSecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		namespace := c.Param("namespace")
+
+		err := pullSecretService.UnlinkNamespace(c.Request.Context(), customerID, name, namespace)
+		recordHistory(c, pullSecretService, customerID, db.HarborActionTypeEnumUnlinkNamespace, name, namespace, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to unlink Harbor project '%s' from namespace '%s': %v", rid, name, namespace, err)
+			respondServiceError(c, err, "Failed to unlink namespace")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Harbor project '%s' unlinked from namespace '%s' by %s", rid, name, namespace, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Namespace unlinked", "request_id": rid})
+	}
+}
+
+// RefreshPullSecretHandler godoc
+// @Summary     Refresh the pull secret of a namespace
+// @Description Replaces the robot behind the pull secret of a linked namespace with a new one and updates the secret, e.g. after a credential leak
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       namespace path string true "Namespace name"
+// @Success     200 {object} service.PullSecretResponse "Pull secret refreshed"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not linked to this namespace"
+// @Failure     409 {object} map[string]string "Secret of the same name not managed by this project"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor or Kubernetes error"
+// @Router      /harbor/v1/project/{name}/pull-secrets/{namespace}/refresh [post]
+// @Security Bearer
+func RefreshPullSecretHandler(pullSecretService *service.PullSecretService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		namespace := c.Param("namespace")
+
+		secret, err := pullSecretService.RefreshPullSecret(c.Request.Context(), customerID, name, namespace)
+		recordHistory(c, pullSecretService, customerID, db.HarborActionTypeEnumRefreshPullSecret, name, namespace, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to refresh pull secret of Harbor project '%s' in namespace '%s': %v", rid, name, namespace, err)
+			respondServiceError(c, err, "Failed to refresh pull secret")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Pull secret of Harbor project '%s' in namespace '%s' refreshed by %s", rid, name, namespace, c.GetString("sub"))
+		c.JSON(http.StatusOK, secret)
+	}
+}
@@ -0,0 +1,125 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// SetProjectQuotaHandler godoc
+// @Summary     Update the storage quota of a project
+// @Description Sets the storage limit (GB) of a Harbor project owned by the customer. The default of the customer plan applies when 0, and the limit cannot exceed the plan maximum.
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.QuotaRequest true "Storage limit in GB"
+// @Success     200 {object} service.ProjectQuota "Updated quota (bytes, -1 when unlimited)"
+// @Failure     400 {object} map[string]string "Invalid request"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]interface{} "Storage limit exceeds the customer plan"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/quota [put]
+// @Security Bearer
+func SetProjectQuotaHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.QuotaRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		quota, err := projectService.SetProjectQuota(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumUpdateQuota, name, "", err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to update quota of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to update project quota")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Quota of Harbor project '%s' updated by %s", rid, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, quota)
+	}
+}
+
+// GetCustomerPlanAdminHandler godoc
+// @Summary     Get the Harbor plan of a customer (admin)
+// @Description Returns the Harbor plan of a customer and its storage limits per project (GB, 0 means unlimited)
+// @Tags        harbor - admin
+// @Produce     json
+// @Param       customer_id path string true "Customer ID"
+// @Success     200 {object} service.CustomerPlanResponse "Customer plan"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]string "Admin role required"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /harbor/v1/admin/plans/{customer_id} [get]
+// @Security Bearer
+func GetCustomerPlanAdminHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID := c.Param("customer_id")
+
+		plan, err := projectService.GetCustomerPlan(c.Request.Context(), customerID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get Harbor plan of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to get customer plan")
+			return
+		}
+
+		c.JSON(http.StatusOK, plan)
+	}
+}
+
+// SetCustomerPlanAdminHandler godoc
+// @Summary     Set the Harbor plan of a customer (admin)
+// @Description Assigns one of the configured Harbor plans to a customer. Existing project quotas are left unchanged.
+// @Tags        harbor - admin
+// @Accept      json
+// @Produce     json
+// @Param       customer_id path string true "Customer ID"
+// @Param       request body service.CustomerPlanRequest true "Plan"
+// @Success     200 {object} service.CustomerPlanResponse "Customer plan"
+// @Failure     400 {object} map[string]string "Invalid request or unknown plan"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     403 {object} map[string]string "Admin role required"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Router      /harbor/v1/admin/plans/{customer_id} [put]
+// @Security Bearer
+func SetCustomerPlanAdminHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID := c.Param("customer_id")
+
+		var req service.CustomerPlanRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		plan, err := projectService.SetCustomerPlan(c.Request.Context(), customerID, req, c.GetString("sub"))
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumUpdatePlan, "", req.Plan, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to set Harbor plan of customer %s: %v", rid, customerID, err)
+			respondServiceError(c, err, "Failed to set customer plan")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Harbor plan of customer %s set to '%s' by %s", rid, customerID, plan.Plan, c.GetString("sub"))
+		c.JSON(http.StatusOK, plan)
+	}
+}
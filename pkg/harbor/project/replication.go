@@ -0,0 +1,250 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// ListUpstreamsHandler godoc
+// @Summary     List the upstream registries
+// @Description Lists the upstream registries (Docker Hub, quay.io...) approved by the administrators, usable as the upstream of a proxy-cache project or as the source of a replication rule
+// @Tags        harbor
+// @Produce     json
+// @Success     200 {array} service.UpstreamResponse "Upstream registries"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/upstreams [get]
+// @Security Bearer
+func ListUpstreamsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		if _, ok := utils.GetCustomerIDOrAbort(c); !ok {
+			return
+		}
+
+		upstreams, err := projectService.ListUpstreams(c.Request.Context())
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list upstream registries: %v", rid, err)
+			respondServiceError(c, err, "Failed to list upstream registries")
+			return
+		}
+
+		c.JSON(http.StatusOK, upstreams)
+	}
+}
+
+// CreateReplicationHandler godoc
+// @Summary     Add a pull-replication rule to a project
+// @Description Replicates the repositories matching repository (and the tags matching tag, all when omitted) from an approved upstream registry into a Harbor project owned by the customer. "library/nginx" is replicated as "<project>/nginx". The rule runs on the cron schedule (Harbor cron, with seconds) or manually when omitted; scheduled runs must be at least replication_min_interval_minutes apart (60 by default).
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.ReplicationRequest true "Replication rule"
+// @Success     201 {object} service.ReplicationResponse "Replication rule created"
+// @Failure     400 {object} map[string]string "Upstream not allowed, proxy-cache project, invalid filters or cron too frequent"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/replications [post]
+// @Security Bearer
+func CreateReplicationHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.ReplicationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		rule, err := projectService.CreateReplication(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumCreateReplication, name, req.Upstream+"/"+req.Repository, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to create replication rule on Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to create replication rule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Replication of %s/%s into Harbor project '%s' created by %s", rid, req.Upstream, req.Repository, name, c.GetString("sub"))
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// ListReplicationsHandler godoc
+// @Summary     List the replication rules of a project
+// @Description Lists the pull-replication rules targeting a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.ReplicationResponse "Replication rules"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/replications [get]
+// @Security Bearer
+func ListReplicationsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		rules, err := projectService.ListReplications(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list replication rules of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list replication rules")
+			return
+		}
+
+		c.JSON(http.StatusOK, rules)
+	}
+}
+
+// DeleteReplicationHandler godoc
+// @Summary     Delete a replication rule
+// @Description Removes a pull-replication rule from a Harbor project owned by the customer; already replicated artifacts are kept
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       id path int true "Rule ID"
+// @Success     200 {object} map[string]string "Replication rule deleted"
+// @Failure     400 {object} map[string]string "Invalid rule ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or rule not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/replications/{id} [delete]
+// @Security Bearer
+func DeleteReplicationHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		policyID, ok := parseReplicationID(c)
+		if !ok {
+			return
+		}
+
+		err := projectService.DeleteReplication(c.Request.Context(), customerID, name, policyID)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteReplication, name, c.Param("id"), err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete replication rule %d of Harbor project '%s': %v", rid, policyID, name, err)
+			respondServiceError(c, err, "Failed to delete replication rule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Replication rule %d of Harbor project '%s' deleted by %s", rid, policyID, name, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Replication rule deleted", "request_id": rid})
+	}
+}
+
+// RunReplicationHandler godoc
+// @Summary     Run a replication rule
+// @Description Starts a pull-replication rule of a Harbor project owned by the customer immediately. The replication runs asynchronously in Harbor.
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       id path int true "Rule ID"
+// @Success     202 {object} map[string]interface{} "Replication started"
+// @Failure     400 {object} map[string]string "Invalid rule ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or rule not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/replications/{id}/run [post]
+// @Security Bearer
+func RunReplicationHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		policyID, ok := parseReplicationID(c)
+		if !ok {
+			return
+		}
+
+		executionID, err := projectService.RunReplication(c.Request.Context(), customerID, name, policyID)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumRunReplication, name, c.Param("id"), err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to run replication rule %d of Harbor project '%s': %v", rid, policyID, name, err)
+			respondServiceError(c, err, "Failed to run replication rule")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Replication rule %d of Harbor project '%s' started by %s", rid, policyID, name, c.GetString("sub"))
+		c.JSON(http.StatusAccepted, gin.H{"message": "Replication started", "execution_id": executionID, "request_id": rid})
+	}
+}
+
+// ListReplicationExecutionsHandler godoc
+// @Summary     List the executions of a replication rule
+// @Description Returns the last executions of a pull-replication rule of a Harbor project owned by the customer, most recent first
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       id path int true "Rule ID"
+// @Success     200 {array} service.ReplicationExecution "Executions"
+// @Failure     400 {object} map[string]string "Invalid rule ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or rule not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/replications/{id}/executions [get]
+// @Security Bearer
+func ListReplicationExecutionsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		policyID, ok := parseReplicationID(c)
+		if !ok {
+			return
+		}
+
+		executions, err := projectService.ListReplicationExecutions(c.Request.Context(), customerID, name, policyID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list executions of replication rule %d of Harbor project '%s': %v", rid, policyID, name, err)
+			respondServiceError(c, err, "Failed to list replication executions")
+			return
+		}
+
+		c.JSON(http.StatusOK, executions)
+	}
+}
+
+// parseReplicationID lit le paramètre id, répond 400 s'il est invalide
+func parseReplicationID(c *gin.Context) (int64, bool) {
+	policyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || policyID <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID", "request_id": c.GetString("request_id")})
+		return 0, false
+	}
+	return policyID, true
+}
@@ -0,0 +1,193 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// ListRepositoriesHandler godoc
+// @Summary     List repositories of a project
+// @Description Lists the repositories of a Harbor project owned by the customer, with their artifact and pull counts
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.RepositoryResponse "Repositories"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/repositories [get]
+// @Security Bearer
+func ListRepositoriesHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		repos, err := projectService.ListRepositories(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list repositories of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list repositories")
+			return
+		}
+
+		c.JSON(http.StatusOK, repos)
+	}
+}
+
+// ListArtifactsHandler godoc
+// @Summary     List artifacts of a repository
+// @Description Lists a page of the artifacts of a repository with their tags, digest, size (bytes) and push/pull times. Repository names may contain "/".
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Param       page query int false "Page number" default(1)
+// @Param       page_size query int false "Page size (max 100)" default(50)
+// @Success     200 {array} service.ArtifactResponse "Artifacts"
+// @Failure     400 {object} map[string]string "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or repository not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts [get]
+// @Security Bearer
+func ListArtifactsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+		page, err1 := strconv.ParseInt(c.DefaultQuery("page", "1"), 10, 64)
+		pageSize, err2 := strconv.ParseInt(c.DefaultQuery("page_size", strconv.FormatInt(service.DefaultArtifactPageSize, 10)), 10, 64)
+		if err1 != nil || err2 != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid page or page_size", "request_id": rid})
+			return
+		}
+
+		artifacts, err := projectService.ListArtifacts(c.Request.Context(), customerID, name, repo, page, pageSize)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list artifacts of %s/%s: %v", rid, name, repo, err)
+			respondServiceError(c, err, "Failed to list artifacts")
+			return
+		}
+
+		c.JSON(http.StatusOK, artifacts)
+	}
+}
+
+// DeleteArtifactHandler godoc
+// @Summary     Delete an artifact
+// @Description Deletes an artifact and all its tags. The reference is a digest (sha256:...) or a tag of the artifact.
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       reference path string true "Artifact digest or tag"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Success     200 {object} map[string]string "Artifact deleted"
+// @Failure     400 {object} map[string]string "Missing repository"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or artifact not found"
+// @Failure     412 {object} map[string]string "Artifact is immutable"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts/{reference} [delete]
+// @Security Bearer
+func DeleteArtifactHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		reference := c.Param("reference")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		err := projectService.DeleteArtifact(c.Request.Context(), customerID, name, repo, reference)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteArtifact, name, repo+"@"+reference, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete artifact %s/%s@%s: %v", rid, name, repo, reference, err)
+			respondServiceError(c, err, "Failed to delete artifact")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Artifact %s/%s@%s deleted by %s", rid, name, repo, reference, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Artifact deleted", "request_id": rid})
+	}
+}
+
+// DeleteTagHandler godoc
+// @Summary     Delete a tag
+// @Description Removes a tag from a repository; the artifact stays available by digest
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       tag path string true "Tag name"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Success     200 {object} map[string]string "Tag deleted"
+// @Failure     400 {object} map[string]string "Missing repository"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or tag not found"
+// @Failure     412 {object} map[string]string "Tag is immutable"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/tags/{tag} [delete]
+// @Security Bearer
+func DeleteTagHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		tag := c.Param("tag")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		err := projectService.DeleteTag(c.Request.Context(), customerID, name, repo, tag)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteTag, name, repo+":"+tag, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to delete tag %s/%s:%s: %v", rid, name, repo, tag, err)
+			respondServiceError(c, err, "Failed to delete tag")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Tag %s/%s:%s deleted by %s", rid, name, repo, tag, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{"message": "Tag deleted", "request_id": rid})
+	}
+}
+
+// repositoryQueryOrAbort les noms de dépôt pouvant contenir des "/", ils sont passés en paramètre de requête
+func repositoryQueryOrAbort(c *gin.Context) (string, bool) {
+	repo := c.Query("repository")
+	if repo == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing repository parameter", "request_id": c.GetString("request_id")})
+		return "", false
+	}
+	return repo, true
+}
@@ -0,0 +1,186 @@
+package project
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// CreateRobotHandler godoc
+// @Summary     Create a robot account
+// @Description Creates a robot account limited to a Harbor project owned by the customer, with pull or push (push includes pull) permissions on its repositories. The secret is only returned once.
+// @Tags        harbor
+// @Accept      json
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       request body service.CreateRobotRequest true "Robot account"
+// @Success     201 {object} service.RobotCreatedResponse "Robot account created"
+// @Failure     400 {object} map[string]string "Invalid request"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     409 {object} map[string]string "Robot account already exists"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/create/robot/{name} [post]
+// @Security Bearer
+func CreateRobotHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		var req service.CreateRobotRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			klog.Warningf("[request_id=%s] Invalid request body: %v", rid, err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "request_id": rid})
+			return
+		}
+
+		created, err := projectService.CreateRobot(c.Request.Context(), customerID, name, req)
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumCreateRobot, name, req.Name, err)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to create robot on Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to create robot account")
+			return
+		}
+
+		klog.Infof("[request_id=%s] Robot '%s' created on Harbor project '%s' by %s", rid, created.Name, name, c.GetString("sub"))
+		c.JSON(http.StatusCreated, created)
+	}
+}
+
+// ListRobotsHandler godoc
+// @Summary     List robot accounts of a project
+// @Description Lists the robot accounts of a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {array} service.RobotResponse "Robot accounts"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/robot/{name} [get]
+// @Security Bearer
+func ListRobotsHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		robots, err := projectService.ListRobots(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to list robots of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to list robot accounts")
+			return
+		}
+
+		c.JSON(http.StatusOK, robots)
+	}
+}
+
+// GetRobotHandler godoc
+// @Summary     Get a robot account
+// @Description Returns a robot account of a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       id path int true "Robot ID"
+// @Success     200 {object} service.RobotResponse "Robot account"
+// @Failure     400 {object} map[string]string "Invalid robot ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Robot account not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/robot/{id} [get]
+// @Security Bearer
+func GetRobotHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		robotID, ok := parseRobotID(c)
+		if !ok {
+			return
+		}
+
+		robot, err := projectService.GetRobot(c.Request.Context(), customerID, robotID)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get Harbor robot %d: %v", rid, robotID, err)
+			respondServiceError(c, err, "Failed to get robot account")
+			return
+		}
+
+		c.JSON(http.StatusOK, robot)
+	}
+}
+
+// DeleteRobotHandler godoc
+// @Summary     Delete a robot account
+// @Description Deletes a robot account of a Harbor project owned by the customer
+// @Tags        harbor
+// @Produce     json
+// @Param       id path int true "Robot ID"
+// @Success     200 {object} map[string]interface{} "Robot account deleted"
+// @Failure     400 {object} map[string]string "Invalid robot ID"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Robot account not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/robot/{id} [delete]
+// @Security Bearer
+func DeleteRobotHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		robotID, ok := parseRobotID(c)
+		if !ok {
+			return
+		}
+
+		robot, err := projectService.DeleteRobot(c.Request.Context(), customerID, robotID)
+		if err != nil {
+			recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteRobot, "", c.Param("id"), err)
+			klog.Errorf("[request_id=%s] Failed to delete Harbor robot %d: %v", rid, robotID, err)
+			respondServiceError(c, err, "Failed to delete robot account")
+			return
+		}
+
+		recordHistory(c, projectService, customerID, db.HarborActionTypeEnumDeleteRobot, robot.Project, robot.Name, nil)
+		klog.Infof("[request_id=%s] Robot '%s' deleted by %s", rid, robot.Name, c.GetString("sub"))
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "Robot account deleted",
+			"id":         robot.ID,
+			"name":       robot.Name,
+			"project":    robot.Project,
+			"request_id": rid,
+		})
+	}
+}
+
+// parseRobotID lit le paramètre id, répond 400 s'il est invalide
+func parseRobotID(c *gin.Context) (int64, bool) {
+	robotID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || robotID <= 0 {
+		rid := c.GetString("request_id")
+		klog.Warningf("[request_id=%s] Invalid robot ID: %s", rid, c.Param("id"))
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid robot ID", "request_id": rid})
+		return 0, false
+	}
+	return robotID, true
+}
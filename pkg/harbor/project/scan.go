@@ -0,0 +1,215 @@
+package project
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/Gskill75/api2/pkg/harbor/service"
+	"github.com/Gskill75/api2/pkg/utils"
+	"k8s.io/klog/v2"
+)
+
+// ScanArtifactHandler godoc
+// @Summary     Scan an artifact
+// @Description Triggers a vulnerability scan or an SBOM generation on an artifact. The scan runs asynchronously in Harbor.
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       reference path string true "Artifact digest or tag"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Param       type query string false "Scan type" Enums(vulnerability, sbom) default(vulnerability)
+// @Success     202 {object} map[string]string "Scan requested"
+// @Failure     400 {object} map[string]string "Invalid parameters"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project or artifact not found"
+// @Failure     409 {object} map[string]string "A scan is already running"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts/{reference}/scan [post]
+// @Security Bearer
+func ScanArtifactHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		reference := c.Param("reference")
+		scanType := c.DefaultQuery("type", service.ScanTypeVulnerability)
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		if err := projectService.ScanArtifact(c.Request.Context(), customerID, name, repo, reference, scanType); err != nil {
+			klog.Errorf("[request_id=%s] Failed to scan artifact %s/%s@%s: %v", rid, name, repo, reference, err)
+			respondServiceError(c, err, "Failed to scan artifact")
+			return
+		}
+
+		klog.Infof("[request_id=%s] %s scan of %s/%s@%s requested by %s", rid, scanType, name, repo, reference, c.GetString("sub"))
+		c.JSON(http.StatusAccepted, gin.H{"message": "Scan requested", "type": scanType, "request_id": rid})
+	}
+}
+
+// GetVulnerabilitySummaryHandler godoc
+// @Summary     Get the vulnerability summary of an artifact
+// @Description Returns the status of the last vulnerability scan of an artifact and its vulnerability counts per severity
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       reference path string true "Artifact digest or tag"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Success     200 {object} service.VulnerabilitySummary "Vulnerability summary"
+// @Failure     400 {object} map[string]string "Missing repository"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project, artifact or scan report not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts/{reference}/vulnerabilities [get]
+// @Security Bearer
+func GetVulnerabilitySummaryHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		reference := c.Param("reference")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		summary, err := projectService.GetVulnerabilitySummary(c.Request.Context(), customerID, name, repo, reference)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get vulnerability summary of %s/%s@%s: %v", rid, name, repo, reference, err)
+			respondServiceError(c, err, "Failed to get vulnerability summary")
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
+	}
+}
+
+// GetVulnerabilityReportHandler godoc
+// @Summary     Get the vulnerability report of an artifact
+// @Description Returns the vulnerabilities found by the last scan of an artifact, with affected package and fix version
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       reference path string true "Artifact digest or tag"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Success     200 {object} service.VulnerabilityReport "Vulnerability report"
+// @Failure     400 {object} map[string]string "Missing repository"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project, artifact or scan report not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts/{reference}/vulnerabilities/report [get]
+// @Security Bearer
+func GetVulnerabilityReportHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		reference := c.Param("reference")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		report, err := projectService.GetVulnerabilityReport(c.Request.Context(), customerID, name, repo, reference)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get vulnerability report of %s/%s@%s: %v", rid, name, repo, reference, err)
+			respondServiceError(c, err, "Failed to get vulnerability report")
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+	}
+}
+
+// GetSBOMHandler godoc
+// @Summary     Download the SBOM of an artifact
+// @Description Downloads the SBOM generated by Harbor for an artifact (SPDX JSON)
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Param       reference path string true "Artifact digest or tag"
+// @Param       repository query string true "Repository name, without the project prefix"
+// @Success     200 {file} file "SBOM document"
+// @Failure     400 {object} map[string]string "Missing repository"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project, artifact or SBOM not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/artifacts/{reference}/sbom [get]
+// @Security Bearer
+func GetSBOMHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+		reference := c.Param("reference")
+
+		repo, ok := repositoryQueryOrAbort(c)
+		if !ok {
+			return
+		}
+
+		sbom, err := projectService.GetSBOM(c.Request.Context(), customerID, name, repo, reference)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get SBOM of %s/%s@%s: %v", rid, name, repo, reference, err)
+			respondServiceError(c, err, "Failed to get SBOM")
+			return
+		}
+
+		c.Header("Content-Disposition", `attachment; filename="sbom.spdx.json"`)
+		c.Data(http.StatusOK, "application/json", []byte(sbom))
+	}
+}
+
+// GetProjectVulnerabilitiesHandler godoc
+// @Summary     Get the vulnerability summary of a project
+// @Description Aggregates the critical and high vulnerabilities found by the last scans of all artifacts of a project, and lists the affected artifacts
+// @Tags        harbor
+// @Produce     json
+// @Param       name path string true "Project name"
+// @Success     200 {object} service.ProjectVulnerabilitySummary "Project vulnerability summary"
+// @Failure     401 {object} map[string]string "Unauthorized or missing customer_id"
+// @Failure     404 {object} map[string]string "Project not found"
+// @Failure     500 {object} map[string]string "Internal server error"
+// @Failure     502 {object} map[string]string "Harbor error"
+// @Router      /harbor/v1/project/{name}/vulnerabilities [get]
+// @Security Bearer
+func GetProjectVulnerabilitiesHandler(projectService *service.ProjectService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rid := c.GetString("request_id")
+		customerID, ok := utils.GetCustomerIDOrAbort(c)
+		if !ok {
+			return
+		}
+		name := c.Param("name")
+
+		summary, err := projectService.GetProjectVulnerabilitySummary(c.Request.Context(), customerID, name)
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to get vulnerability summary of Harbor project '%s': %v", rid, name, err)
+			respondServiceError(c, err, "Failed to get project vulnerabilities")
+			return
+		}
+
+		c.JSON(http.StatusOK, summary)
+	}
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"k8s.io/klog/v2"
+)
+
+// HistoryActor auteur des opérations lancées par les tâches de fond
+const HistoryActor = "system"
+
+type HistoryEntry struct {
+	ID           int32     `json:"id"`
+	CustomerID   string    `json:"customer_id"`
+	Action       string    `json:"action"`
+	Status       string    `json:"status"`
+	Project      string    `json:"project,omitempty"`
+	Resource     string    `json:"resource,omitempty"`
+	ErrorMessage string    `json:"error_message,omitempty"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// HistoryQuery filtre de l'historique ; sans CustomerID (admin uniquement) tous les clients sont renvoyés
+type HistoryQuery struct {
+	CustomerID  string
+	ProjectName string
+	Page        int64
+	PageSize    int64
+}
+
+// RecordHistory trace une opération Harbor dans harbor_history ; un échec d'écriture est seulement journalisé
+func (s *ProjectService) RecordHistory(ctx context.Context, customerID string, action db.HarborActionTypeEnum, projectName, resource, createdBy string, opErr error) {
+	status := db.HarborStatusEnumSuccess
+	errorMessage := ""
+	if opErr != nil {
+		status = db.HarborStatusEnumError
+		errorMessage = historyErrorMessage(opErr)
+	}
+
+	_, err := s.Queries.CreateHarborHistory(ctx, db.CreateHarborHistoryParams{
+		CustomerID:   customerID,
+		ActionType:   action,
+		Status:       status,
+		ProjectName:  projectName,
+		Resource:     pgtype.Text{String: resource, Valid: resource != ""},
+		ErrorMessage: pgtype.Text{String: errorMessage, Valid: errorMessage != ""},
+		CreatedBy:    createdBy,
+	})
+	if err != nil {
+		klog.Errorf("Failed to record Harbor history (customer=%s, action=%s, project=%s): %v", customerID, action, projectName, err)
+	}
+}
+
+// historyErrors erreurs dont le message peut figurer tel quel dans l'historique
+var historyErrors = []error{
+	ErrProjectNotFound,
+	ErrProjectAlreadyExists,
+	ErrPlanNotFound,
+	ErrQuotaNotFound,
+	ErrRepositoryNotFound,
+	ErrArtifactNotFound,
+	ErrArtifactImmutable,
+	ErrScanNotFound,
+	ErrScanRunning,
+	ErrImmutableRuleNotFound,
+	ErrRobotNotFound,
+	ErrRobotAlreadyExists,
+	ErrMemberNotFound,
+	ErrMemberAlreadyExists,
+	ErrReplicationNotFound,
+	ErrNamespaceNotFound,
+	ErrPullSecretNotFound,
+	ErrPullSecretAlreadyExists,
+	ErrSecretConflict,
+}
+
+// historyErrorMessage message enregistré dans l'historique, consultable par le client : les erreurs de
+// base de données et les réponses de Harbor ou de Kubernetes ne sont pas exposées
+func historyErrorMessage(err error) string {
+	var limitErr *StorageLimitError
+	switch {
+	case errors.As(err, &limitErr), errors.Is(err, ErrInvalidRequest), errors.Is(err, ErrProjectNotEmpty):
+		// Messages de validation, déjà renvoyés au client par l'API
+		return strings.TrimSpace(err.Error())
+	case errors.Is(err, ErrHarborAPI):
+		return "Harbor error"
+	case errors.Is(err, ErrKubernetesAPI):
+		return "Kubernetes error"
+	}
+	for _, known := range historyErrors {
+		if errors.Is(err, known) {
+			return known.Error()
+		}
+	}
+	return "internal error"
+}
+
+// ListHistory renvoie l'historique des opérations Harbor, les plus récentes en premier
+func (s *ProjectService) ListHistory(ctx context.Context, q HistoryQuery) ([]HistoryEntry, error) {
+	if err := validatePage(q.Page, q.PageSize); err != nil {
+		return nil, err
+	}
+	if q.CustomerID == "" && q.ProjectName != "" {
+		return nil, fmt.Errorf("%w: customer_id is required to filter by project", ErrInvalidRequest)
+	}
+	limit := int32(q.PageSize)
+	offset := int32((q.Page - 1) * q.PageSize)
+
+	var (
+		rows []db.HarborHistory
+		err  error
+	)
+	switch {
+	case q.CustomerID == "":
+		rows, err = s.Queries.ListHarborHistory(ctx, db.ListHarborHistoryParams{
+			Limit:  limit,
+			Offset: offset,
+		})
+	case q.ProjectName == "":
+		rows, err = s.Queries.ListHarborHistoryByCustomer(ctx, db.ListHarborHistoryByCustomerParams{
+			CustomerID: q.CustomerID,
+			Limit:      limit,
+			Offset:     offset,
+		})
+	default:
+		rows, err = s.Queries.ListHarborHistoryByProject(ctx, db.ListHarborHistoryByProjectParams{
+			CustomerID:  q.CustomerID,
+			ProjectName: q.ProjectName,
+			Limit:       limit,
+			Offset:      offset,
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	entries := make([]HistoryEntry, 0, len(rows))
+	for _, h := range rows {
+		entries = append(entries, HistoryEntry{
+			ID:           h.ID,
+			CustomerID:   h.CustomerID,
+			Action:       string(h.ActionType),
+			Status:       string(h.Status),
+			Project:      h.ProjectName,
+			Resource:     h.Resource.String,
+			ErrorMessage: h.ErrorMessage.String,
+			CreatedBy:    h.CreatedBy,
+			CreatedAt:    h.CreatedAt.Time,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/member"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	MemberTypeUser  = "user"
+	MemberTypeGroup = "group"
+
+	MemberRoleProjectAdmin = "project_admin"
+	MemberRoleMaintainer   = "maintainer"
+	MemberRoleDeveloper    = "developer"
+	MemberRoleGuest        = "guest"
+
+	// oidcGroupType type de groupe Harbor pour les groupes issus du fournisseur OIDC
+	oidcGroupType  = int64(3)
+	memberPageSize = int64(100)
+)
+
+var (
+	ErrMemberNotFound      = errors.New("project member not found")
+	ErrMemberAlreadyExists = errors.New("project member already exists")
+
+	// memberRoles identifiants des rôles de projet Harbor
+	memberRoles = map[string]int64{
+		MemberRoleProjectAdmin: 1,
+		MemberRoleDeveloper:    2,
+		MemberRoleGuest:        3,
+		MemberRoleMaintainer:   4,
+	}
+)
+
+type MemberRequest struct {
+	// Nom d'utilisateur Harbor ou nom du groupe OIDC
+	Name string `json:"name" binding:"required"`
+	// user (par défaut) ou group
+	Type string `json:"type"`
+	// project_admin, maintainer, developer ou guest
+	Role string `json:"role" binding:"required"`
+}
+
+type MemberResponse struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	Role string `json:"role"`
+}
+
+// AddMember ajoute un utilisateur ou un groupe OIDC au projet du client avec le rôle demandé
+func (s *ProjectService) AddMember(ctx context.Context, customerID, projectName string, req MemberRequest) (*MemberResponse, error) {
+	if req.Type == "" {
+		req.Type = MemberTypeUser
+	}
+	roleID, ok := memberRoles[req.Role]
+	if !ok {
+		return nil, fmt.Errorf("%w: role must be project_admin, maintainer, developer or guest", ErrInvalidRequest)
+	}
+
+	pm := &models.ProjectMember{RoleID: roleID}
+	switch req.Type {
+	case MemberTypeUser:
+		pm.MemberUser = &models.UserEntity{Username: req.Name}
+	case MemberTypeGroup:
+		pm.MemberGroup = &models.UserGroup{GroupName: req.Name, GroupType: oidcGroupType}
+	default:
+		return nil, fmt.Errorf("%w: type must be user or group", ErrInvalidRequest)
+	}
+
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	isName := true
+	created, err := s.Client.ClientSet().V2().Member.CreateProjectMember(ctx, member.NewCreateProjectMemberParams().
+		WithProjectNameOrID(projectName).
+		WithXIsResourceName(&isName).
+		WithProjectMember(pm))
+	if err != nil {
+		switch {
+		case harborclient.IsConflict(err):
+			return nil, ErrMemberAlreadyExists
+		case harborclient.IsNotFound(err), harborclient.IsBadRequest(err):
+			// Un utilisateur OIDC n'existe dans Harbor qu'après sa première connexion
+			return nil, fmt.Errorf("%w: %s '%s' not found in Harbor, users must have logged in to Harbor once", ErrInvalidRequest, req.Type, req.Name)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	// Harbor ne renvoie que l'URL du membre créé
+	id, _ := strconv.ParseInt(path.Base(created.Location), 10, 64)
+
+	klog.Infof("%s '%s' added to Harbor project '%s' as %s for customer %s", req.Type, req.Name, projectName, req.Role, customerID)
+	return &MemberResponse{
+		ID:   id,
+		Name: req.Name,
+		Type: req.Type,
+		Role: req.Role,
+	}, nil
+}
+
+// ListMembers renvoie les membres du projet du client
+func (s *ProjectService) ListMembers(ctx context.Context, customerID, projectName string) ([]MemberResponse, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	isName := true
+	members := []MemberResponse{}
+	for page := int64(1); ; page++ {
+		pageSize := memberPageSize
+		resp, err := s.Client.ClientSet().V2().Member.ListProjectMembers(ctx, member.NewListProjectMembersParams().
+			WithProjectNameOrID(projectName).
+			WithXIsResourceName(&isName).
+			WithPage(&page).
+			WithPageSize(&pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+		for _, m := range resp.Payload {
+			if m != nil {
+				members = append(members, toMemberResponse(m))
+			}
+		}
+		if int64(len(resp.Payload)) < pageSize {
+			break
+		}
+	}
+	return members, nil
+}
+
+// RemoveMember retire un membre du projet du client
+func (s *ProjectService) RemoveMember(ctx context.Context, customerID, projectName string, memberID int64) error {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+
+	isName := true
+	_, err := s.Client.ClientSet().V2().Member.DeleteProjectMember(ctx, member.NewDeleteProjectMemberParams().
+		WithProjectNameOrID(projectName).
+		WithXIsResourceName(&isName).
+		WithMid(memberID))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return ErrMemberNotFound
+		}
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	klog.Infof("Member %d removed from Harbor project '%s' for customer %s", memberID, projectName, customerID)
+	return nil
+}
+
+func toMemberResponse(m *models.ProjectMemberEntity) MemberResponse {
+	memberType := MemberTypeUser
+	if m.EntityType == "g" {
+		memberType = MemberTypeGroup
+	}
+	role := m.RoleName
+	for name, id := range memberRoles {
+		if id == m.RoleID {
+			role = name
+			break
+		}
+	}
+	return MemberResponse{
+		ID:   m.ID,
+		Name: m.EntityName,
+		Type: memberType,
+		Role: role,
+	}
+}
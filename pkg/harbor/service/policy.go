@@ -0,0 +1,417 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/immutable"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/retention"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/Gskill75/api2/pkg/config"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// Templates de règles Harbor
+	retentionTemplateKeepLast = "latestPushedK"
+	retentionTemplateKeepDays = "nDaysSinceLastPush"
+	immutableTemplate         = "immutable_template"
+
+	// Harbor limite une politique de rétention à 15 règles
+	maxRetentionRules = 15
+	immuRulePageSize  = int64(100)
+	defaultPattern    = "**"
+)
+
+var ErrImmutableRuleNotFound = errors.New("immutability rule not found")
+
+// RetentionRule conserve les keep_last derniers tags poussés, ou ceux poussés depuis moins de keep_days jours.
+// Les motifs suivent la syntaxe doublestar de Harbor ("**" par défaut)
+type RetentionRule struct {
+	KeepLast          int64  `json:"keep_last,omitempty"`
+	KeepDays          int64  `json:"keep_days,omitempty"`
+	TagPattern        string `json:"tag_pattern,omitempty"`
+	RepositoryPattern string `json:"repository_pattern,omitempty"`
+	// Template Harbor d'une règle définie hors de l'API (lecture seule)
+	Template string `json:"template,omitempty"`
+}
+
+// RetentionPolicy les tags ne correspondant à aucune règle sont supprimés à chaque exécution
+type RetentionPolicy struct {
+	ID       int64           `json:"id,omitempty"`
+	Schedule string          `json:"schedule"`
+	Rules    []RetentionRule `json:"rules"`
+}
+
+type RetentionPolicyRequest struct {
+	// Cron Harbor avec secondes, la planification par défaut si vide
+	Schedule string          `json:"schedule"`
+	Rules    []RetentionRule `json:"rules" binding:"required"`
+}
+
+type ImmutableRule struct {
+	ID                int64  `json:"id"`
+	TagPattern        string `json:"tag_pattern"`
+	RepositoryPattern string `json:"repository_pattern"`
+	Disabled          bool   `json:"disabled"`
+}
+
+type ImmutableRuleRequest struct {
+	TagPattern        string `json:"tag_pattern" binding:"required"`
+	RepositoryPattern string `json:"repository_pattern"`
+}
+
+// GetRetentionPolicy renvoie la politique de rétention d'un projet du client, vide s'il n'en a pas
+func (s *ProjectService) GetRetentionPolicy(ctx context.Context, customerID, projectName string) (*RetentionPolicy, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+	prj, err := s.harborProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	id, ok := retentionID(prj)
+	if !ok {
+		return &RetentionPolicy{Rules: []RetentionRule{}}, nil
+	}
+	resp, err := s.Client.ClientSet().V2().Retention.GetRetention(ctx, retention.NewGetRetentionParams().WithID(id))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return &RetentionPolicy{Rules: []RetentionRule{}}, nil
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	return toRetentionPolicy(resp.Payload), nil
+}
+
+// SetRetentionPolicy remplace la politique de rétention d'un projet du client
+func (s *ProjectService) SetRetentionPolicy(ctx context.Context, customerID, projectName string, req RetentionPolicyRequest) (*RetentionPolicy, error) {
+	if err := validateRetentionRules(req.Rules); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+	prj, err := s.harborProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule := req.Schedule
+	if schedule == "" {
+		schedule = s.cfg.Harbor.DefaultRetention.Schedule
+	}
+	if err := s.putRetentionPolicy(ctx, prj, schedule, req.Rules); err != nil {
+		return nil, err
+	}
+
+	klog.Infof("Harbor retention policy of project '%s' updated for customer %s (%d rules)", projectName, customerID, len(req.Rules))
+	return s.GetRetentionPolicy(ctx, customerID, projectName)
+}
+
+// ListImmutableRules renvoie les règles d'immutabilité d'un projet du client
+func (s *ProjectService) ListImmutableRules(ctx context.Context, customerID, projectName string) ([]ImmutableRule, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+	return s.projectImmutableRules(ctx, projectName)
+}
+
+// projectImmutableRules renvoie toutes les règles d'immutabilité du projet Harbor
+func (s *ProjectService) projectImmutableRules(ctx context.Context, projectName string) ([]ImmutableRule, error) {
+	isName := true
+	rules := []ImmutableRule{}
+	for page := int64(1); ; page++ {
+		pageSize := immuRulePageSize
+		resp, err := s.Client.ClientSet().V2().Immutable.ListImmuRules(ctx, immutable.NewListImmuRulesParams().
+			WithXIsResourceName(&isName).
+			WithProjectNameOrID(projectName).
+			WithPage(&page).
+			WithPageSize(&pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+		for _, r := range resp.Payload {
+			if r != nil {
+				rules = append(rules, toImmutableRule(r))
+			}
+		}
+		if int64(len(resp.Payload)) < pageSize {
+			break
+		}
+	}
+	return rules, nil
+}
+
+// CreateImmutableRule protège les tags correspondant au motif contre l'écrasement et la suppression
+func (s *ProjectService) CreateImmutableRule(ctx context.Context, customerID, projectName string, req ImmutableRuleRequest) error {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+	if err := s.createImmutableRule(ctx, projectName, req.TagPattern, req.RepositoryPattern); err != nil {
+		return err
+	}
+
+	klog.Infof("Harbor immutability rule '%s' created on project '%s' for customer %s", req.TagPattern, projectName, customerID)
+	return nil
+}
+
+// DeleteImmutableRule supprime une règle d'immutabilité d'un projet du client
+func (s *ProjectService) DeleteImmutableRule(ctx context.Context, customerID, projectName string, ruleID int64) error {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+
+	isName := true
+	_, err := s.Client.ClientSet().V2().Immutable.DeleteImmuRule(ctx, immutable.NewDeleteImmuRuleParams().
+		WithXIsResourceName(&isName).
+		WithProjectNameOrID(projectName).
+		WithImmutableRuleID(ruleID))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return ErrImmutableRuleNotFound
+		}
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	klog.Infof("Harbor immutability rule %d deleted from project '%s' for customer %s", ruleID, projectName, customerID)
+	return nil
+}
+
+// deleteProjectImmutableRules supprime les règles d'immutabilité du projet : Harbor refuse de
+// supprimer un dépôt contenant des tags immuables
+func (s *ProjectService) deleteProjectImmutableRules(ctx context.Context, projectName string) error {
+	rules, err := s.projectImmutableRules(ctx, projectName)
+	if err != nil {
+		return err
+	}
+
+	isName := true
+	for _, rule := range rules {
+		_, err := s.Client.ClientSet().V2().Immutable.DeleteImmuRule(ctx, immutable.NewDeleteImmuRuleParams().
+			WithXIsResourceName(&isName).
+			WithProjectNameOrID(projectName).
+			WithImmutableRuleID(rule.ID))
+		if err != nil && !harborclient.IsNotFound(err) {
+			return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+	}
+	return nil
+}
+
+// applyDefaultPolicies applique les politiques par défaut de la configuration à un nouveau projet ;
+// un échec n'annule pas la création, le client pouvant les définir ensuite
+func (s *ProjectService) applyDefaultPolicies(ctx context.Context, projectName string, proxyCache bool) {
+	defaults := s.cfg.Harbor.DefaultRetention
+	if len(defaults.Rules) > 0 {
+		prj, err := s.harborProject(ctx, projectName)
+		if err == nil {
+			err = s.putRetentionPolicy(ctx, prj, defaults.Schedule, toRetentionRules(defaults.Rules))
+		}
+		if err != nil {
+			klog.Warningf("Failed to apply default retention policy to Harbor project '%s': %v", projectName, err)
+		}
+	}
+
+	// Un proxy-cache doit pouvoir remplacer les tags modifiés en amont
+	if proxyCache {
+		return
+	}
+	for _, pattern := range s.cfg.Harbor.DefaultImmutableTags {
+		if err := s.createImmutableRule(ctx, projectName, pattern, ""); err != nil {
+			klog.Warningf("Failed to apply default immutability rule '%s' to Harbor project '%s': %v", pattern, projectName, err)
+		}
+	}
+}
+
+// putRetentionPolicy crée la politique du projet ou remplace celle référencée dans ses métadonnées
+func (s *ProjectService) putRetentionPolicy(ctx context.Context, prj *models.Project, schedule string, rules []RetentionRule) error {
+	policy := &models.RetentionPolicy{
+		Algorithm: "or",
+		Rules:     make([]*models.RetentionRule, 0, len(rules)),
+		Scope:     &models.RetentionPolicyScope{Level: "project", Ref: int64(prj.ProjectID)},
+		Trigger: &models.RetentionRuleTrigger{
+			Kind:     "Schedule",
+			Settings: map[string]string{"cron": schedule},
+		},
+	}
+	for _, r := range rules {
+		policy.Rules = append(policy.Rules, toHarborRetentionRule(r))
+	}
+
+	var err error
+	if id, ok := retentionID(prj); ok {
+		policy.ID = id
+		_, err = s.Client.ClientSet().V2().Retention.UpdateRetention(ctx, retention.NewUpdateRetentionParams().WithID(id).WithPolicy(policy))
+	} else {
+		_, err = s.Client.ClientSet().V2().Retention.CreateRetention(ctx, retention.NewCreateRetentionParams().WithPolicy(policy))
+	}
+	if err != nil {
+		if harborclient.IsBadRequest(err) {
+			return fmt.Errorf("%w: retention policy rejected by Harbor, check the schedule and patterns", ErrInvalidRequest)
+		}
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	return nil
+}
+
+func (s *ProjectService) createImmutableRule(ctx context.Context, projectName, tagPattern, repoPattern string) error {
+	isName := true
+	_, err := s.Client.ClientSet().V2().Immutable.CreateImmuRule(ctx, immutable.NewCreateImmuRuleParams().
+		WithXIsResourceName(&isName).
+		WithProjectNameOrID(projectName).
+		WithImmutableRule(&models.ImmutableRule{
+			Action:   "immutable",
+			Template: immutableTemplate,
+			TagSelectors: []*models.ImmutableSelector{{
+				Kind:       "doublestar",
+				Decoration: "matches",
+				Pattern:    tagPattern,
+			}},
+			ScopeSelectors: map[string][]models.ImmutableSelector{
+				"repository": {{
+					Kind:       "doublestar",
+					Decoration: "repoMatches",
+					Pattern:    patternOrDefault(repoPattern),
+				}},
+			},
+		}))
+	if err != nil {
+		switch {
+		case harborclient.IsBadRequest(err):
+			return fmt.Errorf("%w: immutability rule rejected by Harbor, check the patterns", ErrInvalidRequest)
+		case harborclient.IsConflict(err):
+			return fmt.Errorf("%w: an identical immutability rule already exists", ErrInvalidRequest)
+		default:
+			return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+	}
+	return nil
+}
+
+func validateRetentionRules(rules []RetentionRule) error {
+	if len(rules) == 0 || len(rules) > maxRetentionRules {
+		return fmt.Errorf("%w: between 1 and %d rules are required", ErrInvalidRequest, maxRetentionRules)
+	}
+	for _, r := range rules {
+		if (r.KeepLast > 0) == (r.KeepDays > 0) || r.KeepLast < 0 || r.KeepDays < 0 {
+			return fmt.Errorf("%w: each rule needs either keep_last or keep_days", ErrInvalidRequest)
+		}
+	}
+	return nil
+}
+
+// retentionID Harbor référence la politique de rétention d'un projet dans ses métadonnées
+func retentionID(prj *models.Project) (int64, bool) {
+	if prj.Metadata == nil || prj.Metadata.RetentionID == nil {
+		return 0, false
+	}
+	id, err := strconv.ParseInt(*prj.Metadata.RetentionID, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, false
+	}
+	return id, true
+}
+
+func toRetentionRules(rules []config.HarborRetentionRule) []RetentionRule {
+	resp := make([]RetentionRule, 0, len(rules))
+	for _, r := range rules {
+		resp = append(resp, RetentionRule{
+			KeepLast:          r.KeepLast,
+			KeepDays:          r.KeepDays,
+			TagPattern:        r.TagPattern,
+			RepositoryPattern: r.RepositoryPattern,
+		})
+	}
+	return resp
+}
+
+func toHarborRetentionRule(r RetentionRule) *models.RetentionRule {
+	rule := &models.RetentionRule{
+		Action: "retain",
+		TagSelectors: []*models.RetentionSelector{{
+			Kind:       "doublestar",
+			Decoration: "matches",
+			Pattern:    patternOrDefault(r.TagPattern),
+		}},
+		ScopeSelectors: map[string][]models.RetentionSelector{
+			"repository": {{
+				Kind:       "doublestar",
+				Decoration: "repoMatches",
+				Pattern:    patternOrDefault(r.RepositoryPattern),
+			}},
+		},
+	}
+	if r.KeepLast > 0 {
+		rule.Template = retentionTemplateKeepLast
+		rule.Params = map[string]interface{}{retentionTemplateKeepLast: r.KeepLast}
+	} else {
+		rule.Template = retentionTemplateKeepDays
+		rule.Params = map[string]interface{}{retentionTemplateKeepDays: r.KeepDays}
+	}
+	return rule
+}
+
+func toRetentionPolicy(p *models.RetentionPolicy) *RetentionPolicy {
+	policy := &RetentionPolicy{ID: p.ID, Rules: []RetentionRule{}}
+	if p.Trigger != nil {
+		if settings, ok := p.Trigger.Settings.(map[string]interface{}); ok {
+			policy.Schedule, _ = settings["cron"].(string)
+		}
+	}
+	for _, r := range p.Rules {
+		if r == nil || r.Disabled {
+			continue
+		}
+		rule := RetentionRule{}
+		switch r.Template {
+		case retentionTemplateKeepLast:
+			rule.KeepLast = paramInt(r.Params, retentionTemplateKeepLast)
+		case retentionTemplateKeepDays:
+			rule.KeepDays = paramInt(r.Params, retentionTemplateKeepDays)
+		default:
+			rule.Template = r.Template
+		}
+		if len(r.TagSelectors) > 0 && r.TagSelectors[0] != nil {
+			rule.TagPattern = r.TagSelectors[0].Pattern
+		}
+		if repos := r.ScopeSelectors["repository"]; len(repos) > 0 {
+			rule.RepositoryPattern = repos[0].Pattern
+		}
+		policy.Rules = append(policy.Rules, rule)
+	}
+	return policy
+}
+
+func toImmutableRule(r *models.ImmutableRule) ImmutableRule {
+	rule := ImmutableRule{ID: r.ID, Disabled: r.Disabled}
+	if len(r.TagSelectors) > 0 && r.TagSelectors[0] != nil {
+		rule.TagPattern = r.TagSelectors[0].Pattern
+	}
+	if repos := r.ScopeSelectors["repository"]; len(repos) > 0 {
+		rule.RepositoryPattern = repos[0].Pattern
+	}
+	return rule
+}
+
+// paramInt les paramètres des règles sont décodés depuis le JSON de Harbor
+func paramInt(params map[string]interface{}, key string) int64 {
+	switch v := params[key].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	}
+	return 0
+}
+
+func patternOrDefault(pattern string) string {
+	if pattern == "" {
+		return defaultPattern
+	}
+	return pattern
+}
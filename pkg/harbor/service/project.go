@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/project"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	"github.com/Gskill75/api2/pkg/config"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+// ProjectService gère les projets Harbor des clients : propriété (harbor_projects) et appels à Harbor
+type ProjectService struct {
+	Queries     *db.Queries
+	Client      *harborclient.Client
+	cfg         *config.Config
+	pullSecrets *PullSecretService
+}
+
+var (
+	ErrProjectNotFound      = errors.New("project not found or not owned by customer")
+	ErrProjectAlreadyExists = errors.New("project already exists")
+	ErrProjectNotEmpty      = errors.New("project is not empty")
+	ErrInvalidRequest       = errors.New("invalid request")
+	// ErrHarborAPI enveloppe les erreurs renvoyées par Harbor
+	ErrHarborAPI = errors.New("harbor_api_error")
+)
+
+func NewProjectService(cfg *config.Config, queries *db.Queries, client *harborclient.Client) *ProjectService {
+	return &ProjectService{
+		Queries: queries,
+		Client:  client,
+		cfg:     cfg,
+	}
+}
+
+type ProjectMetadata struct {
+	Public             bool   `json:"public"`
+	AutoScan           bool   `json:"auto_scan"`
+	AutoSbomGeneration bool   `json:"auto_sbom_generation"`
+	PreventVulnerable  bool   `json:"prevent_vulnerable"`
+	Severity           string `json:"severity,omitempty"`
+}
+
+// ProjectQuota stockage en octets, -1 pour une limite illimitée
+type ProjectQuota struct {
+	StorageLimitBytes int64 `json:"storage_limit_bytes"`
+	StorageUsedBytes  int64 `json:"storage_used_bytes"`
+}
+
+type ProjectDetails struct {
+	Name       string          `json:"name"`
+	ProjectID  int32           `json:"project_id"`
+	CustomerID string          `json:"customer_id"`
+	CreatedBy  string          `json:"created_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+	RepoCount  int64           `json:"repo_count"`
+	Metadata   ProjectMetadata `json:"metadata"`
+	Quota      *ProjectQuota   `json:"quota,omitempty"`
+}
+
+// ProjectListItem projet du client, complété des données Harbor lorsqu'il y existe encore
+type ProjectListItem struct {
+	Name       string    `json:"name"`
+	CustomerID string    `json:"customer_id"`
+	CreatedBy  string    `json:"created_by"`
+	CreatedAt  time.Time `json:"created_at"`
+	// false si le projet a été supprimé directement dans Harbor
+	InHarbor  bool  `json:"in_harbor"`
+	ProjectID int32 `json:"project_id,omitempty"`
+	Public    bool  `json:"public"`
+	RepoCount int64 `json:"repo_count"`
+}
+
+type DeleteProjectResponse struct {
+	Name                string `json:"name"`
+	DeletedRepositories int    `json:"deleted_repositories"`
+}
+
+type CreateProjectParams struct {
+	Name       string
+	Public     bool
+	CustomerID string
+	CreatedBy  string
+	// Limite de stockage en Go, la valeur par défaut du plan du client si 0
+	StorageLimitGB int64
+	// Registre amont autorisé : le projet est alors un proxy-cache de ce registre
+	Upstream string
+}
+
+type CreateProjectResult struct {
+	Name           string `json:"name"`
+	CustomerID     string `json:"customer_id"`
+	CreatedBy      string `json:"created_by"`
+	Plan           string `json:"plan"`
+	StorageLimitGB int64  `json:"storage_limit_gb"`
+	Upstream       string `json:"upstream,omitempty"`
+}
+
+// CreateProject crée le projet dans Harbor (proxy-cache si un registre amont est demandé) avec la limite
+// de stockage du plan du client, l'enregistre
+// puis lui applique les politiques de rétention et d'immutabilité par défaut
+func (s *ProjectService) CreateProject(ctx context.Context, p CreateProjectParams) (*CreateProjectResult, error) {
+	plan, storageGB, err := s.resolveStorageLimit(ctx, p.CustomerID, p.StorageLimitGB)
+	if err != nil {
+		return nil, err
+	}
+
+	// Vérifie si le projet existe déjà
+	_, err = s.Client.ClientSet().V2().Project.HeadProject(ctx, project.NewHeadProjectParams().WithProjectName(p.Name))
+	if err == nil {
+		return nil, ErrProjectAlreadyExists
+	}
+	if !harborclient.IsNotFound(err) {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	// Prépare le projet à créer
+	var registryID *int64
+	if p.Upstream != "" {
+		reg, err := s.upstreamRegistry(ctx, p.Upstream)
+		if err != nil {
+			return nil, err
+		}
+		registryID = &reg.ID
+	}
+	storageLimit := storageBytes(storageGB)
+	req := &models.ProjectReq{
+		ProjectName:  p.Name,
+		StorageLimit: &storageLimit,
+		RegistryID:   registryID,
+		Metadata: &models.ProjectMetadata{
+			Public:             boolToString(p.Public),
+			AutoScan:           strPtr("true"),
+			AutoSbomGeneration: strPtr("true"),
+			PreventVul:         strPtr("true"),
+			Severity:           strPtr("critical"),
+		},
+	}
+	if _, err := s.Client.ClientSet().V2().Project.CreateProject(ctx, project.NewCreateProjectParams().WithProject(req)); err != nil {
+		if harborclient.IsConflict(err) {
+			return nil, ErrProjectAlreadyExists
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	// Enregistrement DB
+	err = s.Queries.InsertHarborProject(ctx, db.InsertHarborProjectParams{
+		Name:       p.Name,
+		CustomerID: p.CustomerID,
+		CreatedBy:  p.CreatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_create_error: %w", err)
+	}
+
+	s.applyDefaultPolicies(ctx, p.Name, p.Upstream != "")
+
+	return &CreateProjectResult{
+		Name:           p.Name,
+		CustomerID:     p.CustomerID,
+		CreatedBy:      p.CreatedBy,
+		Plan:           plan,
+		StorageLimitGB: storageGB,
+		Upstream:       p.Upstream,
+	}, nil
+}
+
+// GetOwnedProject renvoie le projet s'il appartient au client
+func (s *ProjectService) GetOwnedProject(ctx context.Context, customerID, name string) (*db.HarborProject, error) {
+	prj, err := s.Queries.GetHarborProject(ctx, db.GetHarborProjectParams{
+		Name:       name,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return &prj, nil
+}
+
+// GetProject renvoie le détail d'un projet du client : métadonnées, nombre de dépôts et quota
+func (s *ProjectService) GetProject(ctx context.Context, customerID, name string) (*ProjectDetails, error) {
+	owned, err := s.GetOwnedProject(ctx, customerID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	prj, err := s.harborProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &ProjectDetails{
+		Name:       owned.Name,
+		ProjectID:  prj.ProjectID,
+		CustomerID: owned.CustomerID,
+		CreatedBy:  owned.CreatedBy,
+		CreatedAt:  owned.CreatedAt.Time,
+		RepoCount:  prj.RepoCount,
+	}
+	if md := prj.Metadata; md != nil {
+		details.Metadata = ProjectMetadata{
+			Public:             md.Public == "true",
+			AutoScan:           isTrue(md.AutoScan),
+			AutoSbomGeneration: isTrue(md.AutoSbomGeneration),
+			PreventVulnerable:  isTrue(md.PreventVul),
+			Severity:           derefString(md.Severity),
+		}
+	}
+
+	isName := true
+	summary, err := s.Client.ClientSet().V2().Project.GetProjectSummary(ctx, project.NewGetProjectSummaryParams().
+		WithXIsResourceName(&isName).
+		WithProjectNameOrID(name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	if q := summary.Payload.Quota; q != nil {
+		details.Quota = &ProjectQuota{
+			StorageLimitBytes: q.Hard[storageResource],
+			StorageUsedBytes:  q.Used[storageResource],
+		}
+	}
+	details.RepoCount = summary.Payload.RepoCount
+
+	return details, nil
+}
+
+// ListProjects renvoie les projets du client enregistrés en base, complétés des données Harbor
+func (s *ProjectService) ListProjects(ctx context.Context, customerID string) ([]ProjectListItem, error) {
+	rows, err := s.Queries.ListHarborProjectsByCustomer(ctx, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	projects := make([]ProjectListItem, 0, len(rows))
+	for _, row := range rows {
+		item := ProjectListItem{
+			Name:       row.Name,
+			CustomerID: row.CustomerID,
+			CreatedBy:  row.CreatedBy,
+			CreatedAt:  row.CreatedAt.Time,
+		}
+		prj, err := s.harborProject(ctx, row.Name)
+		switch {
+		case err == nil:
+			item.InHarbor = true
+			item.ProjectID = prj.ProjectID
+			item.RepoCount = prj.RepoCount
+			item.Public = prj.Metadata != nil && prj.Metadata.Public == "true"
+		case errors.Is(err, ErrProjectNotFound):
+			klog.Warningf("Harbor project '%s' of customer %s is registered but missing in Harbor", row.Name, customerID)
+		default:
+			return nil, err
+		}
+		projects = append(projects, item)
+	}
+	return projects, nil
+}
+
+// DeleteProject supprime un projet du client ; un projet contenant des dépôts n'est supprimé
+// qu'avec force, ses dépôts étant alors supprimés d'abord
+func (s *ProjectService) DeleteProject(ctx context.Context, customerID, name string, force bool) (*DeleteProjectResponse, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, name); err != nil {
+		return nil, err
+	}
+	resp := &DeleteProjectResponse{Name: name}
+
+	_, err := s.harborProject(ctx, name)
+	switch {
+	case err == nil:
+		repos, err := s.projectRepositories(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if len(repos) > 0 && !force {
+			return nil, fmt.Errorf("%w: %d repositories, use force=true to delete them", ErrProjectNotEmpty, len(repos))
+		}
+		if err := s.unlinkPullSecrets(ctx, customerID, name); err != nil {
+			return nil, err
+		}
+		if len(repos) > 0 {
+			// Les tags immuables (règles par défaut comprises) bloqueraient la suppression des dépôts
+			if err := s.deleteProjectImmutableRules(ctx, name); err != nil {
+				return nil, err
+			}
+		}
+		for _, repo := range repos {
+			if err := s.deleteRepository(ctx, name, repositoryName(name, repo.Name)); err != nil {
+				return nil, err
+			}
+			resp.DeletedRepositories++
+		}
+
+		if err := s.deleteProjectReplications(ctx, name); err != nil {
+			return nil, err
+		}
+
+		isName := true
+		if _, err := s.Client.ClientSet().V2().Project.DeleteProject(ctx, project.NewDeleteProjectParams().
+			WithXIsResourceName(&isName).
+			WithProjectNameOrID(name)); err != nil && !harborclient.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+	case errors.Is(err, ErrProjectNotFound):
+		// Déjà supprimé dans Harbor : seuls les secrets de pull et l'enregistrement sont retirés
+		klog.Warningf("Harbor project '%s' of customer %s already missing in Harbor", name, customerID)
+		if err := s.unlinkPullSecrets(ctx, customerID, name); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, err
+	}
+
+	if err := s.Queries.DeleteHarborProject(ctx, db.DeleteHarborProjectParams{
+		Name:       name,
+		CustomerID: customerID,
+	}); err != nil {
+		return nil, fmt.Errorf("db_delete_error: %w", err)
+	}
+
+	klog.Infof("Harbor project '%s' deleted for customer %s (%d repositories deleted)", name, customerID, resp.DeletedRepositories)
+	return resp, nil
+}
+
+// unlinkPullSecrets retire les secrets de pull du projet des namespaces liés
+func (s *ProjectService) unlinkPullSecrets(ctx context.Context, customerID, name string) error {
+	if s.pullSecrets == nil {
+		return nil
+	}
+	return s.pullSecrets.UnlinkProject(ctx, customerID, name)
+}
+
+// harborProject renvoie le projet côté Harbor
+func (s *ProjectService) harborProject(ctx context.Context, name string) (*models.Project, error) {
+	isName := true
+	resp, err := s.Client.ClientSet().V2().Project.GetProject(ctx, project.NewGetProjectParams().
+		WithXIsResourceName(&isName).
+		WithProjectNameOrID(name))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	return resp.Payload, nil
+}
+
+func boolToString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func isTrue(s *string) bool {
+	return s != nil && *s == "true"
+}
+
+func derefString(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
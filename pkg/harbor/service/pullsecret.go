@@ -0,0 +1,475 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/Gskill75/api2/pkg/config"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	kubernetesdb "github.com/Gskill75/api2/pkg/db/sqlc/kubernetes"
+	k8sclient "github.com/Gskill75/api2/pkg/kubernetes/client"
+	v1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	defaultServiceAccount = "default"
+	robotNameMaxLength    = 64
+)
+
+var (
+	ErrNamespaceNotFound       = errors.New("namespace not found or not owned by customer")
+	ErrPullSecretNotFound      = errors.New("pull secret not found")
+	ErrPullSecretAlreadyExists = errors.New("project already linked to namespace")
+	ErrSecretConflict          = errors.New("secret already exists in namespace and is not managed by this project")
+	ErrKubernetesAPI           = errors.New("kubernetes_api_error")
+)
+
+// PullSecretService synchronise les identifiants de robots Harbor en imagePullSecrets
+// dans les namespaces Kubernetes des clients
+type PullSecretService struct {
+	projects   *ProjectService
+	Queries    *db.Queries
+	Namespaces *kubernetesdb.Queries
+	Kube       *k8sclient.Client
+	cfg        *config.Config
+}
+
+func NewPullSecretService(cfg *config.Config, projects *ProjectService, kube *k8sclient.Client, namespaces *kubernetesdb.Queries) *PullSecretService {
+	s := &PullSecretService{
+		projects:   projects,
+		Queries:    projects.Queries,
+		Namespaces: namespaces,
+		Kube:       kube,
+		cfg:        cfg,
+	}
+	// La suppression d'un projet retire ses secrets de pull des namespaces liés
+	projects.pullSecrets = s
+	return s
+}
+
+type PullSecretRequest struct {
+	Namespace string `json:"namespace" binding:"required"`
+}
+
+type PullSecretResponse struct {
+	Project    string     `json:"project"`
+	Namespace  string     `json:"namespace"`
+	SecretName string     `json:"secret_name"`
+	RobotName  string     `json:"robot_name"`
+	ExpiresAt  *time.Time `json:"expires_at,omitempty"`
+	CreatedBy  string     `json:"created_by"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// LinkNamespace crée un robot en lecture seule sur le projet, écrit ses identifiants dans un secret
+// kubernetes.io/dockerconfigjson du namespace et l'ajoute aux imagePullSecrets du ServiceAccount par défaut
+func (s *PullSecretService) LinkNamespace(ctx context.Context, customerID, projectName, namespace, createdBy string) (*PullSecretResponse, error) {
+	if _, err := s.projects.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+	if err := s.ensureOwnedNamespace(ctx, customerID, namespace); err != nil {
+		return nil, err
+	}
+	_, err := s.Queries.GetHarborPullSecret(ctx, db.GetHarborPullSecretParams{
+		CustomerID:    customerID,
+		ProjectName:   projectName,
+		NamespaceName: namespace,
+	})
+	if err == nil {
+		return nil, ErrPullSecretAlreadyExists
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	robot, err := s.createPullRobot(ctx, customerID, projectName, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	secretName := pullSecretName(projectName)
+	if err := s.writeSecret(ctx, customerID, projectName, namespace, secretName, robot); err != nil {
+		s.deleteRobot(ctx, customerID, robot.ID)
+		return nil, err
+	}
+	if err := s.patchServiceAccount(ctx, namespace, secretName, true); err != nil {
+		s.deleteSecret(ctx, namespace, secretName)
+		s.deleteRobot(ctx, customerID, robot.ID)
+		return nil, err
+	}
+
+	row, err := s.Queries.InsertHarborPullSecret(ctx, db.InsertHarborPullSecretParams{
+		CustomerID:    customerID,
+		ProjectName:   projectName,
+		NamespaceName: namespace,
+		SecretName:    secretName,
+		RobotID:       robot.ID,
+		RobotName:     robot.Name,
+		ExpiresAt:     optionalTimestamp(robot.ExpiresAt),
+		CreatedBy:     createdBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_create_error: %w", err)
+	}
+
+	klog.Infof("Harbor project '%s' linked to namespace '%s' for customer %s (secret=%s, robot=%s)", projectName, namespace, customerID, secretName, robot.Name)
+	return toPullSecretResponse(row), nil
+}
+
+// ListPullSecrets renvoie les namespaces liés à un projet du client
+func (s *PullSecretService) ListPullSecrets(ctx context.Context, customerID, projectName string) ([]PullSecretResponse, error) {
+	if _, err := s.projects.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.Queries.ListHarborPullSecretsByProject(ctx, db.ListHarborPullSecretsByProjectParams{
+		CustomerID:  customerID,
+		ProjectName: projectName,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+
+	secrets := make([]PullSecretResponse, 0, len(rows))
+	for _, row := range rows {
+		secrets = append(secrets, *toPullSecretResponse(row))
+	}
+	return secrets, nil
+}
+
+// RefreshPullSecret renouvelle immédiatement le robot d'un namespace lié
+func (s *PullSecretService) RefreshPullSecret(ctx context.Context, customerID, projectName, namespace string) (*PullSecretResponse, error) {
+	row, err := s.getPullSecret(ctx, customerID, projectName, namespace)
+	if err != nil {
+		return nil, err
+	}
+	return s.refresh(ctx, row)
+}
+
+// UnlinkNamespace retire le secret du ServiceAccount, le supprime du namespace et supprime le robot
+func (s *PullSecretService) UnlinkNamespace(ctx context.Context, customerID, projectName, namespace string) error {
+	row, err := s.getPullSecret(ctx, customerID, projectName, namespace)
+	if err != nil {
+		return err
+	}
+	return s.unlink(ctx, row)
+}
+
+// UnlinkProject retire les secrets de pull de tous les namespaces liés au projet
+func (s *PullSecretService) UnlinkProject(ctx context.Context, customerID, projectName string) error {
+	rows, err := s.Queries.ListHarborPullSecretsByProject(ctx, db.ListHarborPullSecretsByProjectParams{
+		CustomerID:  customerID,
+		ProjectName: projectName,
+	})
+	if err != nil {
+		return fmt.Errorf("db_error: %w", err)
+	}
+
+	for _, row := range rows {
+		if err := s.unlink(ctx, row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *PullSecretService) unlink(ctx context.Context, row db.HarborPullSecret) error {
+	namespace := row.NamespaceName
+	if err := s.patchServiceAccount(ctx, namespace, row.SecretName, false); err != nil {
+		return err
+	}
+	if err := s.Kube.Clientset().CoreV1().Secrets(namespace).Delete(ctx, row.SecretName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return fmt.Errorf("%w: %w", ErrKubernetesAPI, err)
+	}
+	s.deleteRobot(ctx, row.CustomerID, row.RobotID)
+
+	if err := s.Queries.DeleteHarborPullSecret(ctx, row.ID); err != nil {
+		return fmt.Errorf("db_delete_error: %w", err)
+	}
+
+	klog.Infof("Harbor project '%s' unlinked from namespace '%s' for customer %s", row.ProjectName, namespace, row.CustomerID)
+	return nil
+}
+
+// RefreshExpiring renouvelle les robots expirant dans moins de pull_secret_refresh_before_days jours ;
+// les liens dont le projet ou le namespace n'appartient plus au client sont abandonnés.
+// Chaque lien est réservé avant renouvellement : un seul réplica le traite
+func (s *PullSecretService) RefreshExpiring(ctx context.Context) {
+	threshold := time.Now().Add(time.Duration(s.cfg.Harbor.PullSecretRefreshBeforeDays) * 24 * time.Hour)
+	rows, err := s.Queries.ClaimHarborPullSecretsToRefresh(ctx, pgtype.Timestamptz{Time: threshold, Valid: true})
+	if err != nil {
+		klog.Errorf("Failed to claim Harbor pull secrets to refresh: %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		_, err := s.refresh(ctx, row)
+		s.RecordHistory(ctx, row.CustomerID, db.HarborActionTypeEnumRefreshPullSecret, row.ProjectName, row.NamespaceName, HistoryActor, err)
+		switch {
+		case err == nil:
+		case errors.Is(err, ErrProjectNotFound), errors.Is(err, ErrNamespaceNotFound):
+			klog.Warningf("Dropping pull secret of project '%s' in namespace '%s': %v", row.ProjectName, row.NamespaceName, err)
+			if err := s.Queries.DeleteHarborPullSecret(ctx, row.ID); err != nil {
+				klog.Errorf("Failed to delete pull secret %d: %v", row.ID, err)
+			}
+		default:
+			klog.Errorf("Failed to refresh pull secret of project '%s' in namespace '%s': %v", row.ProjectName, row.NamespaceName, err)
+		}
+	}
+}
+
+// RecordHistory trace une opération sur les secrets de pull dans l'historique Harbor
+func (s *PullSecretService) RecordHistory(ctx context.Context, customerID string, action db.HarborActionTypeEnum, projectName, resource, createdBy string, opErr error) {
+	s.projects.RecordHistory(ctx, customerID, action, projectName, resource, createdBy, opErr)
+}
+
+// RunRefresher renouvelle les secrets de pull avant l'expiration de leurs robots jusqu'à l'arrêt du serveur
+func (s *PullSecretService) RunRefresher(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.RefreshExpiring(ctx)
+		}
+	}
+}
+
+// refresh crée un nouveau robot, met à jour le secret puis supprime l'ancien robot
+func (s *PullSecretService) refresh(ctx context.Context, row db.HarborPullSecret) (*PullSecretResponse, error) {
+	if _, err := s.projects.GetOwnedProject(ctx, row.CustomerID, row.ProjectName); err != nil {
+		return nil, err
+	}
+	if err := s.ensureOwnedNamespace(ctx, row.CustomerID, row.NamespaceName); err != nil {
+		return nil, err
+	}
+
+	robot, err := s.createPullRobot(ctx, row.CustomerID, row.ProjectName, row.NamespaceName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.writeSecret(ctx, row.CustomerID, row.ProjectName, row.NamespaceName, row.SecretName, robot); err != nil {
+		s.deleteRobot(ctx, row.CustomerID, robot.ID)
+		return nil, err
+	}
+	// Le ServiceAccount a pu être recréé ou modifié depuis la liaison
+	if err := s.patchServiceAccount(ctx, row.NamespaceName, row.SecretName, true); err != nil {
+		klog.Warningf("Failed to patch ServiceAccount of namespace '%s': %v", row.NamespaceName, err)
+	}
+
+	updated, err := s.Queries.UpdateHarborPullSecretRobot(ctx, db.UpdateHarborPullSecretRobotParams{
+		ID:        row.ID,
+		RobotID:   robot.ID,
+		RobotName: robot.Name,
+		ExpiresAt: optionalTimestamp(robot.ExpiresAt),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_update_error: %w", err)
+	}
+	s.deleteRobot(ctx, row.CustomerID, row.RobotID)
+
+	klog.Infof("Pull secret of project '%s' in namespace '%s' refreshed (robot=%s)", row.ProjectName, row.NamespaceName, robot.Name)
+	return toPullSecretResponse(updated), nil
+}
+
+func (s *PullSecretService) getPullSecret(ctx context.Context, customerID, projectName, namespace string) (db.HarborPullSecret, error) {
+	row, err := s.Queries.GetHarborPullSecret(ctx, db.GetHarborPullSecretParams{
+		CustomerID:    customerID,
+		ProjectName:   projectName,
+		NamespaceName: namespace,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return row, ErrPullSecretNotFound
+		}
+		return row, fmt.Errorf("db_error: %w", err)
+	}
+	return row, nil
+}
+
+// ensureOwnedNamespace vérifie que le namespace appartient au client (table namespaces de la solution Kubernetes)
+func (s *PullSecretService) ensureOwnedNamespace(ctx context.Context, customerID, namespace string) error {
+	_, err := s.Namespaces.GetNamespace(ctx, kubernetesdb.GetNamespaceParams{
+		Name:       namespace,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNamespaceNotFound
+		}
+		return fmt.Errorf("db_error: %w", err)
+	}
+	return nil
+}
+
+func (s *PullSecretService) createPullRobot(ctx context.Context, customerID, projectName, namespace string) (*RobotCreatedResponse, error) {
+	return s.projects.CreateRobot(ctx, customerID, projectName, CreateRobotRequest{
+		Name:         pullRobotName(namespace, time.Now()),
+		Description:  fmt.Sprintf("Image pull secret of namespace %s", namespace),
+		Permissions:  []string{RobotPermissionPull},
+		DurationDays: s.cfg.Harbor.PullSecretRobotDurationDays,
+	})
+}
+
+// writeSecret crée ou met à jour le secret dockerconfigjson du namespace
+func (s *PullSecretService) writeSecret(ctx context.Context, customerID, projectName, namespace, secretName string, robot *RobotCreatedResponse) error {
+	dockerConfig, err := s.dockerConfigJSON(robot.Name, robot.Secret)
+	if err != nil {
+		return err
+	}
+
+	secrets := s.Kube.Clientset().CoreV1().Secrets(namespace)
+	existing, err := secrets.Get(ctx, secretName, metav1.GetOptions{})
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %w", ErrKubernetesAPI, err)
+		}
+		_, err = secrets.Create(ctx, &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: namespace,
+				Annotations: map[string]string{
+					"customer-id":    customerID,
+					"harbor-project": projectName,
+					"harbor-robot":   robot.Name,
+				},
+			},
+			Type: v1.SecretTypeDockerConfigJson,
+			Data: map[string][]byte{v1.DockerConfigJsonKey: dockerConfig},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrKubernetesAPI, err)
+		}
+		return nil
+	}
+
+	// Un secret du même nom créé hors de l'API n'est jamais écrasé
+	if existing.Annotations["customer-id"] != customerID || existing.Annotations["harbor-project"] != projectName {
+		return fmt.Errorf("%w: %s", ErrSecretConflict, secretName)
+	}
+	existing.Annotations["harbor-robot"] = robot.Name
+	existing.Data = map[string][]byte{v1.DockerConfigJsonKey: dockerConfig}
+	if _, err := secrets.Update(ctx, existing, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("%w: %w", ErrKubernetesAPI, err)
+	}
+	return nil
+}
+
+// patchServiceAccount ajoute (ou retire) le secret des imagePullSecrets du ServiceAccount par défaut
+func (s *PullSecretService) patchServiceAccount(ctx context.Context, namespace, secretName string, add bool) error {
+	serviceAccounts := s.Kube.Clientset().CoreV1().ServiceAccounts(namespace)
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		sa, err := serviceAccounts.Get(ctx, defaultServiceAccount, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		idx := slices.IndexFunc(sa.ImagePullSecrets, func(ref v1.LocalObjectReference) bool {
+			return ref.Name == secretName
+		})
+		switch {
+		case add && idx < 0:
+			sa.ImagePullSecrets = append(sa.ImagePullSecrets, v1.LocalObjectReference{Name: secretName})
+		case !add && idx >= 0:
+			sa.ImagePullSecrets = slices.Delete(sa.ImagePullSecrets, idx, idx+1)
+		default:
+			return nil
+		}
+		_, err = serviceAccounts.Update(ctx, sa, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		if !add && k8serrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("%w: %w", ErrKubernetesAPI, err)
+	}
+	return nil
+}
+
+func (s *PullSecretService) deleteSecret(ctx context.Context, namespace, secretName string) {
+	if err := s.Kube.Clientset().CoreV1().Secrets(namespace).Delete(ctx, secretName, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		klog.Warningf("Failed to delete secret '%s' of namespace '%s': %v", secretName, namespace, err)
+	}
+}
+
+// deleteRobot un robot déjà supprimé dans Harbor n'est pas une erreur
+func (s *PullSecretService) deleteRobot(ctx context.Context, customerID string, robotID int64) {
+	if _, err := s.projects.DeleteRobot(ctx, customerID, robotID); err != nil && !errors.Is(err, ErrRobotNotFound) {
+		klog.Warningf("Failed to delete Harbor robot %d: %v", robotID, err)
+	}
+}
+
+// dockerConfigJSON format attendu par les secrets kubernetes.io/dockerconfigjson
+func (s *PullSecretService) dockerConfigJSON(username, password string) ([]byte, error) {
+	registry := s.cfg.Harbor.Url
+	if u, err := url.Parse(s.cfg.Harbor.Url); err == nil && u.Host != "" {
+		registry = u.Host
+	}
+
+	auth := base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+	return json.Marshal(map[string]any{
+		"auths": map[string]any{
+			registry: map[string]string{
+				"username": username,
+				"password": password,
+				"auth":     auth,
+			},
+		},
+	})
+}
+
+// pullSecretName les noms de secrets n'acceptent pas "_", autorisé dans les noms de projets Harbor
+func pullSecretName(projectName string) string {
+	return "harbor-" + strings.ReplaceAll(projectName, "_", "-")
+}
+
+// pullRobotName nom unique par renouvellement, Harbor refusant deux robots de même nom
+func pullRobotName(namespace string, now time.Time) string {
+	suffix := fmt.Sprintf("-%d", now.Unix())
+	name := "k8s-" + namespace
+	if len(name)+len(suffix) > robotNameMaxLength {
+		name = strings.TrimRight(name[:robotNameMaxLength-len(suffix)], "-.")
+	}
+	return name + suffix
+}
+
+func optionalTimestamp(t *time.Time) pgtype.Timestamptz {
+	if t == nil {
+		return pgtype.Timestamptz{}
+	}
+	return pgtype.Timestamptz{Time: *t, Valid: true}
+}
+
+func toPullSecretResponse(row db.HarborPullSecret) *PullSecretResponse {
+	resp := &PullSecretResponse{
+		Project:    row.ProjectName,
+		Namespace:  row.NamespaceName,
+		SecretName: row.SecretName,
+		RobotName:  row.RobotName,
+		CreatedBy:  row.CreatedBy,
+		CreatedAt:  row.CreatedAt.Time,
+		UpdatedAt:  row.UpdatedAt.Time,
+	}
+	if row.ExpiresAt.Valid {
+		resp.ExpiresAt = &row.ExpiresAt.Time
+	}
+	return resp
+}
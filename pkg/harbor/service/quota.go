@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/quota"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	db "github.com/Gskill75/api2/pkg/db/sqlc/harbor"
+	"k8s.io/klog/v2"
+)
+
+const storageResource = "storage"
+
+// maxStorageGB plus grande limite convertible en octets sans dépasser un int64
+const maxStorageGB = math.MaxInt64 >> 30
+
+var (
+	ErrStorageLimitExceeded = errors.New("storage_limit_exceeded")
+	ErrPlanNotFound         = errors.New("plan not found")
+	ErrQuotaNotFound        = errors.New("project quota not found")
+)
+
+// StorageLimitError précise la limite du plan qui serait dépassée
+type StorageLimitError struct {
+	Plan      string
+	Limit     int64
+	Requested int64
+}
+
+func (e *StorageLimitError) Error() string {
+	return fmt.Sprintf("%s: plan %s allows %d GB per project, requested %d GB", ErrStorageLimitExceeded, e.Plan, e.Limit, e.Requested)
+}
+
+func (e *StorageLimitError) Unwrap() error {
+	return ErrStorageLimitExceeded
+}
+
+type QuotaRequest struct {
+	// Limite de stockage en Go, la valeur par défaut du plan du client si 0
+	StorageLimitGB int64 `json:"storage_limit"`
+}
+
+type CustomerPlanRequest struct {
+	Plan string `json:"plan" binding:"required"`
+}
+
+// CustomerPlanResponse limites en Go, 0 pour illimité
+type CustomerPlanResponse struct {
+	CustomerID       string     `json:"customer_id"`
+	Plan             string     `json:"plan"`
+	Default          bool       `json:"default"`
+	DefaultStorageGB int64      `json:"default_storage_gb"`
+	MaxStorageGB     int64      `json:"max_storage_gb"`
+	UpdatedBy        string     `json:"updated_by,omitempty"`
+	UpdatedAt        *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetProjectQuota modifie la limite de stockage d'un projet du client, dans les limites de son plan
+func (s *ProjectService) SetProjectQuota(ctx context.Context, customerID, name string, req QuotaRequest) (*ProjectQuota, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, name); err != nil {
+		return nil, err
+	}
+	_, storageGB, err := s.resolveStorageLimit(ctx, customerID, req.StorageLimitGB)
+	if err != nil {
+		return nil, err
+	}
+
+	prj, err := s.harborProject(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	reference := "project"
+	referenceID := strconv.FormatInt(int64(prj.ProjectID), 10)
+	quotas, err := s.Client.ClientSet().V2().Quota.ListQuotas(ctx, quota.NewListQuotasParams().
+		WithReference(&reference).
+		WithReferenceID(&referenceID))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	if len(quotas.Payload) == 0 || quotas.Payload[0] == nil {
+		return nil, ErrQuotaNotFound
+	}
+	q := quotas.Payload[0]
+
+	hard := models.ResourceList{storageResource: storageBytes(storageGB)}
+	if _, err := s.Client.ClientSet().V2().Quota.UpdateQuota(ctx, quota.NewUpdateQuotaParams().
+		WithID(q.ID).
+		WithHard(&models.QuotaUpdateReq{Hard: hard})); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	klog.Infof("Harbor project '%s' storage limit set to %d GB for customer %s", name, storageGB, customerID)
+	return &ProjectQuota{
+		StorageLimitBytes: hard[storageResource],
+		StorageUsedBytes:  q.Used[storageResource],
+	}, nil
+}
+
+// GetCustomerPlan renvoie le plan du client, le plan par défaut s'il n'en a pas
+func (s *ProjectService) GetCustomerPlan(ctx context.Context, customerID string) (*CustomerPlanResponse, error) {
+	row, err := s.Queries.GetHarborCustomerPlan(ctx, customerID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return s.planResponse(customerID, s.cfg.Harbor.DefaultPlan, true), nil
+		}
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return s.customerPlanResponse(row), nil
+}
+
+// SetCustomerPlan attribue un plan défini dans la configuration au client
+func (s *ProjectService) SetCustomerPlan(ctx context.Context, customerID string, req CustomerPlanRequest, updatedBy string) (*CustomerPlanResponse, error) {
+	if _, ok := s.cfg.Harbor.Plans[req.Plan]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPlanNotFound, req.Plan)
+	}
+
+	row, err := s.Queries.UpsertHarborCustomerPlan(ctx, db.UpsertHarborCustomerPlanParams{
+		CustomerID: customerID,
+		Plan:       req.Plan,
+		UpdatedBy:  updatedBy,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("db_error: %w", err)
+	}
+	return s.customerPlanResponse(row), nil
+}
+
+// resolveStorageLimit applique la valeur par défaut du plan du client et vérifie son maximum
+func (s *ProjectService) resolveStorageLimit(ctx context.Context, customerID string, requestedGB int64) (string, int64, error) {
+	plan, err := s.GetCustomerPlan(ctx, customerID)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if requestedGB < 0 {
+		return "", 0, fmt.Errorf("%w: storage_limit must be positive", ErrInvalidRequest)
+	}
+	if requestedGB == 0 {
+		requestedGB = plan.DefaultStorageGB
+	}
+	if plan.MaxStorageGB > 0 && (requestedGB == 0 || requestedGB > plan.MaxStorageGB) {
+		return "", 0, &StorageLimitError{Plan: plan.Plan, Limit: plan.MaxStorageGB, Requested: requestedGB}
+	}
+	if requestedGB > maxStorageGB {
+		return "", 0, fmt.Errorf("%w: storage_limit must not exceed %d GB", ErrInvalidRequest, int64(maxStorageGB))
+	}
+	return plan.Plan, requestedGB, nil
+}
+
+func (s *ProjectService) customerPlanResponse(row db.HarborCustomerPlan) *CustomerPlanResponse {
+	resp := s.planResponse(row.CustomerID, row.Plan, false)
+	resp.UpdatedBy = row.UpdatedBy
+	if row.UpdatedAt.Valid {
+		resp.UpdatedAt = &row.UpdatedAt.Time
+	}
+	return resp
+}
+
+// planResponse un plan retiré de la configuration n'a plus de limites
+func (s *ProjectService) planResponse(customerID, name string, isDefault bool) *CustomerPlanResponse {
+	plan := s.cfg.Harbor.Plans[name]
+	return &CustomerPlanResponse{
+		CustomerID:       customerID,
+		Plan:             name,
+		Default:          isDefault,
+		DefaultStorageGB: plan.DefaultStorageGB,
+		MaxStorageGB:     plan.MaxStorageGB,
+	}
+}
+
+// storageBytes convertit une limite en Go, -1 (illimité) si 0
+func storageBytes(gb int64) int64 {
+	if gb == 0 {
+		return -1
+	}
+	return gb << 30
+}
@@ -0,0 +1,467 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/registry"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/replication"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	replicationPageSize = int64(100)
+	// replicationExecutionCount nombre d'exécutions renvoyées par règle
+	replicationExecutionCount = int64(10)
+	// flattenOneLevel "library/nginx" est répliqué en "<projet>/nginx"
+	flattenOneLevel = int8(1)
+)
+
+var ErrReplicationNotFound = errors.New("replication rule not found")
+
+type UpstreamResponse struct {
+	Name   string `json:"name"`
+	Type   string `json:"type"`
+	URL    string `json:"url"`
+	Status string `json:"status,omitempty"`
+}
+
+type ReplicationRequest struct {
+	// Registre amont autorisé (voir GET /upstreams)
+	Upstream string `json:"upstream" binding:"required"`
+	// Dépôt(s) amont, motifs Harbor acceptés (library/nginx, library/**)
+	Repository string `json:"repository" binding:"required"`
+	// Tags à répliquer, tous si vide
+	Tag string `json:"tag"`
+	// Planification (cron Harbor, avec secondes) ; réplication manuelle si vide.
+	// Deux exécutions sont espacées d'au moins replication_min_interval_minutes
+	Cron string `json:"cron"`
+}
+
+type ReplicationResponse struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	Upstream   string    `json:"upstream"`
+	Repository string    `json:"repository"`
+	Tag        string    `json:"tag,omitempty"`
+	Cron       string    `json:"cron,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReplicationExecution struct {
+	ID         int64      `json:"id"`
+	Status     string     `json:"status"`
+	StatusText string     `json:"status_text,omitempty"`
+	Trigger    string     `json:"trigger"`
+	Total      int64      `json:"total"`
+	Succeed    int64      `json:"succeed"`
+	Failed     int64      `json:"failed"`
+	StartTime  time.Time  `json:"start_time"`
+	EndTime    *time.Time `json:"end_time,omitempty"`
+}
+
+// ListUpstreams renvoie les registres amont autorisés et configurés dans Harbor
+func (s *ProjectService) ListUpstreams(ctx context.Context) ([]UpstreamResponse, error) {
+	upstreams := make([]UpstreamResponse, 0, len(s.cfg.Harbor.UpstreamRegistries))
+	for _, name := range s.cfg.Harbor.UpstreamRegistries {
+		reg, err := s.upstreamRegistry(ctx, name)
+		if err != nil {
+			if errors.Is(err, ErrInvalidRequest) {
+				klog.Warningf("Upstream registry '%s' is not configured in Harbor", name)
+				continue
+			}
+			return nil, err
+		}
+		upstreams = append(upstreams, UpstreamResponse{
+			Name:   reg.Name,
+			Type:   reg.Type,
+			URL:    reg.URL,
+			Status: reg.Status,
+		})
+	}
+	return upstreams, nil
+}
+
+// CreateReplication crée une règle de réplication en pull d'un registre amont vers le projet du client
+func (s *ProjectService) CreateReplication(ctx context.Context, customerID, projectName string, req ReplicationRequest) (*ReplicationResponse, error) {
+	if req.Cron != "" {
+		minInterval := time.Duration(s.cfg.Harbor.ReplicationMinIntervalMinutes) * time.Minute
+		if err := validateReplicationCron(req.Cron, minInterval); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+	prj, err := s.harborProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	if prj.RegistryID != 0 {
+		return nil, fmt.Errorf("%w: cannot replicate into a proxy-cache project", ErrInvalidRequest)
+	}
+	reg, err := s.upstreamRegistry(ctx, req.Upstream)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := []*models.ReplicationFilter{{Type: "name", Value: req.Repository}}
+	if req.Tag != "" {
+		filters = append(filters, &models.ReplicationFilter{Type: "tag", Value: req.Tag})
+	}
+	trigger := &models.ReplicationTrigger{Type: "manual"}
+	if req.Cron != "" {
+		trigger = &models.ReplicationTrigger{
+			Type:            "scheduled",
+			TriggerSettings: &models.ReplicationTriggerSettings{Cron: req.Cron},
+		}
+	}
+
+	name, err := replicationPolicyName(projectName)
+	if err != nil {
+		return nil, err
+	}
+	replaceCount := flattenOneLevel
+	policy := &models.ReplicationPolicy{
+		Name:                      name,
+		Description:               fmt.Sprintf("Pull replication of %s from %s", req.Repository, reg.Name),
+		SrcRegistry:               &models.Registry{ID: reg.ID},
+		DestNamespace:             projectName,
+		DestNamespaceReplaceCount: &replaceCount,
+		Filters:                   filters,
+		Trigger:                   trigger,
+		Enabled:                   true,
+		Override:                  true,
+	}
+	created, err := s.Client.ClientSet().V2().Replication.CreateReplicationPolicy(ctx, replication.NewCreateReplicationPolicyParams().
+		WithPolicy(policy))
+	if err != nil {
+		if harborclient.IsBadRequest(err) {
+			return nil, fmt.Errorf("%w: invalid repository, tag or cron", ErrInvalidRequest)
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	// Harbor ne renvoie que l'URL de la règle créée
+	policy.ID, _ = strconv.ParseInt(path.Base(created.Location), 10, 64)
+	policy.SrcRegistry = reg
+
+	klog.Infof("Replication rule '%s' created from '%s' into Harbor project '%s' for customer %s", policy.Name, reg.Name, projectName, customerID)
+	resp := toReplicationResponse(policy)
+	resp.CreatedAt = time.Now().UTC()
+	return &resp, nil
+}
+
+// ListReplications renvoie les règles de réplication vers le projet du client
+func (s *ProjectService) ListReplications(ctx context.Context, customerID, projectName string) ([]ReplicationResponse, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	policies, err := s.projectReplications(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]ReplicationResponse, 0, len(policies))
+	for _, p := range policies {
+		rules = append(rules, toReplicationResponse(p))
+	}
+	return rules, nil
+}
+
+// DeleteReplication supprime une règle de réplication du projet du client
+func (s *ProjectService) DeleteReplication(ctx context.Context, customerID, projectName string, policyID int64) error {
+	if _, err := s.ownedReplication(ctx, customerID, projectName, policyID); err != nil {
+		return err
+	}
+
+	_, err := s.Client.ClientSet().V2().Replication.DeleteReplicationPolicy(ctx, replication.NewDeleteReplicationPolicyParams().
+		WithID(policyID))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return ErrReplicationNotFound
+		}
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	klog.Infof("Replication rule %d of Harbor project '%s' deleted for customer %s", policyID, projectName, customerID)
+	return nil
+}
+
+// RunReplication lance immédiatement une règle de réplication du projet du client
+func (s *ProjectService) RunReplication(ctx context.Context, customerID, projectName string, policyID int64) (int64, error) {
+	if _, err := s.ownedReplication(ctx, customerID, projectName, policyID); err != nil {
+		return 0, err
+	}
+
+	started, err := s.Client.ClientSet().V2().Replication.StartReplication(ctx, replication.NewStartReplicationParams().
+		WithExecution(&models.StartReplicationExecution{PolicyID: policyID}))
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	executionID, _ := strconv.ParseInt(path.Base(started.Location), 10, 64)
+	klog.Infof("Replication rule %d of Harbor project '%s' started for customer %s (execution=%d)", policyID, projectName, customerID, executionID)
+	return executionID, nil
+}
+
+// ListReplicationExecutions renvoie les dernières exécutions d'une règle du projet du client
+func (s *ProjectService) ListReplicationExecutions(ctx context.Context, customerID, projectName string, policyID int64) ([]ReplicationExecution, error) {
+	if _, err := s.ownedReplication(ctx, customerID, projectName, policyID); err != nil {
+		return nil, err
+	}
+
+	page, pageSize, sort := int64(1), replicationExecutionCount, "-start_time"
+	resp, err := s.Client.ClientSet().V2().Replication.ListReplicationExecutions(ctx, replication.NewListReplicationExecutionsParams().
+		WithPolicyID(&policyID).
+		WithSort(&sort).
+		WithPage(&page).
+		WithPageSize(&pageSize))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	executions := make([]ReplicationExecution, 0, len(resp.Payload))
+	for _, e := range resp.Payload {
+		if e == nil {
+			continue
+		}
+		executions = append(executions, ReplicationExecution{
+			ID:         e.ID,
+			Status:     e.Status,
+			StatusText: e.StatusText,
+			Trigger:    e.Trigger,
+			Total:      e.Total,
+			Succeed:    e.Succeed,
+			Failed:     e.Failed,
+			StartTime:  time.Time(e.StartTime),
+			EndTime:    optionalTime(e.EndTime),
+		})
+	}
+	return executions, nil
+}
+
+// ownedReplication renvoie la règle si elle réplique vers le projet du client
+func (s *ProjectService) ownedReplication(ctx context.Context, customerID, projectName string, policyID int64) (*models.ReplicationPolicy, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Client.ClientSet().V2().Replication.GetReplicationPolicy(ctx, replication.NewGetReplicationPolicyParams().
+		WithID(policyID))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrReplicationNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	if !isProjectReplication(resp.Payload, projectName) {
+		return nil, ErrReplicationNotFound
+	}
+	return resp.Payload, nil
+}
+
+// projectReplications renvoie les règles de réplication en pull vers le projet ; Harbor ne permettant
+// pas de filtrer sur le projet de destination, toutes les règles sont parcourues
+func (s *ProjectService) projectReplications(ctx context.Context, projectName string) ([]*models.ReplicationPolicy, error) {
+	policies := []*models.ReplicationPolicy{}
+	for page := int64(1); ; page++ {
+		pageSize := replicationPageSize
+		resp, err := s.Client.ClientSet().V2().Replication.ListReplicationPolicies(ctx, replication.NewListReplicationPoliciesParams().
+			WithPage(&page).
+			WithPageSize(&pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+		for _, p := range resp.Payload {
+			if isProjectReplication(p, projectName) {
+				policies = append(policies, p)
+			}
+		}
+		if int64(len(resp.Payload)) < pageSize {
+			break
+		}
+	}
+	return policies, nil
+}
+
+// deleteProjectReplications supprime les règles de réplication vers un projet supprimé
+func (s *ProjectService) deleteProjectReplications(ctx context.Context, projectName string) error {
+	policies, err := s.projectReplications(ctx, projectName)
+	if err != nil {
+		return err
+	}
+	for _, p := range policies {
+		_, err := s.Client.ClientSet().V2().Replication.DeleteReplicationPolicy(ctx, replication.NewDeleteReplicationPolicyParams().
+			WithID(p.ID))
+		if err != nil && !harborclient.IsNotFound(err) {
+			return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+	}
+	return nil
+}
+
+// upstreamRegistry renvoie l'endpoint Harbor d'un registre amont autorisé
+func (s *ProjectService) upstreamRegistry(ctx context.Context, name string) (*models.Registry, error) {
+	if !slices.Contains(s.cfg.Harbor.UpstreamRegistries, name) {
+		return nil, fmt.Errorf("%w: upstream '%s' is not allowed", ErrInvalidRequest, name)
+	}
+
+	resp, err := s.Client.ClientSet().V2().Registry.ListRegistries(ctx, registry.NewListRegistriesParams().
+		WithName(&name))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	for _, reg := range resp.Payload {
+		if reg != nil && reg.Name == name {
+			return reg, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: upstream '%s' is not configured in Harbor", ErrInvalidRequest, name)
+}
+
+// replicationPolicyName les règles de réplication sont globales dans Harbor : le nom est préfixé par
+// le projet et complété d'un suffixe aléatoire, deux créations simultanées ne devant pas entrer en conflit
+func replicationPolicyName(projectName string) (string, error) {
+	raw := make([]byte, 6)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("failed to generate replication rule name: %w", err)
+	}
+	return projectName + "-" + hex.EncodeToString(raw), nil
+}
+
+// validateReplicationCron vérifie un cron Harbor (secondes minutes heures jour mois jour-de-semaine) et
+// que deux exécutions sur une journée sont espacées d'au moins minInterval. Les champs jour, mois et
+// jour de la semaine ne peuvent qu'espacer davantage les exécutions : seuls les trois premiers comptent
+func validateReplicationCron(cron string, minInterval time.Duration) error {
+	fields := strings.Fields(cron)
+	if len(fields) != 6 {
+		return fmt.Errorf("%w: cron must have 6 fields (seconds minutes hours day month weekday)", ErrInvalidRequest)
+	}
+	seconds, err := cronFieldValues(fields[0], 0, 59)
+	if err != nil {
+		return err
+	}
+	minutes, err := cronFieldValues(fields[1], 0, 59)
+	if err != nil {
+		return err
+	}
+	hours, err := cronFieldValues(fields[2], 0, 23)
+	if err != nil {
+		return err
+	}
+
+	// Instants d'exécution dans la journée (secondes depuis minuit), dans l'ordre croissant
+	runs := make([]int, 0, len(hours)*len(minutes)*len(seconds))
+	for _, h := range hours {
+		for _, m := range minutes {
+			for _, sec := range seconds {
+				runs = append(runs, h*3600+m*60+sec)
+			}
+		}
+	}
+	// L'écart entre la dernière exécution d'un jour et la première du lendemain compte aussi
+	shortest := runs[0] + 24*3600 - runs[len(runs)-1]
+	for i := 1; i < len(runs); i++ {
+		shortest = min(shortest, runs[i]-runs[i-1])
+	}
+	if time.Duration(shortest)*time.Second < minInterval {
+		return fmt.Errorf("%w: cron must not run more often than every %s", ErrInvalidRequest, minInterval)
+	}
+	return nil
+}
+
+// cronFieldValues valeurs d'un champ cron (*, n, a-b, */n, a-b/n, listes séparées par des virgules), triées
+func cronFieldValues(field string, lower, upper int) ([]int, error) {
+	invalid := fmt.Errorf("%w: invalid cron field %q", ErrInvalidRequest, field)
+	set := map[int]bool{}
+	for _, part := range strings.Split(field, ",") {
+		rng, step := part, 1
+		if before, after, ok := strings.Cut(part, "/"); ok {
+			n, err := strconv.Atoi(after)
+			if err != nil || n <= 0 {
+				return nil, invalid
+			}
+			rng, step = before, n
+		}
+
+		first, last := lower, upper
+		switch {
+		case rng == "*" || rng == "?":
+		case strings.Contains(rng, "-"):
+			a, b, _ := strings.Cut(rng, "-")
+			var errA, errB error
+			first, errA = strconv.Atoi(a)
+			last, errB = strconv.Atoi(b)
+			if errA != nil || errB != nil {
+				return nil, invalid
+			}
+		default:
+			n, err := strconv.Atoi(rng)
+			if err != nil {
+				return nil, invalid
+			}
+			first = n
+			// "a/n" va de a à la valeur maximale du champ
+			if step == 1 {
+				last = n
+			}
+		}
+		if first < lower || last > upper || first > last {
+			return nil, invalid
+		}
+		for v := first; v <= last; v += step {
+			set[v] = true
+		}
+	}
+
+	values := make([]int, 0, len(set))
+	for v := range set {
+		values = append(values, v)
+	}
+	slices.Sort(values)
+	return values, nil
+}
+
+func isProjectReplication(p *models.ReplicationPolicy, projectName string) bool {
+	return p != nil && p.SrcRegistry != nil && p.SrcRegistry.ID != 0 && p.DestNamespace == projectName
+}
+
+func toReplicationResponse(p *models.ReplicationPolicy) ReplicationResponse {
+	resp := ReplicationResponse{
+		ID:        p.ID,
+		Name:      p.Name,
+		Enabled:   p.Enabled,
+		CreatedAt: time.Time(p.CreationTime),
+	}
+	if p.SrcRegistry != nil {
+		resp.Upstream = p.SrcRegistry.Name
+	}
+	for _, f := range p.Filters {
+		if f == nil {
+			continue
+		}
+		value, _ := f.Value.(string)
+		switch f.Type {
+		case "name":
+			resp.Repository = value
+		case "tag":
+			resp.Tag = value
+		}
+	}
+	if p.Trigger != nil && p.Trigger.TriggerSettings != nil {
+		resp.Cron = p.Trigger.TriggerSettings.Cron
+	}
+	return resp
+}
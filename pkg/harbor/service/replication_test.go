@@ -0,0 +1,67 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestValidateReplicationCron(t *testing.T) {
+	tests := []struct {
+		name        string
+		cron        string
+		minInterval time.Duration
+		wantErr     bool
+	}{
+		{name: "daily", cron: "0 0 3 * * *", minInterval: time.Hour, wantErr: false},
+		{name: "hourly", cron: "0 15 * * * *", minInterval: time.Hour, wantErr: false},
+		{name: "every two hours", cron: "0 0 */2 * * *", minInterval: time.Hour, wantErr: false},
+		{name: "twice a day", cron: "0 30 6,18 * * 1-5", minInterval: time.Hour, wantErr: false},
+		{name: "every second", cron: "* * * * * *", minInterval: time.Hour, wantErr: true},
+		{name: "every minute", cron: "0 * * * * *", minInterval: time.Hour, wantErr: true},
+		{name: "every 30 minutes", cron: "0 */30 * * * *", minInterval: time.Hour, wantErr: true},
+		{name: "two minutes in an hour", cron: "0 0,59 * * * *", minInterval: time.Hour, wantErr: true},
+		{name: "across midnight", cron: "0 0 1,23 * * *", minInterval: 3 * time.Hour, wantErr: true},
+		{name: "across midnight allowed", cron: "0 0 1,23 * * *", minInterval: 2 * time.Hour, wantErr: false},
+		{name: "five fields", cron: "0 3 * * *", minInterval: time.Hour, wantErr: true},
+		{name: "out of range", cron: "0 60 3 * * *", minInterval: time.Hour, wantErr: true},
+		{name: "reversed range", cron: "0 0 5-2 * * *", minInterval: time.Hour, wantErr: true},
+		{name: "invalid step", cron: "0 0 */0 * * *", minInterval: time.Hour, wantErr: true},
+		{name: "descriptor", cron: "@every 1m", minInterval: time.Hour, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateReplicationCron(tt.cron, tt.minInterval)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateReplicationCron(%q) = %v, want error %v", tt.cron, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRequest) {
+				t.Fatalf("validateReplicationCron(%q) = %v, want ErrInvalidRequest", tt.cron, err)
+			}
+		})
+	}
+}
+
+func TestCronFieldValues(t *testing.T) {
+	tests := []struct {
+		field string
+		want  []int
+	}{
+		{field: "5", want: []int{5}},
+		{field: "1-3", want: []int{1, 2, 3}},
+		{field: "*/20", want: []int{0, 20, 40}},
+		{field: "10-30/10", want: []int{10, 20, 30}},
+		{field: "50/5", want: []int{50, 55}},
+		{field: "7,1,7", want: []int{1, 7}},
+	}
+	for _, tt := range tests {
+		got, err := cronFieldValues(tt.field, 0, 59)
+		if err != nil {
+			t.Fatalf("cronFieldValues(%q): %v", tt.field, err)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Fatalf("cronFieldValues(%q) = %v, want %v", tt.field, got, tt.want)
+		}
+	}
+}
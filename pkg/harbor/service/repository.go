@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-openapi/strfmt"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/repository"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	repositoryPageSize = int64(100)
+
+	DefaultArtifactPageSize = int64(50)
+	MaxArtifactPageSize     = int64(100)
+)
+
+var (
+	ErrRepositoryNotFound = errors.New("repository not found")
+	ErrArtifactNotFound   = errors.New("artifact or tag not found")
+	ErrArtifactImmutable  = errors.New("artifact is protected by an immutability rule")
+)
+
+type RepositoryResponse struct {
+	// Nom du dépôt sans le préfixe du projet
+	Name          string     `json:"name"`
+	FullName      string     `json:"full_name"`
+	Description   string     `json:"description,omitempty"`
+	ArtifactCount int64      `json:"artifact_count"`
+	PullCount     int64      `json:"pull_count"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+type TagResponse struct {
+	Name      string     `json:"name"`
+	Immutable bool       `json:"immutable"`
+	PushedAt  *time.Time `json:"pushed_at,omitempty"`
+	PulledAt  *time.Time `json:"pulled_at,omitempty"`
+}
+
+// ArtifactResponse taille en octets ; pulled_at absent si l'artefact n'a jamais été tiré
+type ArtifactResponse struct {
+	Digest    string        `json:"digest"`
+	Type      string        `json:"type"`
+	MediaType string        `json:"media_type"`
+	Size      int64         `json:"size"`
+	Tags      []TagResponse `json:"tags"`
+	PushedAt  *time.Time    `json:"pushed_at,omitempty"`
+	PulledAt  *time.Time    `json:"pulled_at,omitempty"`
+}
+
+// ListRepositories renvoie les dépôts d'un projet du client
+func (s *ProjectService) ListRepositories(ctx context.Context, customerID, projectName string) ([]RepositoryResponse, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	repos, err := s.projectRepositories(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]RepositoryResponse, 0, len(repos))
+	for _, r := range repos {
+		item := RepositoryResponse{
+			Name:          repositoryName(projectName, r.Name),
+			FullName:      r.Name,
+			Description:   r.Description,
+			ArtifactCount: r.ArtifactCount,
+			PullCount:     r.PullCount,
+			UpdatedAt:     optionalTime(r.UpdateTime),
+		}
+		if r.CreationTime != nil {
+			item.CreatedAt = optionalTime(*r.CreationTime)
+		}
+		resp = append(resp, item)
+	}
+	return resp, nil
+}
+
+// ListArtifacts renvoie une page des artefacts d'un dépôt du client, avec leurs tags
+func (s *ProjectService) ListArtifacts(ctx context.Context, customerID, projectName, repoName string, page, pageSize int64) ([]ArtifactResponse, error) {
+	if err := validatePage(page, pageSize); err != nil {
+		return nil, err
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	withTag := true
+	resp, err := s.Client.ClientSet().V2().Artifact.ListArtifacts(ctx, artifact.NewListArtifactsParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithWithTag(&withTag).
+		WithPage(&page).
+		WithPageSize(&pageSize))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrRepositoryNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	artifacts := make([]ArtifactResponse, 0, len(resp.Payload))
+	for _, a := range resp.Payload {
+		if a != nil {
+			artifacts = append(artifacts, toArtifactResponse(a))
+		}
+	}
+	return artifacts, nil
+}
+
+// DeleteArtifact supprime un artefact (référence : digest ou tag) et tous ses tags
+func (s *ProjectService) DeleteArtifact(ctx context.Context, customerID, projectName, repoName, reference string) error {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+
+	_, err := s.Client.ClientSet().V2().Artifact.DeleteArtifact(ctx, artifact.NewDeleteArtifactParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(reference))
+	if err != nil {
+		return artifactError(err)
+	}
+
+	klog.Infof("Harbor artifact %s/%s@%s deleted for customer %s", projectName, repoName, reference, customerID)
+	return nil
+}
+
+// DeleteTag retire un tag, l'artefact reste disponible par son digest
+func (s *ProjectService) DeleteTag(ctx context.Context, customerID, projectName, repoName, tag string) error {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+
+	_, err := s.Client.ClientSet().V2().Artifact.DeleteTag(ctx, artifact.NewDeleteTagParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(tag).
+		WithTagName(tag))
+	if err != nil {
+		return artifactError(err)
+	}
+
+	klog.Infof("Harbor tag %s/%s:%s deleted for customer %s", projectName, repoName, tag, customerID)
+	return nil
+}
+
+// projectRepositories renvoie tous les dépôts d'un projet Harbor
+func (s *ProjectService) projectRepositories(ctx context.Context, projectName string) ([]*models.Repository, error) {
+	var repos []*models.Repository
+	for page := int64(1); ; page++ {
+		pageSize := repositoryPageSize
+		resp, err := s.Client.ClientSet().V2().Repository.ListRepositories(ctx, repository.NewListRepositoriesParams().
+			WithProjectName(projectName).
+			WithPage(&page).
+			WithPageSize(&pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+		repos = append(repos, resp.Payload...)
+		if int64(len(resp.Payload)) < pageSize {
+			break
+		}
+	}
+	return repos, nil
+}
+
+// deleteRepository supprime un dépôt et tous ses artefacts
+func (s *ProjectService) deleteRepository(ctx context.Context, projectName, repoName string) error {
+	_, err := s.Client.ClientSet().V2().Repository.DeleteRepository(ctx, repository.NewDeleteRepositoryParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)))
+	switch {
+	case err == nil, harborclient.IsNotFound(err):
+		return nil
+	case harborclient.IsPreconditionFailed(err):
+		// Harbor refuse (412) de supprimer un dépôt qui contient des tags immuables
+		return fmt.Errorf("%w: repository '%s' holds immutable tags", ErrArtifactImmutable, repoName)
+	default:
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+}
+
+func artifactError(err error) error {
+	switch {
+	case harborclient.IsNotFound(err):
+		return ErrArtifactNotFound
+	case harborclient.IsPreconditionFailed(err):
+		return ErrArtifactImmutable
+	default:
+		return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+}
+
+func validatePage(page, pageSize int64) error {
+	if page < 1 {
+		return fmt.Errorf("%w: page must be positive", ErrInvalidRequest)
+	}
+	if pageSize < 1 || pageSize > MaxArtifactPageSize {
+		return fmt.Errorf("%w: page_size must be between 1 and %d", ErrInvalidRequest, MaxArtifactPageSize)
+	}
+	return nil
+}
+
+func toArtifactResponse(a *models.Artifact) ArtifactResponse {
+	tags := make([]TagResponse, 0, len(a.Tags))
+	for _, t := range a.Tags {
+		if t == nil {
+			continue
+		}
+		tags = append(tags, TagResponse{
+			Name:      t.Name,
+			Immutable: t.Immutable,
+			PushedAt:  optionalTime(t.PushTime),
+			PulledAt:  optionalTime(t.PullTime),
+		})
+	}
+	return ArtifactResponse{
+		Digest:    a.Digest,
+		Type:      a.Type,
+		MediaType: a.ManifestMediaType,
+		Size:      a.Size,
+		Tags:      tags,
+		PushedAt:  optionalTime(a.PushTime),
+		PulledAt:  optionalTime(a.PullTime),
+	}
+}
+
+// optionalTime Harbor renvoie une date nulle (0001-01-01) pour un artefact jamais tiré
+func optionalTime(dt strfmt.DateTime) *time.Time {
+	t := time.Time(dt)
+	if t.IsZero() || t.Year() <= 1 {
+		return nil
+	}
+	return &t
+}
+
+// repositoryName retire le préfixe du projet renvoyé par Harbor ("projet/dépôt")
+func repositoryName(projectName, fullName string) string {
+	return strings.TrimPrefix(fullName, projectName+"/")
+}
+
+// escapeRepositoryName Harbor attend les "/" des noms de dépôt encodés deux fois,
+// le client encode le chemin une seconde fois
+func escapeRepositoryName(name string) string {
+	return url.PathEscape(name)
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/robot"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	RobotPermissionPull = "pull"
+	RobotPermissionPush = "push"
+
+	robotPageSize = int64(100)
+)
+
+var (
+	ErrRobotNotFound      = errors.New("robot account not found")
+	ErrRobotAlreadyExists = errors.New("robot account already exists")
+
+	robotNameRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[._-][a-z0-9]+)*$`)
+)
+
+type CreateRobotRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	// Droits sur les dépôts du projet : pull et/ou push (push inclut pull)
+	Permissions []string `json:"permissions" binding:"required"`
+	// Validité en jours, la valeur par défaut de la configuration si 0
+	DurationDays int64 `json:"duration_days"`
+}
+
+type RobotResponse struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	Project     string     `json:"project"`
+	Description string     `json:"description,omitempty"`
+	Permissions []string   `json:"permissions"`
+	Disabled    bool       `json:"disabled"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// RobotCreatedResponse le secret n'est renvoyé qu'à la création
+type RobotCreatedResponse struct {
+	RobotResponse
+	Secret string `json:"secret"`
+}
+
+// CreateRobot crée un robot limité au projet du client, avec les droits pull/push demandés
+func (s *ProjectService) CreateRobot(ctx context.Context, customerID, projectName string, req CreateRobotRequest) (*RobotCreatedResponse, error) {
+	access, permissions, err := s.validateRobotRequest(&req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	created, err := s.Client.ClientSet().V2().Robot.CreateRobot(ctx, robot.NewCreateRobotParams().WithRobot(&models.RobotCreate{
+		Name:        req.Name,
+		Description: req.Description,
+		Level:       "project",
+		Duration:    req.DurationDays,
+		Permissions: []*models.RobotPermission{{
+			Kind:      "project",
+			Namespace: projectName,
+			Access:    access,
+		}},
+	}))
+	if err != nil {
+		if harborclient.IsConflict(err) {
+			return nil, ErrRobotAlreadyExists
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	klog.Infof("Harbor robot '%s' created on project '%s' for customer %s (permissions=%v, duration=%dd)", created.Payload.Name, projectName, customerID, permissions, req.DurationDays)
+	return &RobotCreatedResponse{
+		RobotResponse: RobotResponse{
+			ID:          created.Payload.ID,
+			Name:        created.Payload.Name,
+			Project:     projectName,
+			Description: req.Description,
+			Permissions: permissions,
+			ExpiresAt:   expiresAt(created.Payload.ExpiresAt),
+			CreatedAt:   time.Time(created.Payload.CreationTime),
+		},
+		Secret: created.Payload.Secret,
+	}, nil
+}
+
+// ListRobots renvoie les robots du projet du client
+func (s *ProjectService) ListRobots(ctx context.Context, customerID, projectName string) ([]RobotResponse, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	prj, err := s.harborProject(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("Level=project,ProjectID=%d", prj.ProjectID)
+	robots := []RobotResponse{}
+	for page := int64(1); ; page++ {
+		pageSize := robotPageSize
+		resp, err := s.Client.ClientSet().V2().Robot.ListRobot(ctx, robot.NewListRobotParams().
+			WithQ(&query).
+			WithPage(&page).
+			WithPageSize(&pageSize))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+		for _, r := range resp.Payload {
+			robots = append(robots, toRobotResponse(r))
+		}
+		if int64(len(resp.Payload)) < pageSize {
+			break
+		}
+	}
+	return robots, nil
+}
+
+// GetRobot renvoie un robot d'un projet du client
+func (s *ProjectService) GetRobot(ctx context.Context, customerID string, robotID int64) (*RobotResponse, error) {
+	r, err := s.getOwnedRobot(ctx, customerID, robotID)
+	if err != nil {
+		return nil, err
+	}
+	resp := toRobotResponse(r)
+	return &resp, nil
+}
+
+// DeleteRobot supprime un robot d'un projet du client
+func (s *ProjectService) DeleteRobot(ctx context.Context, customerID string, robotID int64) (*RobotResponse, error) {
+	r, err := s.getOwnedRobot(ctx, customerID, robotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.Client.ClientSet().V2().Robot.DeleteRobot(ctx, robot.NewDeleteRobotParams().WithRobotID(robotID)); err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrRobotNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	resp := toRobotResponse(r)
+	klog.Infof("Harbor robot '%s' of project '%s' deleted for customer %s", resp.Name, resp.Project, customerID)
+	return &resp, nil
+}
+
+// getOwnedRobot renvoie le robot s'il est limité à un projet du client ; les robots système
+// et ceux des autres clients sont renvoyés comme inexistants
+func (s *ProjectService) getOwnedRobot(ctx context.Context, customerID string, robotID int64) (*models.Robot, error) {
+	resp, err := s.Client.ClientSet().V2().Robot.GetRobotByID(ctx, robot.NewGetRobotByIDParams().WithRobotID(robotID))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrRobotNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	r := resp.Payload
+	projectName := robotProject(r)
+	if r.Level != "project" || projectName == "" {
+		return nil, ErrRobotNotFound
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		if errors.Is(err, ErrProjectNotFound) {
+			return nil, ErrRobotNotFound
+		}
+		return nil, err
+	}
+	return r, nil
+}
+
+// validateRobotRequest vérifie la demande, applique la durée par défaut et renvoie les accès Harbor
+func (s *ProjectService) validateRobotRequest(req *CreateRobotRequest) ([]*models.Access, []string, error) {
+	if len(req.Name) > 64 || !robotNameRegexp.MatchString(req.Name) {
+		return nil, nil, fmt.Errorf("%w: name must be lowercase alphanumeric (with . _ -), 64 characters max", ErrInvalidRequest)
+	}
+
+	maxDays := s.cfg.Harbor.RobotMaxDurationDays
+	if req.DurationDays == 0 {
+		req.DurationDays = s.cfg.Harbor.RobotDefaultDurationDays
+	}
+	if req.DurationDays < 1 || (maxDays > 0 && req.DurationDays > maxDays) {
+		return nil, nil, fmt.Errorf("%w: duration_days must be between 1 and %d", ErrInvalidRequest, maxDays)
+	}
+
+	if len(req.Permissions) == 0 {
+		return nil, nil, fmt.Errorf("%w: permissions is required", ErrInvalidRequest)
+	}
+
+	access := []*models.Access{{Resource: "repository", Action: "pull"}}
+	permissions := []string{RobotPermissionPull}
+	for _, p := range req.Permissions {
+		switch p {
+		case RobotPermissionPull:
+		case RobotPermissionPush:
+			if !slices.Contains(permissions, RobotPermissionPush) {
+				access = append(access, &models.Access{Resource: "repository", Action: "push"})
+				permissions = append(permissions, RobotPermissionPush)
+			}
+		default:
+			return nil, nil, fmt.Errorf("%w: permissions must be pull or push", ErrInvalidRequest)
+		}
+	}
+	return access, permissions, nil
+}
+
+// robotProject renvoie le projet auquel le robot est limité
+func robotProject(r *models.Robot) string {
+	for _, p := range r.Permissions {
+		if p != nil && p.Kind == "project" {
+			return p.Namespace
+		}
+	}
+	return ""
+}
+
+func toRobotResponse(r *models.Robot) RobotResponse {
+	permissions := []string{}
+	for _, p := range r.Permissions {
+		if p == nil {
+			continue
+		}
+		for _, a := range p.Access {
+			if a != nil && a.Resource == "repository" && (a.Action == RobotPermissionPull || a.Action == RobotPermissionPush) && !slices.Contains(permissions, a.Action) {
+				permissions = append(permissions, a.Action)
+			}
+		}
+	}
+	return RobotResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Project:     robotProject(r),
+		Description: r.Description,
+		Permissions: permissions,
+		Disabled:    r.Disable,
+		ExpiresAt:   expiresAt(r.ExpiresAt),
+		CreatedAt:   time.Time(r.CreationTime),
+	}
+}
+
+// expiresAt convertit l'expiration Harbor (timestamp unix, -1 si jamais)
+func expiresAt(ts int64) *time.Time {
+	if ts <= 0 {
+		return nil
+	}
+	t := time.Unix(ts, 0).UTC()
+	return &t
+}
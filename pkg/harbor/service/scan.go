@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/artifact"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/client/scan"
+	"github.com/goharbor/go-client/pkg/sdk/v2.0/models"
+	harborclient "github.com/Gskill75/api2/pkg/harbor/client"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ScanTypeVulnerability = "vulnerability"
+	ScanTypeSBOM          = "sbom"
+
+	SeverityCritical = "Critical"
+	SeverityHigh     = "High"
+	SeverityMedium   = "Medium"
+	SeverityLow      = "Low"
+	SeverityUnknown  = "Unknown"
+
+	// Addition Harbor portée par l'accessoire SBOM d'un artefact
+	sbomAddition = "sbom"
+)
+
+var (
+	ErrScanNotFound = errors.New("scan report not found")
+	ErrScanRunning  = errors.New("a scan is already running")
+)
+
+// VulnerabilitySummary nombre de vulnérabilités par sévérité du dernier scan
+type VulnerabilitySummary struct {
+	ScanStatus string     `json:"scan_status"`
+	Severity   string     `json:"severity,omitempty"`
+	Scanner    string     `json:"scanner,omitempty"`
+	Total      int64      `json:"total"`
+	Fixable    int64      `json:"fixable"`
+	Critical   int64      `json:"critical"`
+	High       int64      `json:"high"`
+	Medium     int64      `json:"medium"`
+	Low        int64      `json:"low"`
+	Unknown    int64      `json:"unknown"`
+	StartedAt  *time.Time `json:"started_at,omitempty"`
+	EndedAt    *time.Time `json:"ended_at,omitempty"`
+}
+
+type Vulnerability struct {
+	ID          string   `json:"id"`
+	Package     string   `json:"package"`
+	Version     string   `json:"version"`
+	FixVersion  string   `json:"fix_version,omitempty"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description,omitempty"`
+	Links       []string `json:"links,omitempty"`
+}
+
+type VulnerabilityReport struct {
+	GeneratedAt     string          `json:"generated_at,omitempty"`
+	Scanner         string          `json:"scanner,omitempty"`
+	Severity        string          `json:"severity,omitempty"`
+	Vulnerabilities []Vulnerability `json:"vulnerabilities"`
+}
+
+// ArtifactVulnerabilities artefact comportant des vulnérabilités critiques ou hautes
+type ArtifactVulnerabilities struct {
+	Repository string   `json:"repository"`
+	Digest     string   `json:"digest"`
+	Tags       []string `json:"tags"`
+	Critical   int64    `json:"critical"`
+	High       int64    `json:"high"`
+}
+
+// ProjectVulnerabilitySummary synthèse des derniers scans des artefacts d'un projet
+type ProjectVulnerabilitySummary struct {
+	Project          string                    `json:"project"`
+	Artifacts        int64                     `json:"artifacts"`
+	ScannedArtifacts int64                     `json:"scanned_artifacts"`
+	Critical         int64                     `json:"critical"`
+	High             int64                     `json:"high"`
+	Vulnerable       []ArtifactVulnerabilities `json:"vulnerable_artifacts"`
+}
+
+// harborReport rapport natif Harbor (application/vnd.security.vulnerability.report; version=1.1)
+type harborReport struct {
+	GeneratedAt string `json:"generated_at"`
+	Scanner     *struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	} `json:"scanner"`
+	Severity        string `json:"severity"`
+	Vulnerabilities []struct {
+		ID          string   `json:"id"`
+		Package     string   `json:"package"`
+		Version     string   `json:"version"`
+		FixVersion  string   `json:"fix_version"`
+		Severity    string   `json:"severity"`
+		Description string   `json:"description"`
+		Links       []string `json:"links"`
+	} `json:"vulnerabilities"`
+}
+
+// ScanArtifact lance un scan de vulnérabilités ou une génération de SBOM sur un artefact du client
+func (s *ProjectService) ScanArtifact(ctx context.Context, customerID, projectName, repoName, reference, scanType string) error {
+	if scanType != ScanTypeVulnerability && scanType != ScanTypeSBOM {
+		return fmt.Errorf("%w: type must be %s or %s", ErrInvalidRequest, ScanTypeVulnerability, ScanTypeSBOM)
+	}
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return err
+	}
+
+	_, err := s.Client.ClientSet().V2().Scan.ScanArtifact(ctx, scan.NewScanArtifactParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(reference).
+		WithScanType(&models.ScanType{ScanType: scanType}))
+	if err != nil {
+		switch {
+		case harborclient.IsNotFound(err):
+			return ErrArtifactNotFound
+		case harborclient.IsConflict(err):
+			return ErrScanRunning
+		default:
+			return fmt.Errorf("%w: %w", ErrHarborAPI, err)
+		}
+	}
+
+	klog.Infof("Harbor %s scan requested on %s/%s@%s for customer %s", scanType, projectName, repoName, reference, customerID)
+	return nil
+}
+
+// GetVulnerabilitySummary renvoie la synthèse du dernier scan d'un artefact du client
+func (s *ProjectService) GetVulnerabilitySummary(ctx context.Context, customerID, projectName, repoName, reference string) (*VulnerabilitySummary, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	a, err := s.scannedArtifact(ctx, projectName, repoName, reference)
+	if err != nil {
+		return nil, err
+	}
+	summary := toVulnerabilitySummary(a.ScanOverview)
+	if summary == nil {
+		return nil, ErrScanNotFound
+	}
+	return summary, nil
+}
+
+// GetVulnerabilityReport renvoie le détail des vulnérabilités du dernier scan d'un artefact du client
+func (s *ProjectService) GetVulnerabilityReport(ctx context.Context, customerID, projectName, repoName, reference string) (*VulnerabilityReport, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Client.ClientSet().V2().Artifact.GetVulnerabilitiesAddition(ctx, artifact.NewGetVulnerabilitiesAdditionParams().
+		WithDefaults().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(reference))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+
+	// Harbor renvoie un rapport par type MIME, vide si l'artefact n'a pas été scanné
+	var reports map[string]harborReport
+	if err := json.Unmarshal([]byte(resp.Payload), &reports); err != nil {
+		return nil, fmt.Errorf("%w: invalid vulnerability report: %w", ErrHarborAPI, err)
+	}
+	for _, r := range reports {
+		report := &VulnerabilityReport{
+			GeneratedAt:     r.GeneratedAt,
+			Severity:        r.Severity,
+			Vulnerabilities: make([]Vulnerability, 0, len(r.Vulnerabilities)),
+		}
+		if r.Scanner != nil {
+			report.Scanner = r.Scanner.Name + " " + r.Scanner.Version
+		}
+		for _, v := range r.Vulnerabilities {
+			report.Vulnerabilities = append(report.Vulnerabilities, Vulnerability{
+				ID:          v.ID,
+				Package:     v.Package,
+				Version:     v.Version,
+				FixVersion:  v.FixVersion,
+				Severity:    v.Severity,
+				Description: v.Description,
+				Links:       v.Links,
+			})
+		}
+		return report, nil
+	}
+	return nil, ErrScanNotFound
+}
+
+// GetSBOM renvoie le SBOM (JSON) généré pour un artefact du client
+func (s *ProjectService) GetSBOM(ctx context.Context, customerID, projectName, repoName, reference string) (string, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return "", err
+	}
+
+	a, err := s.scannedArtifact(ctx, projectName, repoName, reference)
+	if err != nil {
+		return "", err
+	}
+	if a.SbomOverview == nil || a.SbomOverview.SbomDigest == "" {
+		return "", ErrScanNotFound
+	}
+
+	// Le SBOM est stocké comme accessoire de l'artefact, référencé par son digest
+	resp, err := s.Client.ClientSet().V2().Artifact.GetAddition(ctx, artifact.NewGetAdditionParams().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(a.SbomOverview.SbomDigest).
+		WithAddition(sbomAddition))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return "", ErrScanNotFound
+		}
+		return "", fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	return resp.Payload, nil
+}
+
+// GetProjectVulnerabilitySummary agrège les vulnérabilités critiques et hautes des artefacts d'un projet du client
+func (s *ProjectService) GetProjectVulnerabilitySummary(ctx context.Context, customerID, projectName string) (*ProjectVulnerabilitySummary, error) {
+	if _, err := s.GetOwnedProject(ctx, customerID, projectName); err != nil {
+		return nil, err
+	}
+
+	repos, err := s.projectRepositories(ctx, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &ProjectVulnerabilitySummary{
+		Project:    projectName,
+		Vulnerable: []ArtifactVulnerabilities{},
+	}
+	withScan := true
+	for _, repo := range repos {
+		repoName := repositoryName(projectName, repo.Name)
+		for page := int64(1); ; page++ {
+			pageSize := MaxArtifactPageSize
+			resp, err := s.Client.ClientSet().V2().Artifact.ListArtifacts(ctx, artifact.NewListArtifactsParams().
+				WithDefaults().
+				WithProjectName(projectName).
+				WithRepositoryName(escapeRepositoryName(repoName)).
+				WithWithScanOverview(&withScan).
+				WithPage(&page).
+				WithPageSize(&pageSize))
+			if err != nil {
+				return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+			}
+
+			for _, a := range resp.Payload {
+				if a == nil {
+					continue
+				}
+				summary.Artifacts++
+				vs := toVulnerabilitySummary(a.ScanOverview)
+				if vs == nil || vs.EndedAt == nil {
+					continue
+				}
+				summary.ScannedArtifacts++
+				summary.Critical += vs.Critical
+				summary.High += vs.High
+				if vs.Critical > 0 || vs.High > 0 {
+					tags := []string{}
+					for _, t := range a.Tags {
+						if t != nil {
+							tags = append(tags, t.Name)
+						}
+					}
+					summary.Vulnerable = append(summary.Vulnerable, ArtifactVulnerabilities{
+						Repository: repoName,
+						Digest:     a.Digest,
+						Tags:       tags,
+						Critical:   vs.Critical,
+						High:       vs.High,
+					})
+				}
+			}
+			if int64(len(resp.Payload)) < pageSize {
+				break
+			}
+		}
+	}
+	return summary, nil
+}
+
+// scannedArtifact renvoie un artefact avec la synthèse de ses scans de vulnérabilités et SBOM
+func (s *ProjectService) scannedArtifact(ctx context.Context, projectName, repoName, reference string) (*models.Artifact, error) {
+	withScan := true
+	resp, err := s.Client.ClientSet().V2().Artifact.GetArtifact(ctx, artifact.NewGetArtifactParams().
+		WithDefaults().
+		WithProjectName(projectName).
+		WithRepositoryName(escapeRepositoryName(repoName)).
+		WithReference(reference).
+		WithWithScanOverview(&withScan).
+		WithWithSbomOverview(&withScan))
+	if err != nil {
+		if harborclient.IsNotFound(err) {
+			return nil, ErrArtifactNotFound
+		}
+		return nil, fmt.Errorf("%w: %w", ErrHarborAPI, err)
+	}
+	return resp.Payload, nil
+}
+
+// toVulnerabilitySummary Harbor indexe la synthèse par type MIME du rapport, un seul scanner est configuré
+func toVulnerabilitySummary(overview models.ScanOverview) *VulnerabilitySummary {
+	for _, r := range overview {
+		summary := &VulnerabilitySummary{
+			ScanStatus: r.ScanStatus,
+			Severity:   r.Severity,
+			StartedAt:  optionalTime(r.StartTime),
+			EndedAt:    optionalTime(r.EndTime),
+		}
+		if r.Scanner != nil {
+			summary.Scanner = r.Scanner.Name + " " + r.Scanner.Version
+		}
+		if r.Summary != nil {
+			summary.Total = r.Summary.Total
+			summary.Fixable = r.Summary.Fixable
+			summary.Critical = r.Summary.Summary[SeverityCritical]
+			summary.High = r.Summary.Summary[SeverityHigh]
+			summary.Medium = r.Summary.Summary[SeverityMedium]
+			summary.Low = r.Summary.Summary[SeverityLow]
+			summary.Unknown = r.Summary.Summary[SeverityUnknown]
+		}
+		return summary
+	}
+	return nil
+}
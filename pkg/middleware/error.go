@@ -37,6 +37,8 @@ func determineStatusCode(err error) int {
 	switch {
 	case errors.Is(err, sql.ErrNoRows):
 		return http.StatusNotFound
+	case errors.Is(err, apierrors.ErrNotFound):
+		return http.StatusNotFound
 	case errors.Is(err, apierrors.ErrBadRequest):
 		return http.StatusBadRequest
 	case errors.Is(err, apierrors.ErrUnauthorized):
@@ -47,6 +49,8 @@ func determineStatusCode(err error) int {
 		return http.StatusConflict
 	case errors.Is(err, apierrors.ErrTooManyRequests):
 		return http.StatusTooManyRequests
+	case errors.Is(err, apierrors.ErrUnavailable):
+		return http.StatusServiceUnavailable
 	case strings.HasPrefix(err.Error(), "bad_request:"):
 		return http.StatusBadRequest
 	case strings.HasPrefix(err.Error(), "unauthorized:"):
@@ -57,6 +61,8 @@ func determineStatusCode(err error) int {
 		return http.StatusNotFound
 	case strings.HasPrefix(err.Error(), "conflict:"):
 		return http.StatusConflict
+	case strings.HasPrefix(err.Error(), "unavailable:"):
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -74,6 +80,7 @@ func extractErrorMessage(err error) string {
 		"not_found: ",
 		"conflict: ",
 		"internal_error: ",
+		"unavailable: ",
 	}
 
 	for _, prefix := range prefixes {
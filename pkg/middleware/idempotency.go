@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	idempotencydb "github.com/Gskill75/api2/pkg/db/sqlc/idempotency"
+	"k8s.io/klog/v2"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
+// idempotencyWriter conserve une copie de la réponse pour la rejouer
+type idempotencyWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *idempotencyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *idempotencyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency rejoue la réponse d'une requête mutante déjà traitée avec le même header Idempotency-Key.
+// La clé est propre au client (customer_id du token) et conservée ttl ; réutilisée avec une autre
+// requête, ou pendant que la première est en cours, elle est refusée en 409. Seules les réponses
+// 2xx sont conservées : après une erreur le client peut réessayer avec la même clé.
+// Les réponses sont stockées en clair : à n'installer que sur des routes qui ne renvoient aucun secret.
+func Idempotency(queries *idempotencydb.Queries, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		rid := c.GetString(RequestIDKey)
+		customerID := c.GetString("customer_id")
+		if customerID == "" {
+			// Le handler répond 401
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long", "request_id": rid})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Unable to read request body", "request_id": rid})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		// La query string désigne parfois la cible (?repository=, ?force=...) : elle fait partie de la requête
+		target := c.Request.URL.RequestURI()
+		hash := requestHash(c.Request.Method, target, body)
+
+		ctx := c.Request.Context()
+		record, err := queries.ClaimIdempotencyKey(ctx, idempotencydb.ClaimIdempotencyKeyParams{
+			CustomerID:     customerID,
+			IdempotencyKey: key,
+			Method:         c.Request.Method,
+			Path:           target,
+			RequestHash:    hash,
+			ExpiredBefore:  pgtype.Timestamptz{Time: time.Now().Add(-ttl), Valid: true},
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			replayIdempotentResponse(c, queries, customerID, key, hash)
+			return
+		}
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to claim idempotency key: %v", rid, err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check Idempotency-Key", "request_id": rid})
+			return
+		}
+
+		writer := &idempotencyWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		// La requête du client peut être annulée, l'enregistrement doit aboutir
+		storeCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		status := writer.Status()
+		if status < 200 || status >= 300 {
+			if err := queries.DeleteIdempotencyKey(storeCtx, record.ID); err != nil {
+				klog.Errorf("[request_id=%s] Failed to release idempotency key: %v", rid, err)
+			}
+			return
+		}
+		err = queries.CompleteIdempotencyKey(storeCtx, idempotencydb.CompleteIdempotencyKeyParams{
+			ID:                  record.ID,
+			ResponseStatus:      pgtype.Int4{Int32: int32(status), Valid: true},
+			ResponseContentType: pgtype.Text{String: writer.Header().Get("Content-Type"), Valid: true},
+			ResponseBody:        writer.body.Bytes(),
+		})
+		if err != nil {
+			klog.Errorf("[request_id=%s] Failed to store idempotent response: %v", rid, err)
+		}
+	}
+}
+
+// replayIdempotentResponse répond à une requête dont la clé est déjà enregistrée
+func replayIdempotentResponse(c *gin.Context, queries *idempotencydb.Queries, customerID, key, hash string) {
+	rid := c.GetString(RequestIDKey)
+
+	record, err := queries.GetIdempotencyKey(c.Request.Context(), idempotencydb.GetIdempotencyKeyParams{
+		CustomerID:     customerID,
+		IdempotencyKey: key,
+	})
+	if err != nil {
+		// Clé libérée entre-temps : le client peut réessayer
+		klog.Warningf("[request_id=%s] Idempotency key released during replay: %v", rid, err)
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is being processed, retry later", "request_id": rid})
+		return
+	}
+
+	switch {
+	case record.RequestHash != hash:
+		klog.Warningf("[request_id=%s] Idempotency-Key of customer %s reused with a different request", rid, customerID)
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Idempotency-Key already used with a different request", "request_id": rid})
+	case !record.ResponseStatus.Valid:
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Request with this Idempotency-Key is being processed, retry later", "request_id": rid})
+	default:
+		klog.Infof("[request_id=%s] Replaying response of Idempotency-Key for customer %s", rid, customerID)
+		c.Header(IdempotentReplayedHeader, "true")
+		c.Data(int(record.ResponseStatus.Int32), record.ResponseContentType.String, record.ResponseBody)
+		c.Abort()
+	}
+}
+
+// PurgeIdempotencyKeys supprime périodiquement les clés expirées jusqu'à l'arrêt du serveur
+func PurgeIdempotencyKeys(ctx context.Context, queries *idempotencydb.Queries, ttl, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := queries.DeleteExpiredIdempotencyKeys(ctx, pgtype.Timestamptz{Time: time.Now().Add(-ttl), Valid: true})
+			if err != nil {
+				klog.Errorf("Failed to purge expired idempotency keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				klog.V(1).Infof("Purged %d expired idempotency keys", deleted)
+			}
+		}
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// requestHash identifie la requête : méthode, chemin avec query string et corps
+func requestHash(method, target string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(target))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -19,6 +19,24 @@ func GetCustomerIDOrAbort(c *gin.Context) (string, bool) {
 	return customerID, true
 }
 
+// IsAdmin indique si le token porte le rôle admin, sans interrompre la requête
+func IsAdmin(c *gin.Context) bool {
+	roles, ok := c.Get("roles")
+	if !ok {
+		return false
+	}
+	list, ok := roles.([]string)
+	if !ok {
+		return false
+	}
+	for _, role := range list {
+		if role == "api:sigma-admin" {
+			return true
+		}
+	}
+	return false
+}
+
 // IsAdminOrAbort vérifie que le rôle du token est "admin", sinon répond 403
 func IsAdminOrAbort(c *gin.Context) bool {
 	rid := c.GetString("request_id")